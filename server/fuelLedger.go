@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const fuelLedgerUsage = "uso: server fuel-ledger check|rebuild [carId]"
+
+func runFuelLedger(args []string) {
+	if len(args) < 1 || len(args) > 2 || (args[0] != "check" && args[0] != "rebuild") {
+		log.Fatal(fuelLedgerUsage)
+	}
+
+	store, err := newStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+	ledger := service.NewFuelLedgerService(store)
+	alerts := service.NewAlertService(store)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	var carIDs []primitive.ObjectID
+	if len(args) == 2 {
+		carID, err := primitive.ObjectIDFromHex(args[1])
+		if err != nil {
+			log.Fatal("ID inválido: ", args[1])
+		}
+		carIDs = append(carIDs, carID)
+	} else {
+		for _, active := range []bool{true, false} {
+			cars, err := store.Cars.List(ctx, repository.CarFilter{IsActive: active})
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, car := range cars {
+				carIDs = append(carIDs, car.ID)
+			}
+		}
+	}
+
+	run := ledger.Check
+	if args[0] == "rebuild" {
+		run = ledger.Rebuild
+	}
+
+	inconsistent := 0
+	for _, carID := range carIDs {
+		report, err := run(ctx, carID)
+		if err != nil {
+			log.Fatalf("carro %s: %v", carID.Hex(), err)
+		}
+		if report.Rewritten {
+			if err := alerts.Evaluate(ctx, carID); err != nil {
+				log.Printf("carro %s: erro ao avaliar alertas: %v", carID.Hex(), err)
+			}
+		}
+
+		status := "ok"
+		if !report.Consistent {
+			status = "divergente"
+			inconsistent++
+		}
+		if report.Rewritten {
+			status += ", reescrito"
+		}
+		fmt.Printf("%s  %-8s  gravado %.2f  recalculado %.2f  %s\n",
+			report.CarID.Hex(), report.Plate, report.StoredCurrentFuel, report.ExpectedCurrentFuel, status)
+		for _, divergence := range report.Divergences {
+			fmt.Printf("    %s  %-10s  %s\n", divergence.Date.Format(time.RFC3339), divergence.Type, divergence.Issue)
+		}
+		if len(report.Unmatched) > 0 {
+			fmt.Printf("    %d registro(s) sem evento correspondente\n", len(report.Unmatched))
+		}
+	}
+
+	fmt.Printf("%d carro(s) verificados, %d com divergência\n", len(carIDs), inconsistent)
+}
@@ -1,18 +1,89 @@
 package main
 
 import (
+	"context"
+	"log"
 	"net/http"
 	"os"
 	"time"
 
-	middleware "server/src/middlewares"
+	controller "server/src/controllers"
+	database "server/src/db"
+	model "server/src/models"
+	repository "server/src/repositories"
 	routes "server/src/routes"
+	service "server/src/services"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+func newStore() (*repository.Store, error) {
+	if os.Getenv("STORAGE") == "memory" {
+		log.Println("Usando armazenamento em memória; os dados serão perdidos ao encerrar o servidor")
+		store := repository.NewMemoryStore()
+		return store, seedMemoryAdmin(store)
+	}
+
+	client, err := database.DBInstance()
+	if err != nil {
+		return nil, err
+	}
+	db := database.OpenDatabase(client)
+	if err := checkPendingMigrations(db); err != nil {
+		return nil, err
+	}
+
+	return repository.NewMongoStore(db), nil
+}
+
+func seedMemoryAdmin(store *repository.Store) error {
+	email := os.Getenv("ADMIN_EMAIL")
+	password := os.Getenv("ADMIN_PASSWORD")
+	if email == "" || password == "" {
+		log.Println("Warning: ADMIN_EMAIL/ADMIN_PASSWORD não definidos; nenhum usuário inicial criado")
+		return nil
+	}
+
+	hashedPassword, err := controller.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return store.Users.Create(context.Background(), &model.User{
+		ID:       primitive.NewObjectID(),
+		Name:     "Administrador",
+		Email:    email,
+		Password: hashedPassword,
+		UserType: "ADMIN",
+		CNH:      "12345678900",
+		IsActive: true,
+	})
+}
+
 func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found. Falling back to environment variables.")
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			runMigrate(os.Args[2:])
+			return
+		case "fuel-ledger":
+			runFuelLedger(os.Args[2:])
+			return
+		}
+	}
+
+	store, err := newStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	router := gin.New()
 
 	router.MaxMultipartMemory = 100 << 20
@@ -38,17 +109,9 @@ func main() {
 		c.Next()
 	})
 
-	routes.AuthRoutes(router)
-
-	authProtected := router.Group("/")
-	authProtected.Use(middleware.Authenticate())
-
-	authProtected.Static("/uploads", "./uploads")
+	notifier := service.NewNotifier()
 
-	routes.UserRoutes(authProtected)
-	routes.CarRoutes(authProtected)
-	routes.CarEntryRoutes(authProtected)
-	routes.FormsRoutes(authProtected)
+	routes.RegisterRoutes(router, store, notifier)
 
 	router.Run(":" + port)
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	database "server/src/db"
+	migration "server/src/migrations"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const migrateUsage = "uso: server migrate up|down|status"
+
+func runMigrate(args []string) {
+	if len(args) != 1 || (args[0] != "up" && args[0] != "down" && args[0] != "status") {
+		log.Fatal(migrateUsage)
+	}
+
+	client, err := database.DBInstance()
+	if err != nil {
+		log.Fatal(err)
+	}
+	db := database.OpenDatabase(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	switch args[0] {
+	case "up":
+		applied, err := migration.Up(ctx, db)
+		for _, m := range applied {
+			fmt.Printf("aplicada  %03d  %s\n", m.Version, m.Description)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Nenhuma migração pendente")
+		}
+	case "down":
+		reverted, err := migration.Down(ctx, db)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if reverted == nil {
+			fmt.Println("Nenhuma migração aplicada")
+			return
+		}
+		fmt.Printf("revertida %03d  %s\n", reverted.Version, reverted.Description)
+	default:
+		status, err := migration.Status(ctx, db)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, m := range status {
+			appliedAt := "pendente"
+			if m.AppliedAt != nil {
+				appliedAt = m.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%03d  %-25s  %s\n", m.Version, appliedAt, m.Description)
+		}
+	}
+}
+
+// checkPendingMigrations impede o servidor de subir com um esquema
+// desatualizado: as rotas dependem dos índices e campos criados pelas
+// migrações.
+func checkPendingMigrations(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pending, err := migration.Pending(ctx, db)
+	if err != nil {
+		return fmt.Errorf("não foi possível verificar migrações: %v", err)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d migração(ões) pendente(s); execute \"server migrate up\" antes de iniciar o servidor", len(pending))
+	}
+	return nil
+}
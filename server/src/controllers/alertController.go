@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// evaluateFuelAlerts reavalia os alertas de combustível do carro após uma
+// alteração no saldo. Falhas são apenas registradas no log para não desfazer
+// a operação que já foi concluída.
+func evaluateFuelAlerts(ctx context.Context, alerts *service.AlertService, carID primitive.ObjectID) {
+	if err := alerts.Evaluate(ctx, carID); err != nil {
+		log.Println("Erro ao avaliar alertas do carro", carID.Hex(), err)
+	}
+}
+
+func GetAlerts(alerts *service.AlertService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		filter := repository.AlertFilter{Status: c.Query("status")}
+		if filter.Status != "" && filter.Status != model.AlertStatusOpen &&
+			filter.Status != model.AlertStatusAcknowledged && filter.Status != model.AlertStatusResolved {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Status inválido"})
+			return
+		}
+		if carID := c.Query("carId"); carID != "" {
+			objectID, err := primitive.ObjectIDFromHex(carID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			filter.CarID = &objectID
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := alerts.List(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar alertas"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func AcknowledgeAlert(alerts *service.AlertService) gin.HandlerFunc {
+	return alertTransitionHandler(alerts.Acknowledge)
+}
+
+func ResolveAlert(alerts *service.AlertService) gin.HandlerFunc {
+	return alertTransitionHandler(alerts.Resolve)
+}
+
+func alertTransitionHandler(transition func(context.Context, primitive.ObjectID, primitive.ObjectID) (*model.Alert, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, _, userId := helper.CheckAdminOrUidPermission(c, "")
+		if !ok {
+			return
+		}
+
+		alertID, err := primitive.ObjectIDFromHex(c.Param("alertId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+		userID, err := primitive.ObjectIDFromHex(userId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		alert, err := transition(ctx, alertID, userID)
+		if err != nil {
+			switch err {
+			case repository.ErrNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": "Alerta não encontrado"})
+			case service.ErrInvalidAlertTransition:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar alerta"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, alert)
+	}
+}
@@ -3,25 +3,34 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"log"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
+	"sync"
 	"time"
 
 	helper "server/src/helpers"
 	model "server/src/models"
-
-	database "server/src/db"
+	repository "server/src/repositories"
+	service "server/src/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/golang-jwt/jwt/v5"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var userCollection *mongo.Collection = database.OpenCollection(database.Client, "users")
-var validate = validator.New()
+var validate = newValidator()
+
+// newValidator registra as validações próprias do projeto, como a tag cnh.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterValidation("cnh", func(fl validator.FieldLevel) bool {
+		return helper.ValidCNH(fl.Field().String())
+	})
+	return v
+}
 
 func VerifyPassword(providedPassword string, storedHash string) error {
 	err := bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(providedPassword))
@@ -31,7 +40,36 @@ func VerifyPassword(providedPassword string, storedHash string) error {
 	return nil
 }
 
-func LoginUser() gin.HandlerFunc {
+// setTokenCookie grava o token no cookie HttpOnly usado pelo cliente web.
+func setTokenCookie(c *gin.Context, name, value string, expires time.Time) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   os.Getenv("DOMAIN"),
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   os.Getenv("ENVIROMENT") == "production",
+		SameSite: http.SameSiteNoneMode,
+	})
+}
+
+// dummyPasswordHash é comparado com a senha informada quando o email não
+// pertence a nenhum usuário ativo, para que o tempo de resposta não revele
+// quais contas existem. Usa o mesmo custo dos hashes gravados e só é gerado
+// no primeiro login.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, err := helper.HashPassword("senha-inexistente")
+	if err != nil {
+		log.Println("Erro ao gerar hash de comparação do login:", err)
+	}
+	return hash
+})
+
+// LoginUser autentica o usuário. Email inexistente e senha incorreta recebem
+// a mesma resposta, e tentativas em excesso da conta ou do IP são recusadas
+// com 429 até o fim da espera informada em Retry-After.
+func LoginUser(users repository.UserRepository, tokens *service.TokenService, guard *service.LoginGuardService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
 		defer cancel()
@@ -42,8 +80,6 @@ func LoginUser() gin.HandlerFunc {
 			KeepConnection bool   `json:"keepConnection"`
 		}{}
 
-		var foundUser model.User
-
 		if err := c.BindJSON(&request); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "erro ao ler dados"})
 			return
@@ -55,59 +91,65 @@ func LoginUser() gin.HandlerFunc {
 			return
 		}
 
-		err := userCollection.FindOne(ctx, bson.M{"email": request.Email, "isActive": true}).Decode(&foundUser)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "email e/ou senha incorretos"})
+		attempt := model.LoginAttempt{Email: request.Email, IP: c.ClientIP(), DeviceInfo: requestDeviceInfo(c)}
+		if err := guard.Check(ctx, &attempt); err != nil {
+			if throttled, ok := err.(*service.LoginThrottledError); ok {
+				retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+				c.Header("Retry-After", strconv.Itoa(retryAfter))
+				c.JSON(http.StatusTooManyRequests, gin.H{"error": "Muitas tentativas de login; aguarde antes de tentar novamente", "retryAfter": retryAfter})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao validar login"})
 			return
 		}
 
-		if err := VerifyPassword(request.Password, foundUser.Password); err != nil {
+		foundUser, err := users.FindActiveByEmail(ctx, request.Email)
+		if err != nil && err != repository.ErrNotFound {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao validar login"})
+			return
+		}
+
+		storedHash := dummyPasswordHash()
+		if foundUser != nil {
+			storedHash = foundUser.Password
+			attempt.UserID = &foundUser.ID
+		}
+		if err := VerifyPassword(request.Password, storedHash); err != nil || foundUser == nil {
+			if err := guard.RecordFailure(ctx, &attempt); err != nil {
+				log.Println("Erro ao registrar tentativa de login:", err)
+			}
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "email e/ou senha incorretos"})
 			return
 		}
 
-		var keepLoggedIn = false
-		if request.KeepConnection {
-			keepLoggedIn = true
+		if err := guard.RecordSuccess(ctx, &attempt); err != nil {
+			log.Println("Erro ao registrar tentativa de login:", err)
 		}
 
-		accessToken, refreshToken, _ := helper.GenerateTokens(foundUser.ID.Hex(), foundUser.Name, foundUser.UserType, keepLoggedIn)
-
-		http.SetCookie(c.Writer, &http.Cookie{
-			Name:     "accessToken",
-			Value:    accessToken,
-			Path:     "/",
-			Domain:   os.Getenv("DOMAIN"),
-			Expires:  time.Now().Add(24 * time.Hour),
-			HttpOnly: true,
-			Secure:   os.Getenv("ENVIROMENT") == "production",
-			SameSite: http.SameSiteNoneMode,
-		})
+		pair, err := tokens.Issue(ctx, foundUser, request.KeepConnection, requestDeviceInfo(c))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar token"})
+			return
+		}
 
-		if keepLoggedIn {
-			http.SetCookie(c.Writer, &http.Cookie{
-				Name:     "refreshToken",
-				Value:    refreshToken,
-				Path:     "/",
-				Domain:   os.Getenv("DOMAIN"),
-				Expires:  time.Now().Add(60 * 24 * time.Hour),
-				HttpOnly: true,
-				Secure:   os.Getenv("ENVIROMENT") == "production",
-				SameSite: http.SameSiteNoneMode,
-			})
+		setTokenCookie(c, "accessToken", pair.AccessToken, time.Now().Add(helper.AccessTokenDuration))
+		if pair.RefreshToken != "" {
+			setTokenCookie(c, "refreshToken", pair.RefreshToken, pair.RefreshExpiresAt)
 		}
 
 		foundUser.Password = ""
 
 		c.JSON(http.StatusOK, gin.H{
-			"accessToken":  accessToken,
-			"refreshToken": refreshToken,
+			"accessToken":  pair.AccessToken,
+			"refreshToken": pair.RefreshToken,
 			"user":         foundUser,
 		})
 	}
 }
 
-func RefreshToken() gin.HandlerFunc {
+// RefreshToken troca o refresh token por um novo par de tokens. O token
+// recebido deixa de valer; reapresentá-lo revoga todos os tokens da família.
+func RefreshToken(tokens *service.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		refreshToken := c.GetHeader("Token")
 		if refreshToken == "" {
@@ -118,76 +160,71 @@ func RefreshToken() gin.HandlerFunc {
 			}
 		}
 
-		token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
-			return []byte(os.Getenv("SECRET_KEY")), nil
-		})
-
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Token inválido"})
-			return
-		}
-
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar token"})
-			return
-		}
-
-		userId := claims["UserId"].(string)
-		name := claims["Name"].(string)
-		userType := claims["UserType"].(string)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
 
-		newAccessToken, _, err := helper.GenerateTokens(userId, name, userType, true)
+		pair, err := tokens.Rotate(ctx, refreshToken, c.ClientIP())
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar novo token"})
+			switch err {
+			case service.ErrInvalidRefreshToken:
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Token inválido"})
+			case service.ErrRefreshTokenReused:
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Token já utilizado; faça login novamente"})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar novo token"})
+			}
 			return
 		}
 
-		http.SetCookie(c.Writer, &http.Cookie{
-			Name:     "accessToken",
-			Value:    newAccessToken,
-			Path:     "/",
-			Domain:   os.Getenv("DOMAIN"),
-			Expires:  time.Now().Add(24 * time.Hour),
-			HttpOnly: true,
-			Secure:   os.Getenv("ENVIROMENT") == "production",
-			SameSite: http.SameSiteNoneMode,
-		})
+		setTokenCookie(c, "accessToken", pair.AccessToken, time.Now().Add(helper.AccessTokenDuration))
+		setTokenCookie(c, "refreshToken", pair.RefreshToken, pair.RefreshExpiresAt)
 
 		c.JSON(http.StatusOK, gin.H{
-			"accessToken": newAccessToken,
+			"accessToken":  pair.AccessToken,
+			"refreshToken": pair.RefreshToken,
 		})
 	}
 }
 
-func LogoutUser() gin.HandlerFunc {
+// LogoutUser limpa os cookies e encerra a sessão do access token ou do
+// refresh token enviado; com ?all=true, encerra todas as sessões do usuário.
+// Tokens que não pertencem a uma sessão ativa apenas limpam os cookies.
+func LogoutUser(tokens *service.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token, _ := c.Cookie("accessToken")
-		if token == "" {
+		var presented []string
+		for _, token := range []string{c.GetHeader("Authorization"), c.GetHeader("Token")} {
+			if token != "" {
+				presented = append(presented, token)
+			}
+		}
+		for _, name := range []string{"accessToken", "refreshToken"} {
+			if token, _ := c.Cookie(name); token != "" {
+				presented = append(presented, token)
+			}
+		}
+		if len(presented) == 0 {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Token não fornecido"})
 			return
 		}
 
-		http.SetCookie(c.Writer, &http.Cookie{
-			Name:     "accessToken",
-			Value:    "",
-			Path:     "/",
-			Domain:   os.Getenv("DOMAIN"),
-			Expires:  time.Now().Add(24 * time.Hour),
-			HttpOnly: true,
-			Secure:   os.Getenv("ENVIROMENT") == "production",
-			SameSite: http.SameSiteNoneMode,
-		})
-		http.SetCookie(c.Writer, &http.Cookie{
-			Name:     "refreshToken",
-			Value:    "",
-			Path:     "/",
-			Domain:   os.Getenv("DOMAIN"),
-			Expires:  time.Now().Add(60 * 24 * time.Hour),
-			HttpOnly: true,
-			Secure:   os.Getenv("ENVIROMENT") == "production",
-			SameSite: http.SameSiteNoneMode,
-		})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		everywhere := c.Query("all") == "true"
+		for _, token := range presented {
+			err := tokens.Logout(ctx, token, everywhere)
+			if err == service.ErrInactiveToken {
+				continue
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao encerrar sessões"})
+				return
+			}
+			break
+		}
+
+		setTokenCookie(c, "accessToken", "", time.Now().Add(helper.AccessTokenDuration))
+		setTokenCookie(c, "refreshToken", "", time.Now().Add(helper.RefreshTokenDuration))
 
 		c.JSON(http.StatusOK, gin.H{"message": "Logout realizado com sucesso"})
 	}
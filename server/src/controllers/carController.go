@@ -3,21 +3,17 @@ package controllers
 import (
 	"context"
 	"net/http"
-	database "server/src/db"
 	helper "server/src/helpers"
 	model "server/src/models"
+	repository "server/src/repositories"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var carCollection *mongo.Collection = database.OpenCollection(database.Client, "cars")
-
-func GetCar() gin.HandlerFunc {
+func GetCar(cars repository.CarRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -26,8 +22,6 @@ func GetCar() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		var car model.Car
-
 		carID := c.Param("carId")
 		objectID, err := primitive.ObjectIDFromHex(carID)
 		if err != nil {
@@ -35,9 +29,9 @@ func GetCar() gin.HandlerFunc {
 			return
 		}
 
-		err = carCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&car)
+		car, err := cars.FindByID(ctx, objectID)
 		if err != nil {
-			if err == mongo.ErrNoDocuments {
+			if err == repository.ErrNotFound {
 				c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
 			} else {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar carro"})
@@ -49,53 +43,27 @@ func GetCar() gin.HandlerFunc {
 	}
 }
 
-func GetCars() gin.HandlerFunc {
+func GetCars(cars repository.CarRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		search := c.Query("search")
-		isActive := c.Query("active")
-
-		searchFilter := bson.M{}
-		if search != "" {
-			searchFilter["$or"] = []bson.M{
-				{"plate": bson.M{"$regex": search, "$options": "i"}},
-				{"brand": bson.M{"$regex": search, "$options": "i"}},
-				{"model": bson.M{"$regex": search, "$options": "i"}},
-			}
+		filter := repository.CarFilter{
+			Search:   c.Query("search"),
+			IsActive: c.Query("active") != "false",
 		}
 
-		active := true
-		if isActive == "false" {
-			active = false
-		}
-		searchFilter["isActive"] = active
-
-		var cars []model.Car
-
-		cursor, err := carCollection.Find(ctx, searchFilter)
+		result, err := cars.List(ctx, filter)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar carros"})
 			return
 		}
-		defer cursor.Close(ctx)
 
-		for cursor.Next(ctx) {
-			var car model.Car
-			cursor.Decode(&car)
-			cars = append(cars, car)
-		}
-		if err = cursor.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar carros"})
-			return
-		}
-
-		c.JSON(http.StatusOK, cars)
+		c.JSON(http.StatusOK, result)
 	}
 }
 
-func CreateCar() gin.HandlerFunc {
+func CreateCar(cars repository.CarRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -118,8 +86,7 @@ func CreateCar() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		_, err := carCollection.InsertOne(ctx, car)
-		if err != nil {
+		if err := cars.Create(ctx, &car); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar carro"})
 			return
 		}
@@ -128,7 +95,7 @@ func CreateCar() gin.HandlerFunc {
 	}
 }
 
-func DeleteCar() gin.HandlerFunc {
+func DeleteCar(cars repository.CarRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -144,13 +111,13 @@ func DeleteCar() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		result, err := carCollection.DeleteOne(ctx, bson.M{"_id": objectID})
+		err = cars.Delete(ctx, objectID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao desativar carro"})
-			return
-		}
-		if result.DeletedCount == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao desativar carro"})
+			}
 			return
 		}
 
@@ -158,7 +125,7 @@ func DeleteCar() gin.HandlerFunc {
 	}
 }
 
-func UpdateCar() gin.HandlerFunc {
+func UpdateCar(cars repository.CarRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -171,23 +138,25 @@ func UpdateCar() gin.HandlerFunc {
 			return
 		}
 
-		var updateData bson.M
+		var updateData map[string]interface{}
 		if err := c.BindJSON(&updateData); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
 			return
 		}
+		if value, ok := updateData["requiredLicense"]; ok {
+			license, isString := value.(string)
+			if !isString || (license != "" && (len(license) != 1 || !strings.Contains("ABCDE", license))) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Categoria de CNH inválida"})
+				return
+			}
+		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		update := bson.M{"$set": updateData}
-		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
-
-		var updatedDocument model.Car
-
-		err = carCollection.FindOneAndUpdate(ctx, bson.M{"_id": objectID}, update, opts).Decode(&updatedDocument)
+		updatedDocument, err := cars.Update(ctx, objectID, updateData)
 		if err != nil {
-			if err == mongo.ErrNoDocuments {
+			if err == repository.ErrNotFound {
 				c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
 			} else {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar carro"})
@@ -199,7 +168,7 @@ func UpdateCar() gin.HandlerFunc {
 	}
 }
 
-func DisableCar() gin.HandlerFunc {
+func DisableCar(cars repository.CarRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -215,13 +184,13 @@ func DisableCar() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		result, err := carCollection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"isActive": false}})
+		err = cars.SetActive(ctx, objectID, false)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao desativar carro"})
-			return
-		}
-		if result.MatchedCount == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao desativar carro"})
+			}
 			return
 		}
 
@@ -229,7 +198,7 @@ func DisableCar() gin.HandlerFunc {
 	}
 }
 
-func EnableCar() gin.HandlerFunc {
+func EnableCar(cars repository.CarRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -245,13 +214,13 @@ func EnableCar() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		result, err := carCollection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"isActive": true}})
+		err = cars.SetActive(ctx, objectID, true)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao ativar carro"})
-			return
-		}
-		if result.MatchedCount == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao ativar carro"})
+			}
 			return
 		}
 
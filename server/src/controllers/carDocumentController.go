@@ -0,0 +1,269 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func GetCarDocuments(documents repository.CarDocumentRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := repository.CarDocumentFilter{}
+		if docType := c.Query("type"); docType != "" {
+			switch docType {
+			case model.CarDocumentLicensing, model.CarDocumentMandatoryInsurance, model.CarDocumentInsurance, model.CarDocumentInspection:
+				filter.Type = docType
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Tipo de documento inválido"})
+				return
+			}
+		}
+		if carID := c.Query("carId"); carID != "" {
+			objectID, err := primitive.ObjectIDFromHex(carID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			filter.CarID = &objectID
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := documents.List(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar documentos"})
+			return
+		}
+		if result == nil {
+			result = []model.CarDocument{}
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func GetCarDocument(documents repository.CarDocumentRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		objectID, err := primitive.ObjectIDFromHex(c.Param("documentId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		document, err := documents.FindByID(ctx, objectID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Documento não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar documento"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, document)
+	}
+}
+
+// GetExpiringCarDocuments lista os documentos vigentes que vencem nos próximos
+// ?days= dias (padrão em DOCUMENT_EXPIRY_WARNING_DAYS), incluindo os vencidos.
+func GetExpiringCarDocuments(documents *service.CarDocumentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		days := service.DocumentExpiryDays()
+		if value := c.Query("days"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Prazo inválido"})
+				return
+			}
+			days = parsed
+		}
+
+		var carID *primitive.ObjectID
+		if id := c.Query("carId"); id != "" {
+			objectID, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			carID = &objectID
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := documents.Expiring(ctx, days, carID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar documentos a vencer"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"days": days, "documents": result})
+	}
+}
+
+func CreateCarDocument(documents *service.CarDocumentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, _, userId := helper.CheckAdminOrUidPermission(c, "")
+		if !ok {
+			return
+		}
+		createdBy, err := primitive.ObjectIDFromHex(userId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		var document model.CarDocument
+		if err := c.ShouldBindJSON(&document); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+		if err := validate.Struct(document); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		document.CreatedBy = createdBy
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := documents.Create(ctx, &document); err != nil {
+			if err == service.ErrDocumentCarNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao cadastrar documento"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusCreated, document)
+	}
+}
+
+func UpdateCarDocument(documents *service.CarDocumentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		objectID, err := primitive.ObjectIDFromHex(c.Param("documentId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		var document model.CarDocument
+		if err := c.ShouldBindJSON(&document); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+
+		// O carro do documento não pode ser trocado; CarID é ignorado.
+		if err := validate.StructExcept(document, "CarID"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := documents.Update(ctx, objectID, &document); err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Documento não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar documento"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, document)
+	}
+}
+
+func DeleteCarDocument(documents repository.CarDocumentRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		documentID := c.Param("documentId")
+		objectID, err := primitive.ObjectIDFromHex(documentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := documents.Delete(ctx, objectID); err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Documento não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar documento"})
+			}
+			return
+		}
+
+		if err := os.RemoveAll(filepath.Join(carDocumentUploadsPath, documentID)); err != nil {
+			log.Println("Erro ao deletar arquivos do documento", documentID, err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Documento deletado com sucesso", "id": documentID})
+	}
+}
+
+func UploadCarDocumentFiles(documents repository.CarDocumentRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		documentID := c.Param("documentId")
+		objectID, err := primitive.ObjectIDFromHex(documentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if _, err := documents.FindByID(ctx, objectID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Documento não encontrado"})
+			return
+		}
+
+		uploadedPaths, err := uploadImages(c, carDocumentUploadsPath, documentID, "", 5)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := documents.AddFiles(ctx, objectID, uploadedPaths, time.Now()); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao atualizar documento com os arquivos"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Arquivos do documento enviados com sucesso",
+			"files":   uploadedPaths,
+		})
+	}
+}
@@ -2,25 +2,24 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
 	"os"
-	"path/filepath"
-	database "server/src/db"
 	helper "server/src/helpers"
 	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	ua "github.com/mileusna/useragent"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var fuelCollection *mongo.Collection = database.OpenCollection(database.Client, "fuels")
-var carEntryCollection *mongo.Collection = database.OpenCollection(database.Client, "carEntries")
-
 func deviceType(ua ua.UserAgent) string {
 	if ua.Mobile {
 		return "Mobile"
@@ -37,7 +36,21 @@ func deviceType(ua ua.UserAgent) string {
 	return "Unknown"
 }
 
-func StartCarEntry() gin.HandlerFunc {
+// requestDeviceInfo identifica o dispositivo da requisição pelo User-Agent.
+func requestDeviceInfo(c *gin.Context) model.DeviceInfo {
+	userAgent := c.GetHeader("User-Agent")
+	uaParsed := ua.Parse(userAgent)
+
+	return model.DeviceInfo{
+		UserAgent:  userAgent,
+		IPAddress:  c.ClientIP(),
+		OS:         uaParsed.OS,
+		DeviceType: deviceType(uaParsed),
+		Browser:    uaParsed.Name,
+	}
+}
+
+func StartCarEntry(carEntries repository.CarEntryRepository, availability *service.AvailabilityService, licenses *service.DriverLicenseService, reservations *service.ReservationService, odometer *service.OdometerService, checklists *service.ChecklistService, damages *service.DamageService, costCenters *service.CostCenterService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var carEntry model.CarEntry
 		if err := c.ShouldBindJSON(&carEntry); err != nil {
@@ -45,17 +58,8 @@ func StartCarEntry() gin.HandlerFunc {
 			return
 		}
 
-		userAgent := c.GetHeader("User-Agent")
-		uaParsed := ua.Parse(userAgent)
-
 		carEntry.ID = primitive.NewObjectID()
-		carEntry.DeviceInfo = model.DeviceInfo{
-			UserAgent:  userAgent,
-			IPAddress:  c.ClientIP(),
-			OS:         uaParsed.OS,
-			DeviceType: deviceType(uaParsed),
-			Browser:    uaParsed.Name,
-		}
+		carEntry.DeviceInfo = requestDeviceInfo(c)
 		carEntry.StartedAt = time.Now()
 
 		validationErrors := validate.Struct(carEntry)
@@ -67,26 +71,137 @@ func StartCarEntry() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		err := carEntryCollection.FindOne(ctx, bson.M{
-			"carID":    carEntry.CarID,
-			"checkOut": nil,
-		}).Decode(&carEntry)
-		if err == nil {
-			c.JSON(http.StatusConflict, gin.H{"error": "Já existe uma entrada de carro ativa para este carro"})
+		if !checkCarAvailability(ctx, c, availability, &carEntry) {
+			return
+		}
+
+		if err := licenses.CheckDriver(ctx, carEntry.UserID, carEntry.CarID); err != nil {
+			var licenseErr *service.LicenseError
+			if errors.As(err, &licenseErr) {
+				c.JSON(http.StatusConflict, gin.H{"error": licenseErr.Message})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao validar CNH do motorista"})
+			}
+			return
+		}
+
+		reservation, err := reservations.ForCheckIn(ctx, carEntry.CarID, carEntry.UserID)
+		if err != nil {
+			var reservationErr *service.ReservationError
+			if errors.As(err, &reservationErr) {
+				c.JSON(http.StatusConflict, gin.H{"error": reservationErr.Message})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar reservas do carro"})
+			}
 			return
 		}
+		carEntry.ReservationID = nil
+		if reservation != nil {
+			carEntry.ReservationID = &reservation.ID
+		}
 
-		_, err = carEntryCollection.InsertOne(ctx, carEntry)
+		gap, err := odometer.ValidateCheckIn(ctx, carEntry.CarID, carEntry.CheckIn.ActualKM)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar entrada de carro"})
+			var odometerErr *service.OdometerError
+			if errors.As(err, &odometerErr) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": odometerErr.Message})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao validar quilometragem"})
+			}
+			return
+		}
+		carEntry.OdometerGapKM = gap
+
+		if err := checklists.Prepare(ctx, model.ChecklistStageCheckIn, carEntry.CarID, carEntry.CheckIn.Checklist); err != nil {
+			respondChecklistError(c, err, "Erro ao validar checklist")
 			return
 		}
 
+		if err := costCenters.PrepareTrip(ctx, &carEntry.CheckIn); err != nil {
+			respondCostCenterError(c, err, "Erro ao validar dados da viagem")
+			return
+		}
+
+		singleCarPerDriver := os.Getenv("SINGLE_CAR_PER_DRIVER") != "false"
+
+		err = carEntries.Create(ctx, &carEntry, singleCarPerDriver)
+		if err != nil {
+			var conflict *repository.OpenEntryConflictError
+			if errors.As(err, &conflict) {
+				c.JSON(http.StatusConflict, gin.H{"error": conflict.Error(), "entryId": conflict.EntryID})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar entrada de carro"})
+			}
+			return
+		}
+
+		if carEntry.ReservationID != nil {
+			if err := reservations.LinkCheckIn(ctx, *carEntry.ReservationID, carEntry.ID); err != nil {
+				log.Println("Erro ao vincular reserva", carEntry.ReservationID.Hex(), "à entrada", carEntry.ID.Hex(), err)
+			}
+		}
+		reportChecklistDamages(ctx, damages, &carEntry, model.ChecklistStageCheckIn, carEntry.CheckIn.Checklist)
+
 		c.JSON(http.StatusCreated, gin.H{"id": carEntry.ID})
 
 	}
 }
-func EndCarEntry() gin.HandlerFunc {
+
+// checkCarAvailability recusa o check-in em carro bloqueado, a menos que um
+// administrador envie availabilityOverride com a justificativa, que fica
+// registrada na entrada junto com os motivos do bloqueio.
+func checkCarAvailability(ctx context.Context, c *gin.Context, availability *service.AvailabilityService, carEntry *model.CarEntry) bool {
+	current, err := availability.Check(ctx, carEntry.CarID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar disponibilidade do carro"})
+		}
+		return false
+	}
+
+	if !service.Blocked(current) {
+		carEntry.AvailabilityOverride = nil
+		return true
+	}
+
+	override := carEntry.AvailabilityOverride
+	if override == nil {
+		reasons := make([]string, len(current.Reasons))
+		for i, reason := range current.Reasons {
+			reasons[i] = reason.Message
+		}
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Carro bloqueado para check-in: " + strings.Join(reasons, "; "),
+			"status":  current.Status,
+			"reasons": current.Reasons,
+		})
+		return false
+	}
+
+	ok, userType, userId := helper.CurrentUser(c)
+	if !ok {
+		return false
+	}
+	if userType != "ADMIN" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Apenas administradores podem liberar o check-in de um carro bloqueado"})
+		return false
+	}
+	by, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return false
+	}
+
+	override.By = by
+	override.At = carEntry.StartedAt
+	override.Blocked = current.Reasons
+	log.Println("Check-in liberado em carro bloqueado", carEntry.CarID.Hex(), "por", userId+":", override.Reason)
+	return true
+}
+
+func EndCarEntry(carEntries repository.CarEntryRepository, cars repository.CarRepository, fuels repository.FuelRepository, transactor repository.Transactor, alerts *service.AlertService, checklists *service.ChecklistService, damages *service.DamageService, reservations *service.ReservationService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		type EndCarEntryInput struct {
 			CarID    primitive.ObjectID `json:"carID" binding:"required"`
@@ -103,14 +218,7 @@ func EndCarEntry() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		filter := bson.M{
-			"carID":    input.CarID,
-			"userID":   input.UserID,
-			"checkOut": nil,
-		}
-		opts := options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}})
-		var carEntry model.CarEntry
-		err := carEntryCollection.FindOne(ctx, filter, opts).Decode(&carEntry)
+		carEntry, err := carEntries.FindOpenByCarAndUser(ctx, input.CarID, input.UserID)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Nenhum check-in pendente encontrado"})
 			return
@@ -122,53 +230,66 @@ func EndCarEntry() gin.HandlerFunc {
 			return
 		}
 
-		update := bson.M{
-			"$set": bson.M{
-				"checkOut": input.CheckOut,
-				"endedAt":  time.Now(),
-				"kmDriven": kmDriven,
-			},
+		if err := checklists.Prepare(ctx, model.ChecklistStageCheckOut, input.CarID, input.CheckOut.Checklist); err != nil {
+			respondChecklistError(c, err, "Erro ao validar checklist")
+			return
 		}
 
-		var car model.Car
-		err = carCollection.FindOne(ctx, bson.M{"_id": input.CarID}).Decode(&car)
+		car, err := cars.FindByID(ctx, input.CarID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar dados do carro"})
 			return
 		}
 
-		var lastFuel model.Fuel
-		fuelFilter := bson.M{"carID": input.CarID}
-		fuelOpts := options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}})
-		err = fuelCollection.FindOne(ctx, fuelFilter, fuelOpts).Decode(&lastFuel)
-		previousFuel := 0.0
-		if err == nil {
-			previousFuel = lastFuel.NewFuel
-		}
+		err = transactor.WithTransaction(ctx, func(ctx context.Context) error {
+			now := time.Now()
 
-		fuelSpent := kmDriven / float64(car.Consumption)
-		newFuel := previousFuel - fuelSpent
+			_, err := carEntries.Close(ctx, carEntry.ID, input.CheckOut, kmDriven, now)
+			if err != nil {
+				return err
+			}
+			if err := fuels.LockCar(ctx, input.CarID); err != nil {
+				return err
+			}
 
-		fuelRecord := model.Fuel{
-			ID:          primitive.NewObjectID(),
-			CarID:       input.CarID,
-			PreviusFuel: previousFuel,
-			NewFuel:     newFuel,
-			KMDriven:    kmDriven,
-			CreatedAt:   time.Now(),
-		}
+			previousFuel := 0.0
+			lastFuel, err := fuels.FindLatestByCar(ctx, input.CarID)
+			if err == nil {
+				previousFuel = lastFuel.NewFuel
+			} else if !errors.Is(err, repository.ErrNotFound) {
+				return err
+			}
 
-		_, err = fuelCollection.InsertOne(ctx, fuelRecord)
+			fuelSpent := kmDriven / float64(car.Consumption)
+
+			fuelRecord := model.Fuel{
+				ID:           primitive.NewObjectID(),
+				CarID:        input.CarID,
+				Type:         model.FuelTypeCheckOut,
+				CarEntryID:   &carEntry.ID,
+				PreviousFuel: previousFuel,
+				NewFuel:      previousFuel - fuelSpent,
+				KMDriven:     kmDriven,
+				CreatedAt:    now,
+			}
+			return fuels.Create(ctx, &fuelRecord)
+		})
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar registro de fuel"})
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusConflict, gin.H{"error": "Check-out já realizado para esta entrada"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar check-out"})
+			}
 			return
 		}
 
-		err = carEntryCollection.FindOneAndUpdate(ctx, filter, update).Decode(&carEntry)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar check-out"})
-			return
+		if carEntry.ReservationID != nil {
+			if err := reservations.Complete(ctx, *carEntry.ReservationID); err != nil {
+				log.Println("Erro ao concluir reserva", carEntry.ReservationID.Hex(), err)
+			}
 		}
+		evaluateFuelAlerts(ctx, alerts, input.CarID)
+		reportChecklistDamages(ctx, damages, carEntry, model.ChecklistStageCheckOut, input.CheckOut.Checklist)
 
 		c.JSON(http.StatusOK, gin.H{
 			"id": carEntry.ID,
@@ -177,11 +298,26 @@ func EndCarEntry() gin.HandlerFunc {
 	}
 }
 
-func FuelEntry() gin.HandlerFunc {
+// fuelCostTolerance é a diferença máxima aceita entre o valor total informado
+// e litros × preço por litro, para acomodar o arredondamento da bomba.
+const fuelCostTolerance = 0.1
+
+// errFuelOverCapacity interrompe a transação do abastecimento que não cabe
+// no tanque.
+var errFuelOverCapacity = errors.New("abastecimento excede a capacidade do tanque")
+
+func FuelEntry(fuels repository.FuelRepository, cars repository.CarRepository, transactor repository.Transactor, alerts *service.AlertService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		type FuelEntryInput struct {
-			CarID     primitive.ObjectID `json:"carId" binding:"required"`
-			FuelAdded float64            `json:"fuelAdded" binding:"required"`
+			CarID         primitive.ObjectID  `json:"carId" binding:"required"`
+			Liters        float64             `json:"liters" binding:"required,gt=0"`
+			PricePerLiter float64             `json:"pricePerLiter" binding:"required,gt=0"`
+			TotalCost     float64             `json:"totalCost" binding:"gte=0"`
+			FuelKind      string              `json:"fuelKind" binding:"required,oneof=gasolina etanol diesel gnv"`
+			Station       string              `json:"station" binding:"required"`
+			Odometer      float64             `json:"odometer" binding:"required,gt=0"`
+			FullTank      bool                `json:"fullTank"`
+			DriverID      *primitive.ObjectID `json:"driverId"`
 		}
 
 		var input FuelEntryInput
@@ -190,35 +326,97 @@ func FuelEntry() gin.HandlerFunc {
 			return
 		}
 
+		ok, userType, userId := helper.CurrentUser(c)
+		if !ok {
+			return
+		}
+		if input.DriverID == nil {
+			driverID, err := primitive.ObjectIDFromHex(userId)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			input.DriverID = &driverID
+		} else if userType != "ADMIN" && input.DriverID.Hex() != userId {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Você não tem permissão para registrar abastecimento de outro motorista"})
+			return
+		}
+
+		expectedCost := math.Round(input.Liters*input.PricePerLiter*100) / 100
+		if input.TotalCost == 0 {
+			input.TotalCost = expectedCost
+		} else if math.Abs(input.TotalCost-expectedCost) > fuelCostTolerance {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Valor total não confere com litros × preço por litro"})
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		var lastFuel model.Fuel
-		fuelFilter := bson.M{"carID": input.CarID}
-		fuelOpts := options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}})
-		err := fuelCollection.FindOne(ctx, fuelFilter, fuelOpts).Decode(&lastFuel)
-		previousFuel := 0.0
-		if err == nil {
-			previousFuel = lastFuel.NewFuel
+		car, err := cars.FindByID(ctx, input.CarID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar carro"})
+			}
+			return
 		}
 
-		newFuel := previousFuel + input.FuelAdded
+		var fuelRecord model.Fuel
+		previousFuel := 0.0
+		err = transactor.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := fuels.LockCar(ctx, input.CarID); err != nil {
+				return err
+			}
 
-		fuelRecord := model.Fuel{
-			ID:          primitive.NewObjectID(),
-			CarID:       input.CarID,
-			PreviusFuel: previousFuel,
-			NewFuel:     newFuel,
-			KMDriven:    0,
-			CreatedAt:   time.Now(),
-		}
+			previousFuel = 0.0
+			lastFuel, err := fuels.FindLatestByCar(ctx, input.CarID)
+			if err == nil {
+				previousFuel = lastFuel.NewFuel
+			} else if !errors.Is(err, repository.ErrNotFound) {
+				return err
+			}
+			if math.Max(previousFuel, 0)+input.Liters > float64(car.Capacity) {
+				return errFuelOverCapacity
+			}
 
-		_, err = fuelCollection.InsertOne(ctx, fuelRecord)
+			fuelRecord = model.Fuel{
+				ID:            primitive.NewObjectID(),
+				CarID:         input.CarID,
+				Type:          model.FuelTypeRefuel,
+				PreviousFuel:  previousFuel,
+				NewFuel:       previousFuel + input.Liters,
+				KMDriven:      0,
+				CreatedAt:     time.Now(),
+				Liters:        input.Liters,
+				PricePerLiter: input.PricePerLiter,
+				TotalCost:     input.TotalCost,
+				FuelKind:      input.FuelKind,
+				Station:       input.Station,
+				Odometer:      input.Odometer,
+				FullTank:      input.FullTank,
+				DriverID:      input.DriverID,
+			}
+			return fuels.Create(ctx, &fuelRecord)
+		})
+		if err == errFuelOverCapacity {
+			if err := alerts.RaiseOverCapacity(ctx, car, previousFuel, input.Liters); err != nil {
+				log.Println("Erro ao registrar alerta de capacidade do carro", car.ID.Hex(), err)
+			}
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Abastecimento excede a capacidade do tanque (%d L); cabem no máximo %.1f L",
+					car.Capacity, math.Max(float64(car.Capacity)-math.Max(previousFuel, 0), 0)),
+			})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar abastecimento"})
 			return
 		}
 
+		evaluateFuelAlerts(ctx, alerts, input.CarID)
+
 		c.JSON(http.StatusCreated, gin.H{
 			"message":    "Abastecimento registrado com sucesso",
 			"fuelRecord": fuelRecord,
@@ -226,7 +424,7 @@ func FuelEntry() gin.HandlerFunc {
 	}
 }
 
-func GetCarEntry() gin.HandlerFunc {
+func GetCarEntry(carEntries repository.CarEntryRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -242,74 +440,77 @@ func GetCarEntry() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		pipeline := mongo.Pipeline{
-			{{Key: "$match", Value: bson.D{{Key: "_id", Value: objectID}}}},
-			{{Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "users"},
-				{Key: "localField", Value: "userID"},
-				{Key: "foreignField", Value: "_id"},
-				{Key: "as", Value: "user"},
-			}}},
-			{{Key: "$unwind", Value: bson.D{
-				{Key: "path", Value: "$user"},
-				{Key: "preserveNullAndEmptyArrays", Value: true},
-			}}},
-			{{Key: "$project", Value: bson.D{
-				{Key: "user.password", Value: 0},
-			}}},
-		}
-
-		cursor, err := carEntryCollection.Aggregate(ctx, pipeline)
+		carEntry, err := carEntries.FindByIDWithUser(ctx, objectID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro na agregação"})
-			return
-		}
-
-		var results []model.CarEntry
-		if err := cursor.All(ctx, &results); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao ler os resultados"})
-			return
-		}
-
-		if len(results) == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Entrada de carro não encontrada"})
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Entrada de carro não encontrada"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro na agregação"})
+			}
 			return
 		}
 
-		c.JSON(http.StatusOK, results[0])
+		c.JSON(http.StatusOK, carEntry)
 	}
 }
 
-func GetCarEntrys() gin.HandlerFunc {
+// GetCarEntrys lista as entradas pelo check-in mais recente. Aceita carId,
+// userId, costCenter e o período from/to (AAAA-MM-DD) do check-in.
+func GetCarEntrys(carEntries repository.CarEntryRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
 		}
 
+		filter := repository.CarEntryFilter{CostCenter: service.NormalizeCostCenterCode(c.Query("costCenter"))}
+		if carID := c.Query("carId"); carID != "" {
+			objectID, err := primitive.ObjectIDFromHex(carID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			filter.CarID = &objectID
+		}
+		if userID := c.Query("userId"); userID != "" {
+			objectID, err := primitive.ObjectIDFromHex(userID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			filter.UserID = &objectID
+		}
+		if value := c.Query("from"); value != "" {
+			from, err := time.ParseInLocation(reportDateLayout, value, time.Local)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Data inicial inválida"})
+				return
+			}
+			filter.From = &from
+		}
+		if value := c.Query("to"); value != "" {
+			to, err := time.ParseInLocation(reportDateLayout, value, time.Local)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Data final inválida"})
+				return
+			}
+			to = to.AddDate(0, 0, 1)
+			filter.To = &to
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		var carEntries []model.CarEntry
-
-		opts := options.Find().SetSort(bson.D{{Key: "startedAt", Value: -1}})
-		cursor, err := carEntryCollection.Find(ctx, bson.M{}, opts)
+		result, err := carEntries.List(ctx, filter)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar entradas de carro"})
 			return
 		}
-		defer cursor.Close(ctx)
-
-		for cursor.Next(ctx) {
-			var carEntry model.CarEntry
-			cursor.Decode(&carEntry)
-			carEntries = append(carEntries, carEntry)
-		}
 
-		c.JSON(http.StatusOK, carEntries)
+		c.JSON(http.StatusOK, result)
 	}
 }
 
-func DeleteCarEntry() gin.HandlerFunc {
+func DeleteCarEntry(carEntries repository.CarEntryRepository, cars repository.CarRepository, fuels repository.FuelRepository, transactor repository.Transactor, alerts *service.AlertService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -325,81 +526,82 @@ func DeleteCarEntry() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		var carEntry model.CarEntry
-		err = carEntryCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&carEntry)
+		carEntry, err := carEntries.FindByID(ctx, objectID)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Entrada de carro não encontrada"})
 			return
 		}
 
-		if carEntry.KMDriven == nil {
-			result, err := carEntryCollection.DeleteOne(ctx, bson.M{"_id": objectID})
+		var car *model.Car
+		if carEntry.KMDriven != nil {
+			car, err = cars.FindByID(ctx, carEntry.CarID)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar entrada de carro"})
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar dados do carro"})
 				return
 			}
-			if result.DeletedCount == 0 {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Entrada de carro não encontrada"})
-				return
-			}
-
-			c.JSON(http.StatusOK, gin.H{
-				"message": "Entrada de carro deletada com ajuste de fuel",
-				"id":      entryID,
-			})
-			return
 		}
 
-		kmDriven := *carEntry.KMDriven
-
-		var car model.Car
-		err = carCollection.FindOne(ctx, bson.M{"_id": carEntry.CarID}).Decode(&car)
+		trashPath, err := trashEntryImages(entryID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar dados do carro"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar imagens"})
 			return
 		}
 
-		fuelSpent := kmDriven / float64(car.Consumption)
-
-		var lastFuel model.Fuel
-		fuelFilter := bson.M{"carID": carEntry.CarID}
-		fuelOpts := options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}})
-		err = fuelCollection.FindOne(ctx, fuelFilter, fuelOpts).Decode(&lastFuel)
-		previousFuel := 0.0
-		if err == nil {
-			previousFuel = lastFuel.NewFuel
-		}
-
-		newFuel := previousFuel + fuelSpent
-
-		fuelRecord := model.Fuel{
-			ID:          primitive.NewObjectID(),
-			CarID:       carEntry.CarID,
-			PreviusFuel: previousFuel,
-			NewFuel:     newFuel,
-			KMDriven:    0,
-			CreatedAt:   time.Now(),
-		}
+		var fuelRecord *model.Fuel
+		err = transactor.WithTransaction(ctx, func(ctx context.Context) error {
+			fuelRecord = nil
+
+			if carEntry.KMDriven != nil {
+				if err := fuels.LockCar(ctx, carEntry.CarID); err != nil {
+					return err
+				}
+				fuelSpent := *carEntry.KMDriven / float64(car.Consumption)
+
+				previousFuel := 0.0
+				lastFuel, err := fuels.FindLatestByCar(ctx, carEntry.CarID)
+				if err == nil {
+					previousFuel = lastFuel.NewFuel
+				} else if !errors.Is(err, repository.ErrNotFound) {
+					return err
+				}
+
+				fuelRecord = &model.Fuel{
+					ID:           primitive.NewObjectID(),
+					CarID:        carEntry.CarID,
+					Type:         model.FuelTypeAdjustment,
+					CarEntryID:   &carEntry.ID,
+					PreviousFuel: previousFuel,
+					NewFuel:      previousFuel + fuelSpent,
+					KMDriven:     0,
+					CreatedAt:    time.Now(),
+				}
+				if err := fuels.Create(ctx, fuelRecord); err != nil {
+					return err
+				}
+			}
 
-		_, err = fuelCollection.InsertOne(ctx, fuelRecord)
+			return carEntries.Delete(ctx, objectID)
+		})
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar registro de fuel para ajuste"})
-			return
-		}
+			if restoreErr := restoreEntryImages(entryID, trashPath); restoreErr != nil {
+				log.Println("Erro ao restaurar imagens da entrada", entryID, restoreErr)
+			}
 
-		result, err := carEntryCollection.DeleteOne(ctx, bson.M{"_id": objectID})
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar entrada de carro"})
-			return
-		}
-		if result.DeletedCount == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Entrada de carro não encontrada"})
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Entrada de carro não encontrada"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar entrada de carro"})
+			}
 			return
 		}
 
-		uploadPath := filepath.Join("uploads", carEntry.ID.Hex())
-		if err := os.RemoveAll(uploadPath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar imagens"})
+		if trashPath != "" {
+			if err := os.RemoveAll(trashPath); err != nil {
+				log.Println("Erro ao deletar imagens da entrada", entryID, err)
+			}
+		}
+		if fuelRecord != nil {
+			evaluateFuelAlerts(ctx, alerts, carEntry.CarID)
 		}
 
 		c.JSON(http.StatusOK, gin.H{
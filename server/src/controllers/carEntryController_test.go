@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errForced = errors.New("falha forçada")
+
+// failingFuels falha ao gravar qualquer registro de combustível.
+type failingFuels struct {
+	repository.FuelRepository
+}
+
+func (failingFuels) Create(ctx context.Context, fuel *model.Fuel) error {
+	return errForced
+}
+
+// failingCarEntries falha ao remover entradas.
+type failingCarEntries struct {
+	repository.CarEntryRepository
+}
+
+func (failingCarEntries) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return errForced
+}
+
+// seedEntry grava um carro e uma entrada aberta para ele no store.
+func seedEntry(t *testing.T, store *repository.Store) (*model.Car, *model.CarEntry) {
+	t.Helper()
+	ctx := context.Background()
+
+	car := &model.Car{
+		ID:          primitive.NewObjectID(),
+		Number:      "1",
+		Plate:       "ABC1234",
+		Model:       "Gol",
+		Brand:       "VW",
+		Year:        2020,
+		IsActive:    true,
+		Capacity:    50,
+		Consumption: 10,
+	}
+	if err := store.Cars.Create(ctx, car); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := &model.CarEntry{
+		ID:        primitive.NewObjectID(),
+		CarID:     car.ID,
+		UserID:    primitive.NewObjectID(),
+		CheckIn:   model.CheckIn{Location: model.Location{Latitude: 1, Longitude: 1}, NextLocation: "x", ActualKM: 100},
+		StartedAt: time.Now().Add(-time.Hour),
+	}
+	if err := store.CarEntries.Create(ctx, entry, true); err != nil {
+		t.Fatal(err)
+	}
+	return car, entry
+}
+
+// serve executa o handler como um administrador autenticado.
+func serve(handler gin.HandlerFunc, method, path, route string, body interface{}) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user", jwt.MapClaims{"UserType": "ADMIN", "UserId": primitive.NewObjectID().Hex()})
+	})
+	router.Handle(method, route, handler)
+
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(method, path, bytes.NewReader(payload)))
+	return recorder
+}
+
+func TestEndCarEntryRollsBackWhenFuelRecordFails(t *testing.T) {
+	store := repository.NewMemoryStore()
+	car, entry := seedEntry(t, store)
+
+	handler := EndCarEntry(store.CarEntries, store.Cars, failingFuels{store.Fuels}, store.Transactor,
+		service.NewAlertService(store), service.NewChecklistService(store), service.NewDamageService(store), service.NewReservationService(store))
+	recorder := serve(handler, http.MethodPut, "/car-entry/end", "/car-entry/end", gin.H{
+		"carID":    car.ID,
+		"userID":   entry.UserID,
+		"checkOut": gin.H{"location": gin.H{"latitude": 1, "longitude": 1}, "actualKM": 200},
+	})
+	if recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, esperado %d: %s", recorder.Code, http.StatusInternalServerError, recorder.Body)
+	}
+
+	ctx := context.Background()
+	open, err := store.CarEntries.FindOpenByCarAndUser(ctx, car.ID, entry.UserID)
+	if err != nil {
+		t.Fatalf("a entrada deveria continuar aberta: %v", err)
+	}
+	if open.CheckOut != nil || open.KMDriven != nil || open.EndedAt != nil {
+		t.Fatalf("check-out parcial gravado: %+v", open)
+	}
+	if _, err := store.Fuels.FindLatestByCar(ctx, car.ID); err != repository.ErrNotFound {
+		t.Fatalf("nenhum registro de combustível deveria existir, err = %v", err)
+	}
+}
+
+func TestDeleteCarEntryRollsBackAndRestoresImages(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	store := repository.NewMemoryStore()
+	car, entry := seedEntry(t, store)
+	ctx := context.Background()
+	checkOut := model.CheckOut{Location: model.Location{Latitude: 1, Longitude: 1}, ActualKM: 200}
+	if _, err := store.CarEntries.Close(ctx, entry.ID, checkOut, 100, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	image := filepath.Join(carEntryUploadsPath, entry.ID.Hex(), "checkin", "1")
+	if err := os.MkdirAll(filepath.Dir(image), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(image, []byte("imagem"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := DeleteCarEntry(failingCarEntries{store.CarEntries}, store.Cars, store.Fuels, store.Transactor, service.NewAlertService(store))
+	path := "/car-entry/delete/" + entry.ID.Hex()
+	recorder := serve(handler, http.MethodDelete, path, "/car-entry/delete/:entryId", nil)
+	if recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, esperado %d: %s", recorder.Code, http.StatusInternalServerError, recorder.Body)
+	}
+
+	if _, err := store.CarEntries.FindByID(ctx, entry.ID); err != nil {
+		t.Fatalf("a entrada deveria continuar gravada: %v", err)
+	}
+	if _, err := store.Fuels.FindLatestByCar(ctx, car.ID); err != repository.ErrNotFound {
+		t.Fatalf("o ajuste de combustível deveria ter sido desfeito, err = %v", err)
+	}
+	if _, err := os.Stat(image); err != nil {
+		t.Fatalf("as imagens deveriam ter sido restauradas: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(carEntryUploadsPath, entry.ID.Hex()+".deleting")); !os.IsNotExist(err) {
+		t.Fatalf("a pasta temporária deveria ter sido removida, err = %v", err)
+	}
+}
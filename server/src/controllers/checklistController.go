@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// respondChecklistError responde 400 para checklists inválidos e 500 para
+// os demais erros.
+func respondChecklistError(c *gin.Context, err error, fallback string) {
+	var checklistErr *service.ChecklistError
+	if errors.As(err, &checklistErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": checklistErr.Message})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+}
+
+// GetChecklistTemplates lista os modelos. Com carId, devolve apenas os
+// modelos ativos da etapa que valem para o carro.
+func GetChecklistTemplates(templates repository.ChecklistTemplateRepository, cars repository.CarRepository, checklists *service.ChecklistService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := repository.ChecklistTemplateFilter{
+			IsActive: c.Query("active") != "false",
+			Stage:    c.Query("stage"),
+		}
+		if filter.Stage != "" && filter.Stage != model.ChecklistStageCheckIn && filter.Stage != model.ChecklistStageCheckOut {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Etapa inválida"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if carID := c.Query("carId"); carID != "" {
+			objectID, err := primitive.ObjectIDFromHex(carID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID do carro inválido"})
+				return
+			}
+			if filter.Stage == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Informe a etapa para filtrar por carro"})
+				return
+			}
+
+			car, err := cars.FindByID(ctx, objectID)
+			if err != nil {
+				if err == repository.ErrNotFound {
+					c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+				} else {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar carro"})
+				}
+				return
+			}
+
+			result, err := checklists.Applicable(ctx, filter.Stage, car)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar modelos de checklist"})
+				return
+			}
+			c.JSON(http.StatusOK, result)
+			return
+		}
+
+		result, err := templates.List(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar modelos de checklist"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func GetChecklistTemplate(templates repository.ChecklistTemplateRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		objectID, err := primitive.ObjectIDFromHex(c.Param("templateId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		template, err := templates.FindByID(ctx, objectID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Modelo de checklist não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar modelo de checklist"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, template)
+	}
+}
+
+func CreateChecklistTemplate(checklists *service.ChecklistService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		var template model.ChecklistTemplate
+		if err := c.ShouldBindJSON(&template); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+
+		if err := validate.Struct(template); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := checklists.CreateTemplate(ctx, &template); err != nil {
+			respondChecklistError(c, err, "Erro ao criar modelo de checklist")
+			return
+		}
+
+		c.JSON(http.StatusCreated, template)
+	}
+}
+
+func UpdateChecklistTemplate(checklists *service.ChecklistService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		objectID, err := primitive.ObjectIDFromHex(c.Param("templateId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		var template model.ChecklistTemplate
+		if err := c.ShouldBindJSON(&template); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+		if err := validate.Struct(template); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err = checklists.UpdateTemplate(ctx, objectID, &template)
+		if err != nil {
+			switch err {
+			case repository.ErrNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": "Modelo de checklist não encontrado"})
+			case repository.ErrChecklistVersionExists:
+				c.JSON(http.StatusConflict, gin.H{"error": "Modelo de checklist alterado por outra pessoa; tente novamente"})
+			default:
+				respondChecklistError(c, err, "Erro ao atualizar modelo de checklist")
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, template)
+	}
+}
+
+func GetChecklistTemplateVersions(templates repository.ChecklistTemplateRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		objectID, err := primitive.ObjectIDFromHex(c.Param("templateId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		versions, err := templates.ListVersions(ctx, objectID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar versões do modelo de checklist"})
+			return
+		}
+		if len(versions) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Modelo de checklist não encontrado"})
+			return
+		}
+
+		c.JSON(http.StatusOK, versions)
+	}
+}
+
+// GetChecklistTemplateVersion devolve o modelo exatamente como estava na
+// versão usada por uma entrada.
+func GetChecklistTemplateVersion(templates repository.ChecklistTemplateRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		objectID, err := primitive.ObjectIDFromHex(c.Param("templateId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+		version, err := strconv.Atoi(c.Param("version"))
+		if err != nil || version < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Versão inválida"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := templates.FindVersion(ctx, objectID, version)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Versão do modelo de checklist não encontrada"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar versão do modelo de checklist"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func DisableChecklistTemplate(templates repository.ChecklistTemplateRepository) gin.HandlerFunc {
+	return setChecklistTemplateActive(templates, false, "Modelo de checklist desativado com sucesso", "Erro ao desativar modelo de checklist")
+}
+
+func EnableChecklistTemplate(templates repository.ChecklistTemplateRepository) gin.HandlerFunc {
+	return setChecklistTemplateActive(templates, true, "Modelo de checklist ativado com sucesso", "Erro ao ativar modelo de checklist")
+}
+
+func setChecklistTemplateActive(templates repository.ChecklistTemplateRepository, active bool, message, errorMessage string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		templateID := c.Param("templateId")
+		objectID, err := primitive.ObjectIDFromHex(templateID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err = templates.SetActive(ctx, objectID, active)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Modelo de checklist não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": errorMessage})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": message, "id": templateID})
+	}
+}
+
+func GetChecklistFailures(checklists *service.ChecklistService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		from, to, ok := reportPeriod(c)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		failures, err := checklists.FailureSummary(ctx, from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar relatório de checklist"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"from": from, "to": to, "items": failures})
+	}
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	helper "server/src/helpers"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// consumptionThreshold usa o parâmetro ?threshold= quando informado e, caso
+// contrário, o valor configurado no ambiente.
+func consumptionThreshold(c *gin.Context) (float64, bool) {
+	value := c.Query("threshold")
+	if value == "" {
+		return service.ConsumptionDeviationThreshold(), true
+	}
+
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Percentual de desvio inválido"})
+		return 0, false
+	}
+	return threshold, true
+}
+
+func GetConsumptionReport(consumption *service.ConsumptionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		carID, err := primitive.ObjectIDFromHex(c.Param("carId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		threshold, ok := consumptionThreshold(c)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		report, err := consumption.Report(ctx, carID, threshold)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular consumo"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+func GetConsumptionReports(consumption *service.ConsumptionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		threshold, ok := consumptionThreshold(c)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		reports, err := consumption.ReportActive(ctx, threshold)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular consumo"})
+			return
+		}
+
+		c.JSON(http.StatusOK, reports)
+	}
+}
@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// respondCostCenterError responde 400 para dados inválidos, 409 para código
+// repetido e 500 para os demais erros.
+func respondCostCenterError(c *gin.Context, err error, fallback string) {
+	var costCenterErr *service.CostCenterError
+	switch {
+	case errors.As(err, &costCenterErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": costCenterErr.Message})
+	case err == repository.ErrCostCenterCodeExists:
+		c.JSON(http.StatusConflict, gin.H{"error": "Código de centro de custo já cadastrado"})
+	case err == repository.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Centro de custo não encontrado"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+func GetCostCenters(costCenters repository.CostCenterRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := costCenters.List(ctx, c.Query("active") != "false")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar centros de custo"})
+			return
+		}
+		if result == nil {
+			result = []model.CostCenter{}
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func GetCostCenter(costCenters repository.CostCenterRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		objectID, err := primitive.ObjectIDFromHex(c.Param("costCenterId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		costCenter, err := costCenters.FindByID(ctx, objectID)
+		if err != nil {
+			respondCostCenterError(c, err, "Erro ao buscar centro de custo")
+			return
+		}
+
+		c.JSON(http.StatusOK, costCenter)
+	}
+}
+
+func CreateCostCenter(costCenters *service.CostCenterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		var costCenter model.CostCenter
+		if err := c.ShouldBindJSON(&costCenter); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+		if err := validate.Struct(costCenter); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := costCenters.Create(ctx, &costCenter); err != nil {
+			respondCostCenterError(c, err, "Erro ao criar centro de custo")
+			return
+		}
+
+		c.JSON(http.StatusCreated, costCenter)
+	}
+}
+
+func UpdateCostCenter(costCenters *service.CostCenterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		objectID, err := primitive.ObjectIDFromHex(c.Param("costCenterId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		var costCenter model.CostCenter
+		if err := c.ShouldBindJSON(&costCenter); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+		if err := validate.StructExcept(costCenter, "Code"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := costCenters.Update(ctx, objectID, &costCenter); err != nil {
+			respondCostCenterError(c, err, "Erro ao atualizar centro de custo")
+			return
+		}
+
+		c.JSON(http.StatusOK, costCenter)
+	}
+}
+
+func DisableCostCenter(costCenters repository.CostCenterRepository) gin.HandlerFunc {
+	return setCostCenterActive(costCenters, false, "Centro de custo desativado com sucesso", "Erro ao desativar centro de custo")
+}
+
+func EnableCostCenter(costCenters repository.CostCenterRepository) gin.HandlerFunc {
+	return setCostCenterActive(costCenters, true, "Centro de custo ativado com sucesso", "Erro ao ativar centro de custo")
+}
+
+func setCostCenterActive(costCenters repository.CostCenterRepository, active bool, message, errorMessage string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		costCenterID := c.Param("costCenterId")
+		objectID, err := primitive.ObjectIDFromHex(costCenterID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := costCenters.SetActive(ctx, objectID, active); err != nil {
+			respondCostCenterError(c, err, errorMessage)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": message, "id": costCenterID})
+	}
+}
+
+// GetCostCenterUsage soma os km das viagens concluídas no período por centro
+// de custo.
+func GetCostCenterUsage(costCenters *service.CostCenterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		from, to, ok := reportPeriod(c)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		usage, err := costCenters.Usage(ctx, from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar relatório de centros de custo"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"from": from, "to": to, "items": usage})
+	}
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const reportDateLayout = "2006-01-02"
+
+// reportPeriod lê ?from= e ?to= (datas inclusivas, no formato 2006-01-02).
+// Sem parâmetros, usa o mês corrente.
+func reportPeriod(c *gin.Context) (time.Time, time.Time, bool) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 1, 0)
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.ParseInLocation(reportDateLayout, value, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data inicial inválida"})
+			return from, to, false
+		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.ParseInLocation(reportDateLayout, value, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Data final inválida"})
+			return from, to, false
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data inicial deve ser anterior à final"})
+		return from, to, false
+	}
+	return from, to, true
+}
+
+func GetCostReport(costs *service.CostReportService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		from, to, ok := reportPeriod(c)
+		if !ok {
+			return
+		}
+
+		groupBy := c.DefaultQuery("groupBy", model.CostGroupByCar)
+		if groupBy != model.CostGroupByCar && groupBy != model.CostGroupByDriver && groupBy != model.CostGroupByPeriod {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Agrupamento inválido; use car, driver ou period"})
+			return
+		}
+		interval := c.DefaultQuery("interval", model.CostIntervalMonth)
+		if interval != model.CostIntervalDay && interval != model.CostIntervalMonth {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Intervalo inválido; use day ou month"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		report, err := costs.Report(ctx, from, to, groupBy, interval)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar relatório de custos"})
+			return
+		}
+
+		if c.Query("format") == "csv" {
+			writeCostReportCSV(c, report)
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+func writeCostReportCSV(c *gin.Context, report *model.CostReport) {
+	filename := fmt.Sprintf("custos_%s_%s_%s.csv", report.GroupBy,
+		report.From.Format(reportDateLayout), report.To.AddDate(0, 0, -1).Format(reportDateLayout))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	formatFloat := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"chave", "descricao", "abastecimentos", "litros", "custo_combustivel", "viagens", "km_rodados", "custo_por_km"})
+	for _, row := range append(report.Rows, report.Total) {
+		costPerKM := ""
+		if row.CostPerKM != nil {
+			costPerKM = strconv.FormatFloat(*row.CostPerKM, 'f', 4, 64)
+		}
+		writer.Write([]string{
+			row.Key,
+			row.Label,
+			strconv.Itoa(row.Refuels),
+			formatFloat(row.Liters),
+			formatFloat(row.FuelCost),
+			strconv.Itoa(row.Trips),
+			formatFloat(row.KMDriven),
+			costPerKM,
+		})
+	}
+	writer.Flush()
+}
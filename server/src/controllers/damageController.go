@@ -0,0 +1,342 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// reportChecklistDamages abre as avarias dos itens reprovados no checklist da
+// etapa. Falhas são apenas registradas no log para não desfazer a entrada
+// que já foi gravada.
+func reportChecklistDamages(ctx context.Context, damages *service.DamageService, entry *model.CarEntry, stage string, checklist *model.Checklist) {
+	if err := damages.ReportChecklistFailures(ctx, entry, stage, checklist); err != nil {
+		log.Println("Erro ao registrar avarias do checklist da entrada", entry.ID.Hex(), err)
+	}
+}
+
+func GetDamages(damages repository.DamageRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := repository.DamageFilter{}
+		if status := c.Query("status"); status != "" {
+			switch status {
+			case model.DamageStatusOpen, model.DamageStatusInRepair, model.DamageStatusResolved, model.DamageStatusRejected:
+				filter.Status = []string{status}
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Status inválido"})
+				return
+			}
+		}
+		if carID := c.Query("carId"); carID != "" {
+			objectID, err := primitive.ObjectIDFromHex(carID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			filter.CarID = &objectID
+		}
+		if entryID := c.Query("carEntryId"); entryID != "" {
+			objectID, err := primitive.ObjectIDFromHex(entryID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			filter.CarEntryID = &objectID
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := damages.List(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar avarias"})
+			return
+		}
+		if result == nil {
+			result = []model.Damage{}
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func GetDamage(damages repository.DamageRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		objectID, err := primitive.ObjectIDFromHex(c.Param("damageId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		damage, err := damages.FindByID(ctx, objectID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Avaria não encontrada"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar avaria"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, damage)
+	}
+}
+
+// CreateDamage registra uma avaria. Motoristas só podem relatar avarias
+// vinculadas a uma entrada própria; administradores podem relatar avarias
+// avulsas do carro.
+func CreateDamage(damages *service.DamageService, carEntries repository.CarEntryRepository, cars repository.CarRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		type CreateDamageInput struct {
+			CarID       primitive.ObjectID  `json:"carId" binding:"required"`
+			CarEntryID  *primitive.ObjectID `json:"carEntryId"`
+			Stage       string              `json:"stage" binding:"omitempty,oneof=checkin checkout"`
+			Location    string              `json:"location" binding:"required,max=100"`
+			Description string              `json:"description" binding:"required,max=1000"`
+			Severity    string              `json:"severity" binding:"required,oneof=low medium high"`
+		}
+
+		var input CreateDamageInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+
+		ok, userType, userId := helper.CurrentUser(c)
+		if !ok {
+			return
+		}
+		reportedBy, err := primitive.ObjectIDFromHex(userId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		if (input.CarEntryID == nil) != (input.Stage == "") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Informe a entrada e a etapa juntas"})
+			return
+		}
+		if input.CarEntryID == nil && userType != "ADMIN" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Informe a entrada em que a avaria foi encontrada"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if _, err := cars.FindByID(ctx, input.CarID); err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar carro"})
+			}
+			return
+		}
+
+		if input.CarEntryID != nil {
+			entry, err := carEntries.FindByID(ctx, *input.CarEntryID)
+			if err != nil {
+				if err == repository.ErrNotFound {
+					c.JSON(http.StatusNotFound, gin.H{"error": "Entrada não encontrada"})
+				} else {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar entrada"})
+				}
+				return
+			}
+			if entry.CarID != input.CarID {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "A entrada não pertence ao carro informado"})
+				return
+			}
+			if userType != "ADMIN" && entry.UserID != reportedBy {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Você não tem permissão para acessar este recurso"})
+				return
+			}
+		}
+
+		damage := model.Damage{
+			CarID:       input.CarID,
+			CarEntryID:  input.CarEntryID,
+			Stage:       input.Stage,
+			Location:    input.Location,
+			Description: input.Description,
+			Severity:    input.Severity,
+			ReportedBy:  reportedBy,
+		}
+		if err := damages.Report(ctx, &damage); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar avaria"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, damage)
+	}
+}
+
+func UploadDamagePhotos(damages repository.DamageRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		damageID := c.Param("damageId")
+		objectID, err := primitive.ObjectIDFromHex(damageID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		damage, err := damages.FindByID(ctx, objectID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Avaria não encontrada"})
+			return
+		}
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, damage.ReportedBy.Hex()); !ok {
+			return
+		}
+
+		uploadedPaths, err := uploadImages(c, damageUploadsPath, damageID, "", 5)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := damages.AddPhotos(ctx, objectID, uploadedPaths); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao atualizar avaria com as fotos"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Fotos da avaria enviadas com sucesso",
+			"photos":  uploadedPaths,
+		})
+	}
+}
+
+func UpdateDamageStatus(damages *service.DamageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, _, userId := helper.CheckAdminOrUidPermission(c, "")
+		if !ok {
+			return
+		}
+
+		type UpdateDamageStatusInput struct {
+			Status string `json:"status" binding:"required,oneof=open in_repair resolved rejected"`
+			Note   string `json:"note" binding:"max=500"`
+		}
+
+		var input UpdateDamageStatusInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+
+		damageID, userID, ok := damageAndUserIDs(c, userId)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		damage, err := damages.Transition(ctx, damageID, input.Status, input.Note, userID)
+		respondDamageUpdate(c, damage, err)
+	}
+}
+
+func AssignDamage(damages *service.DamageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, _, userId := helper.CheckAdminOrUidPermission(c, "")
+		if !ok {
+			return
+		}
+
+		type AssignDamageInput struct {
+			AssigneeID primitive.ObjectID `json:"assigneeId" binding:"required"`
+			Note       string             `json:"note" binding:"max=500"`
+		}
+
+		var input AssignDamageInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+
+		damageID, userID, ok := damageAndUserIDs(c, userId)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		damage, err := damages.Assign(ctx, damageID, input.AssigneeID, input.Note, userID)
+		respondDamageUpdate(c, damage, err)
+	}
+}
+
+func damageAndUserIDs(c *gin.Context, userId string) (primitive.ObjectID, primitive.ObjectID, bool) {
+	damageID, err := primitive.ObjectIDFromHex(c.Param("damageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	userID, err := primitive.ObjectIDFromHex(userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return damageID, userID, true
+}
+
+func respondDamageUpdate(c *gin.Context, damage *model.Damage, err error) {
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Avaria não encontrada"})
+		case service.ErrAssigneeNotFound:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case service.ErrInvalidDamageTransition, service.ErrDamageClosed:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar avaria"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, damage)
+}
+
+func CompareEntryDamages(damages *service.DamageService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entryID, err := primitive.ObjectIDFromHex(c.Param("entryId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		comparison, err := damages.Compare(ctx, entryID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Entrada não encontrada"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao comparar avarias"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, comparison)
+	}
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func CheckFuelLedger(ledger *service.FuelLedgerService) gin.HandlerFunc {
+	return fuelLedgerHandler(ledger.Check, "Erro ao verificar histórico de combustível")
+}
+
+func RebuildFuelLedger(ledger *service.FuelLedgerService, alerts *service.AlertService) gin.HandlerFunc {
+	rebuild := func(ctx context.Context, carID primitive.ObjectID) (*model.FuelLedgerReport, error) {
+		report, err := ledger.Rebuild(ctx, carID)
+		if err == nil {
+			evaluateFuelAlerts(ctx, alerts, carID)
+		}
+		return report, err
+	}
+	return fuelLedgerHandler(rebuild, "Erro ao recalcular histórico de combustível")
+}
+
+func fuelLedgerHandler(run func(context.Context, primitive.ObjectID) (*model.FuelLedgerReport, error), errorMessage string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		carID, err := primitive.ObjectIDFromHex(c.Param("carId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		report, err := run(ctx, carID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": errorMessage})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
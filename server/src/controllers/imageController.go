@@ -3,18 +3,29 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"os"
 	"path/filepath"
 	"time"
 
+	helper "server/src/helpers"
 	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	carEntryUploadsPath    = "uploads/carEntries"
+	fuelUploadsPath        = "uploads/fuels"
+	damageUploadsPath      = "uploads/damages"
+	maintenanceUploadsPath = "uploads/maintenance"
+	carDocumentUploadsPath = "uploads/documents"
+)
+
 func ensureDir(dirName string) error {
 	err := os.MkdirAll(dirName, 0755)
 	if err != nil {
@@ -23,7 +34,7 @@ func ensureDir(dirName string) error {
 	return nil
 }
 
-func uploadImages(c *gin.Context, entryID, subfolder string) ([]string, error) {
+func uploadImages(c *gin.Context, basePath, entryID, subfolder string, maxImages int) ([]string, error) {
 	form, err := c.MultipartForm()
 	if err != nil {
 		return nil, fmt.Errorf("erro ao ler formulário: %v", err)
@@ -33,11 +44,10 @@ func uploadImages(c *gin.Context, entryID, subfolder string) ([]string, error) {
 	if len(files) == 0 {
 		return nil, fmt.Errorf("nenhuma imagem enviada")
 	}
-	if len(files) > 5 {
-		return nil, fmt.Errorf("máximo de 5 imagens permitido")
+	if len(files) > maxImages {
+		return nil, fmt.Errorf("máximo de %d imagens permitido", maxImages)
 	}
 
-	basePath := "uploads/carEntries"
 	entryPath := filepath.Join(basePath, entryID, subfolder)
 
 	if err := ensureDir(entryPath); err != nil {
@@ -60,7 +70,30 @@ func uploadImages(c *gin.Context, entryID, subfolder string) ([]string, error) {
 	return uploadedPaths, nil
 }
 
-func UploadCheckInImages() gin.HandlerFunc {
+// trashEntryImages move a pasta de imagens da entrada para um caminho
+// temporário, que pode ser restaurado com restoreEntryImages caso a remoção
+// no banco falhe. Retorna "" quando a entrada não possui imagens.
+func trashEntryImages(entryID string) (string, error) {
+	entryPath := filepath.Join(carEntryUploadsPath, entryID)
+	trashPath := entryPath + ".deleting"
+
+	if err := os.Rename(entryPath, trashPath); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("falha ao mover imagens: %v", err)
+	}
+	return trashPath, nil
+}
+
+func restoreEntryImages(entryID, trashPath string) error {
+	if trashPath == "" {
+		return nil
+	}
+	return os.Rename(trashPath, filepath.Join(carEntryUploadsPath, entryID))
+}
+
+func UploadCheckInImages(carEntries repository.CarEntryRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		entryID := c.Param("entryId")
 
@@ -73,25 +106,19 @@ func UploadCheckInImages() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		var carEntry model.CarEntry
-		err = carEntryCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&carEntry)
+		_, err = carEntries.FindByID(ctx, objectID)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "CarEntry não encontrado"})
 			return
 		}
 
-		uploadedPaths, err := uploadImages(c, entryID, "checkin")
+		uploadedPaths, err := uploadImages(c, carEntryUploadsPath, entryID, "checkin", 5)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		update := bson.M{
-			"$push": bson.M{
-				"checkIn.images": bson.M{"$each": uploadedPaths},
-			},
-		}
-		_, err = carEntryCollection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+		err = carEntries.AddCheckInImages(ctx, objectID, uploadedPaths)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao atualizar CarEntry com imagens"})
 			return
@@ -103,7 +130,7 @@ func UploadCheckInImages() gin.HandlerFunc {
 	}
 }
 
-func UploadCheckOutImages() gin.HandlerFunc {
+func UploadCheckOutImages(carEntries repository.CarEntryRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		entryID := c.Param("entryId")
 
@@ -116,25 +143,19 @@ func UploadCheckOutImages() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		var carEntry model.CarEntry
-		err = carEntryCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&carEntry)
+		_, err = carEntries.FindByID(ctx, objectID)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "CarEntry não encontrado"})
 			return
 		}
 
-		uploadedPaths, err := uploadImages(c, entryID, "checkout")
+		uploadedPaths, err := uploadImages(c, carEntryUploadsPath, entryID, "checkout", 5)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		update := bson.M{
-			"$push": bson.M{
-				"checkOut.images": bson.M{"$each": uploadedPaths},
-			},
-		}
-		_, err = carEntryCollection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+		err = carEntries.AddCheckOutImages(ctx, objectID, uploadedPaths)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao atualizar CarEntry com imagens"})
 			return
@@ -145,3 +166,112 @@ func UploadCheckOutImages() gin.HandlerFunc {
 		})
 	}
 }
+
+func UploadFuelReceipt(fuels repository.FuelRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fuelID := c.Param("fuelId")
+
+		objectID, err := primitive.ObjectIDFromHex(fuelID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		fuel, err := fuels.FindByID(ctx, objectID)
+		if err != nil || fuel.Type != model.FuelTypeRefuel {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Abastecimento não encontrado"})
+			return
+		}
+
+		driverID := ""
+		if fuel.DriverID != nil {
+			driverID = fuel.DriverID.Hex()
+		}
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, driverID); !ok {
+			return
+		}
+
+		uploadedPaths, err := uploadImages(c, fuelUploadsPath, fuelID, "receipt", 1)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = fuels.SetReceiptImage(ctx, objectID, uploadedPaths[0])
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao atualizar abastecimento com o comprovante"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "Comprovante enviado com sucesso",
+			"receiptImage": uploadedPaths[0],
+		})
+	}
+}
+
+func UploadChecklistPhoto(carEntries repository.CarEntryRepository, damages *service.DamageService, stage string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entryID := c.Param("entryId")
+		itemKey := c.Param("itemKey")
+
+		objectID, err := primitive.ObjectIDFromHex(entryID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		carEntry, err := carEntries.FindByID(ctx, objectID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "CarEntry não encontrado"})
+			return
+		}
+
+		checklist := carEntry.CheckIn.Checklist
+		if stage == model.ChecklistStageCheckOut {
+			checklist = nil
+			if carEntry.CheckOut != nil {
+				checklist = carEntry.CheckOut.Checklist
+			}
+		}
+		if checklist == nil || !checklistHasItem(checklist, itemKey) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Item de checklist não encontrado"})
+			return
+		}
+
+		uploadedPaths, err := uploadImages(c, carEntryUploadsPath, entryID, filepath.Join(stage, "checklist", itemKey), 1)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = carEntries.SetChecklistPhoto(ctx, objectID, stage, itemKey, uploadedPaths[0])
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao atualizar CarEntry com a foto do checklist"})
+			return
+		}
+		if err := damages.AttachChecklistPhoto(ctx, objectID, stage, itemKey, uploadedPaths[0]); err != nil {
+			log.Println("Erro ao anexar foto do checklist à avaria da entrada", entryID, err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Foto do checklist enviada com sucesso",
+			"photo":   uploadedPaths[0],
+		})
+	}
+}
+
+func checklistHasItem(checklist *model.Checklist, key string) bool {
+	for _, item := range checklist.Items {
+		if item.Key == key {
+			return true
+		}
+	}
+	return false
+}
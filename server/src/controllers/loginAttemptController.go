@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	helper "server/src/helpers"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultLoginAttemptLimit limita a listagem quando ?limit= não é informado.
+const defaultLoginAttemptLimit = 200
+
+// GetLoginAttempts lista as tentativas de login, da mais recente para a mais
+// antiga. Aceita email, ip, userId, result, o período from/to (AAAA-MM-DD) e
+// limit.
+func GetLoginAttempts(attempts repository.LoginAttemptRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		filter := repository.LoginAttemptFilter{
+			Email:  service.NormalizeLoginEmail(c.Query("email")),
+			IP:     c.Query("ip"),
+			Result: c.Query("result"),
+			Limit:  defaultLoginAttemptLimit,
+		}
+		if userID := c.Query("userId"); userID != "" {
+			objectID, err := primitive.ObjectIDFromHex(userID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			filter.UserID = &objectID
+		}
+		if value := c.Query("from"); value != "" {
+			from, err := time.ParseInLocation(reportDateLayout, value, time.Local)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Data inicial inválida"})
+				return
+			}
+			filter.From = &from
+		}
+		if value := c.Query("to"); value != "" {
+			to, err := time.ParseInLocation(reportDateLayout, value, time.Local)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Data final inválida"})
+				return
+			}
+			to = to.AddDate(0, 0, 1)
+			filter.To = &to
+		}
+		if value := c.Query("limit"); value != "" {
+			limit, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || limit <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Limite inválido"})
+				return
+			}
+			filter.Limit = limit
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := attempts.ListAttempts(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar tentativas de login"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// UnlockUser libera o login de um usuário bloqueado por excesso de
+// tentativas.
+func UnlockUser(users repository.UserRepository, guard *service.LoginGuardService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		userID := c.Param("userId")
+		objectID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		user, err := users.FindByID(ctx, objectID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao desbloquear usuário"})
+			}
+			return
+		}
+
+		if err := guard.Unlock(ctx, user.Email); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao desbloquear usuário"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Usuário desbloqueado com sucesso", "id": userID})
+	}
+}
+
+// UnlockLoginIP libera um IP bloqueado por excesso de tentativas de login.
+func UnlockLoginIP(guard *service.LoginGuardService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		ip := c.Param("ip")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := guard.UnlockIP(ctx, ip); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao desbloquear IP"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "IP desbloqueado com sucesso", "ip": ip})
+	}
+}
@@ -0,0 +1,405 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// respondMaintenanceError responde 400 para planos e registros inválidos e
+// 500 para os demais erros.
+func respondMaintenanceError(c *gin.Context, err error, fallback string) {
+	var maintenanceErr *service.MaintenanceError
+	if errors.As(err, &maintenanceErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": maintenanceErr.Message})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+}
+
+func GetMaintenancePlans(maintenance repository.MaintenanceRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := maintenance.ListPlans(ctx, c.Query("active") != "false")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar planos de manutenção"})
+			return
+		}
+		if result == nil {
+			result = []model.MaintenancePlan{}
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func GetMaintenancePlan(maintenance repository.MaintenanceRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		objectID, err := primitive.ObjectIDFromHex(c.Param("planId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		plan, err := maintenance.FindPlanByID(ctx, objectID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Plano de manutenção não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar plano de manutenção"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+	}
+}
+
+func CreateMaintenancePlan(maintenance *service.MaintenanceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		var plan model.MaintenancePlan
+		if err := c.ShouldBindJSON(&plan); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+		if err := validate.Struct(plan); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := maintenance.CreatePlan(ctx, &plan); err != nil {
+			respondMaintenanceError(c, err, "Erro ao criar plano de manutenção")
+			return
+		}
+
+		c.JSON(http.StatusCreated, plan)
+	}
+}
+
+func UpdateMaintenancePlan(maintenance *service.MaintenanceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		objectID, err := primitive.ObjectIDFromHex(c.Param("planId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		var plan model.MaintenancePlan
+		if err := c.ShouldBindJSON(&plan); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+		if err := validate.Struct(plan); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := maintenance.UpdatePlan(ctx, objectID, &plan); err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Plano de manutenção não encontrado"})
+			} else {
+				respondMaintenanceError(c, err, "Erro ao atualizar plano de manutenção")
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+	}
+}
+
+func DisableMaintenancePlan(maintenance repository.MaintenanceRepository) gin.HandlerFunc {
+	return setMaintenancePlanActive(maintenance, false, "Plano de manutenção desativado com sucesso", "Erro ao desativar plano de manutenção")
+}
+
+func EnableMaintenancePlan(maintenance repository.MaintenanceRepository) gin.HandlerFunc {
+	return setMaintenancePlanActive(maintenance, true, "Plano de manutenção ativado com sucesso", "Erro ao ativar plano de manutenção")
+}
+
+func setMaintenancePlanActive(maintenance repository.MaintenanceRepository, active bool, message, errorMessage string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		planID := c.Param("planId")
+		objectID, err := primitive.ObjectIDFromHex(planID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err = maintenance.SetPlanActive(ctx, objectID, active)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Plano de manutenção não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": errorMessage})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": message, "id": planID})
+	}
+}
+
+// GetMaintenanceSchedule lista o vencimento dos planos por carro. Aceita
+// carId e status (ok, due_soon ou overdue).
+func GetMaintenanceSchedule(maintenance *service.MaintenanceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := c.Query("status")
+		switch status {
+		case "", model.MaintenanceDueOK, model.MaintenanceDueSoon, model.MaintenanceDueOverdue:
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Status inválido"})
+			return
+		}
+
+		var carID *primitive.ObjectID
+		if id := c.Query("carId"); id != "" {
+			objectID, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			carID = &objectID
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		schedule, err := maintenance.Schedule(ctx, carID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular vencimentos de manutenção"})
+			}
+			return
+		}
+
+		result := []model.MaintenanceDue{}
+		for _, due := range schedule {
+			if status == "" || due.Status == status {
+				result = append(result, due)
+			}
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func GetMaintenanceRecords(maintenance repository.MaintenanceRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := repository.MaintenanceRecordFilter{}
+		if carID := c.Query("carId"); carID != "" {
+			objectID, err := primitive.ObjectIDFromHex(carID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			filter.CarID = &objectID
+		}
+		if planID := c.Query("planId"); planID != "" {
+			objectID, err := primitive.ObjectIDFromHex(planID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			filter.PlanID = &objectID
+		}
+		switch c.Query("inProgress") {
+		case "true":
+			inProgress := true
+			filter.InProgress = &inProgress
+		case "false":
+			inProgress := false
+			filter.InProgress = &inProgress
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := maintenance.ListRecords(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar manutenções"})
+			return
+		}
+		if result == nil {
+			result = []model.MaintenanceRecord{}
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func GetMaintenanceRecord(maintenance repository.MaintenanceRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		objectID, err := primitive.ObjectIDFromHex(c.Param("recordId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		record, err := maintenance.FindRecordByID(ctx, objectID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Manutenção não encontrada"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar manutenção"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, record)
+	}
+}
+
+// CreateMaintenanceRecord registra uma manutenção. Sem completedAt ela fica
+// em andamento e o carro aparece como em manutenção até a conclusão.
+func CreateMaintenanceRecord(maintenance *service.MaintenanceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, _, userId := helper.CheckAdminOrUidPermission(c, "")
+		if !ok {
+			return
+		}
+		createdBy, err := primitive.ObjectIDFromHex(userId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		var record model.MaintenanceRecord
+		if err := c.ShouldBindJSON(&record); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+		if err := validate.Struct(record); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		record.CreatedBy = createdBy
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := maintenance.CreateRecord(ctx, &record); err != nil {
+			respondMaintenanceError(c, err, "Erro ao registrar manutenção")
+			return
+		}
+
+		c.JSON(http.StatusCreated, record)
+	}
+}
+
+func CompleteMaintenanceRecord(maintenance *service.MaintenanceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		objectID, err := primitive.ObjectIDFromHex(c.Param("recordId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		type CompleteMaintenanceInput struct {
+			Odometer float64 `json:"odometer" binding:"gte=0"`
+			Cost     float64 `json:"cost" binding:"gte=0"`
+		}
+
+		var input CompleteMaintenanceInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		record, err := maintenance.CompleteRecord(ctx, objectID, input.Odometer, input.Cost)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Manutenção não encontrada"})
+			} else {
+				respondMaintenanceError(c, err, "Erro ao concluir manutenção")
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, record)
+	}
+}
+
+func UploadMaintenanceInvoices(maintenance repository.MaintenanceRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		recordID := c.Param("recordId")
+		objectID, err := primitive.ObjectIDFromHex(recordID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if _, err := maintenance.FindRecordByID(ctx, objectID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Manutenção não encontrada"})
+			return
+		}
+
+		uploadedPaths, err := uploadImages(c, maintenanceUploadsPath, recordID, "", 5)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := maintenance.AddRecordInvoices(ctx, objectID, uploadedPaths); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Falha ao atualizar manutenção com as notas fiscais"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":  "Notas fiscais enviadas com sucesso",
+			"invoices": uploadedPaths,
+		})
+	}
+}
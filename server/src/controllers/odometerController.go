@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	helper "server/src/helpers"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// odometerTolerance usa o parâmetro ?tolerance= quando informado e, caso
+// contrário, o valor configurado no ambiente.
+func odometerTolerance(c *gin.Context) (float64, bool) {
+	value := c.Query("tolerance")
+	if value == "" {
+		return service.OdometerTolerance(), true
+	}
+
+	tolerance, err := strconv.ParseFloat(value, 64)
+	if err != nil || tolerance < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tolerância inválida"})
+		return 0, false
+	}
+	return tolerance, true
+}
+
+func GetOdometerTimeline(odometer *service.OdometerService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
+			return
+		}
+
+		carID, err := primitive.ObjectIDFromHex(c.Param("carId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+		tolerance, ok := odometerTolerance(c)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		timeline, err := odometer.Timeline(ctx, carID, tolerance)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Carro não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar histórico do odômetro"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, timeline)
+	}
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func respondPasswordError(c *gin.Context, err error, fallback string) {
+	var passwordErr *service.PasswordError
+	var throttled *service.LoginThrottledError
+	switch {
+	case errors.As(err, &passwordErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": passwordErr.Message})
+	case errors.As(err, &throttled):
+		retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Muitas tentativas; aguarde antes de tentar novamente", "retryAfter": retryAfter})
+	case err == service.ErrInvalidPasswordResetToken:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Link de redefinição inválido ou expirado"})
+	case err == repository.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// ChangePassword troca a senha do usuário logado. As demais sessões do
+// usuário são encerradas; a sessão usada na troca continua válida.
+func ChangePassword(passwords *service.PasswordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, _, userId := helper.CurrentUser(c)
+		if !ok {
+			return
+		}
+		userID, err := primitive.ObjectIDFromHex(userId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		var request struct {
+			CurrentPassword string `json:"currentPassword" validate:"required"`
+			NewPassword     string `json:"newPassword" validate:"required"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil || validate.Struct(request) != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Informe a senha atual e a nova senha"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		attempt := model.LoginAttempt{IP: c.ClientIP(), DeviceInfo: requestDeviceInfo(c)}
+		if err := passwords.Change(ctx, userID, request.CurrentPassword, request.NewPassword, currentSessionID(c), &attempt); err != nil {
+			respondPasswordError(c, err, "Erro ao alterar a senha")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Senha alterada com sucesso"})
+	}
+}
+
+// ForgotPassword envia o link de redefinição de senha. A resposta é a mesma
+// para emails cadastrados ou não.
+func ForgotPassword(passwords *service.PasswordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request struct {
+			Email string `json:"email" validate:"required"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil || validate.Struct(request) != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Informe o email"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := passwords.RequestReset(ctx, request.Email, c.ClientIP()); err != nil {
+			log.Println("Erro ao gerar redefinição de senha:", err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Se o email estiver cadastrado, enviaremos um link para redefinir a senha"})
+	}
+}
+
+// ResetPassword define uma nova senha com o token recebido por email.
+func ResetPassword(passwords *service.PasswordService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request struct {
+			Token       string `json:"token" validate:"required"`
+			NewPassword string `json:"newPassword" validate:"required"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil || validate.Struct(request) != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Informe o token e a nova senha"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := passwords.Reset(ctx, request.Token, request.NewPassword); err != nil {
+			respondPasswordError(c, err, "Erro ao redefinir a senha")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Senha redefinida com sucesso"})
+	}
+}
@@ -0,0 +1,310 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// respondReservationError responde 400 para reservas inválidas, 409 para
+// conflitos de horário, CNH incompatível ou mudança de status recusada e 500
+// para os demais erros.
+func respondReservationError(c *gin.Context, err error, fallback string) {
+	var reservationErr *service.ReservationError
+	var conflictErr *service.ReservationConflictError
+	var licenseErr *service.LicenseError
+	switch {
+	case err == repository.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reserva não encontrada"})
+	case err == service.ErrInvalidReservationTransition:
+		c.JSON(http.StatusConflict, gin.H{"error": "A reserva não pode passar para este status"})
+	case errors.As(err, &reservationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": reservationErr.Message})
+	case errors.As(err, &conflictErr):
+		c.JSON(http.StatusConflict, gin.H{"error": "Carro já reservado neste horário", "conflicts": conflictErr.Conflicts})
+	case errors.As(err, &licenseErr):
+		c.JSON(http.StatusConflict, gin.H{"error": licenseErr.Message})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// reservationPeriod lê from e to em RFC 3339; os dois são obrigatórios.
+func reservationPeriod(c *gin.Context) (time.Time, time.Time, bool) {
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data inicial inválida"})
+		return from, from, false
+	}
+	to, err := time.Parse(time.RFC3339, c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data final inválida"})
+		return from, to, false
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Data inicial deve ser anterior à final"})
+		return from, to, false
+	}
+	return from, to, true
+}
+
+// GetReservations lista as reservas pelo início. Aceita carId, userId,
+// status (separados por vírgula) e o período from/to em RFC 3339.
+func GetReservations(reservations *service.ReservationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := repository.ReservationFilter{}
+		if carID := c.Query("carId"); carID != "" {
+			objectID, err := primitive.ObjectIDFromHex(carID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			filter.CarID = &objectID
+		}
+		if userID := c.Query("userId"); userID != "" {
+			objectID, err := primitive.ObjectIDFromHex(userID)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+				return
+			}
+			filter.UserID = &objectID
+		}
+		if status := c.Query("status"); status != "" {
+			filter.Status = strings.Split(status, ",")
+		}
+		if c.Query("from") != "" || c.Query("to") != "" {
+			from, to, ok := reservationPeriod(c)
+			if !ok {
+				return
+			}
+			filter.From, filter.To = &from, &to
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := reservations.List(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar reservas"})
+			return
+		}
+		if result == nil {
+			result = []model.Reservation{}
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func GetReservation(reservations repository.ReservationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		objectID, err := primitive.ObjectIDFromHex(c.Param("reservationId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		reservation, err := reservations.FindByID(ctx, objectID)
+		if err != nil {
+			respondReservationError(c, err, "Erro ao buscar reserva")
+			return
+		}
+
+		c.JSON(http.StatusOK, reservation)
+	}
+}
+
+// GetCarSlots informa quais carros ativos estão livres no período from/to.
+func GetCarSlots(reservations *service.ReservationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from, to, ok := reservationPeriod(c)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		slots, err := reservations.Slots(ctx, from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao consultar disponibilidade"})
+			return
+		}
+
+		c.JSON(http.StatusOK, slots)
+	}
+}
+
+// CreateReservation reserva um carro. Motoristas reservam para si e a
+// reserva aguarda aprovação; administradores podem reservar para qualquer
+// motorista e a reserva já nasce aprovada.
+func CreateReservation(reservations *service.ReservationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		type CreateReservationInput struct {
+			CarID  primitive.ObjectID  `json:"carID" binding:"required"`
+			UserID *primitive.ObjectID `json:"userID"`
+			Start  time.Time           `json:"start" binding:"required"`
+			End    time.Time           `json:"end" binding:"required"`
+			Notes  string              `json:"notes"`
+		}
+
+		var input CreateReservationInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+
+		ok, userType, userId := helper.CurrentUser(c)
+		if !ok {
+			return
+		}
+		createdBy, err := primitive.ObjectIDFromHex(userId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+		if input.UserID == nil {
+			input.UserID = &createdBy
+		} else if userType != "ADMIN" && *input.UserID != createdBy {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Você não tem permissão para reservar para outro motorista"})
+			return
+		}
+
+		var approvedBy *primitive.ObjectID
+		if userType == "ADMIN" {
+			approvedBy = &createdBy
+		}
+
+		reservation := model.Reservation{
+			CarID:     input.CarID,
+			UserID:    *input.UserID,
+			Start:     input.Start,
+			End:       input.End,
+			Notes:     strings.TrimSpace(input.Notes),
+			CreatedBy: createdBy,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := reservations.Create(ctx, &reservation, approvedBy); err != nil {
+			respondReservationError(c, err, "Erro ao criar reserva")
+			return
+		}
+
+		c.JSON(http.StatusCreated, reservation)
+	}
+}
+
+func ApproveReservation(reservations *service.ReservationService) gin.HandlerFunc {
+	return decideReservation(reservations, true)
+}
+
+func RejectReservation(reservations *service.ReservationService) gin.HandlerFunc {
+	return decideReservation(reservations, false)
+}
+
+func decideReservation(reservations *service.ReservationService, approve bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, _, userId := helper.CheckAdminOrUidPermission(c, "")
+		if !ok {
+			return
+		}
+
+		objectID, err := primitive.ObjectIDFromHex(c.Param("reservationId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+		by, err := primitive.ObjectIDFromHex(userId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		var input struct {
+			Note string `json:"note"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+		note := strings.TrimSpace(input.Note)
+		if !approve && note == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Informe o motivo da recusa"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		reservation, err := reservations.Decide(ctx, objectID, approve, note, by)
+		if err != nil {
+			respondReservationError(c, err, "Erro ao atualizar reserva")
+			return
+		}
+
+		c.JSON(http.StatusOK, reservation)
+	}
+}
+
+// CancelReservation cancela uma reserva pendente ou aprovada; apenas o
+// motorista da reserva ou um administrador podem cancelar.
+func CancelReservation(reservations *service.ReservationService, repo repository.ReservationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		objectID, err := primitive.ObjectIDFromHex(c.Param("reservationId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		var input struct {
+			Note string `json:"note"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		current, err := repo.FindByID(ctx, objectID)
+		if err != nil {
+			respondReservationError(c, err, "Erro ao buscar reserva")
+			return
+		}
+
+		ok, _, userId := helper.CheckAdminOrUidPermission(c, current.UserID.Hex())
+		if !ok {
+			return
+		}
+		by, err := primitive.ObjectIDFromHex(userId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		reservation, err := reservations.Cancel(ctx, objectID, strings.TrimSpace(input.Note), by)
+		if err != nil {
+			respondReservationError(c, err, "Erro ao cancelar reserva")
+			return
+		}
+
+		c.JSON(http.StatusOK, reservation)
+	}
+}
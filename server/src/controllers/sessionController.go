@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// currentSessionID devolve a sessão do access token da requisição, se houver.
+func currentSessionID(c *gin.Context) *primitive.ObjectID {
+	value, exists := c.Get("sessionId")
+	if !exists {
+		return nil
+	}
+	sessionID, ok := value.(primitive.ObjectID)
+	if !ok {
+		return nil
+	}
+	return &sessionID
+}
+
+// GetSessions lista as sessões ativas do usuário logado ou, em
+// /session/user/:userId, do usuário informado (apenas administradores ou o
+// próprio usuário). Com all=true inclui as encerradas e as expiradas.
+func GetSessions(sessions *service.SessionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userId := c.Param("userId")
+		if userId == "" {
+			ok, _, currentUserId := helper.CurrentUser(c)
+			if !ok {
+				return
+			}
+			userId = currentUserId
+		} else if ok, _, _ := helper.CheckAdminOrUidPermission(c, userId); !ok {
+			return
+		}
+
+		userID, err := primitive.ObjectIDFromHex(userId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := sessions.List(ctx, userID, currentSessionID(c), c.Query("all") == "true")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar sessões"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// RevokeSession encerra uma sessão do próprio usuário ou, para
+// administradores, de qualquer usuário.
+func RevokeSession(sessions *service.SessionService, repo repository.SessionRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, err := primitive.ObjectIDFromHex(c.Param("sessionId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		session, err := repo.FindByID(ctx, sessionID)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Sessão não encontrada"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar sessão"})
+			}
+			return
+		}
+
+		ok, _, userId := helper.CheckAdminOrUidPermission(c, session.UserID.Hex())
+		if !ok {
+			return
+		}
+		by, err := primitive.ObjectIDFromHex(userId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+			return
+		}
+
+		reason := model.SessionRevokedByUser
+		if by != session.UserID {
+			reason = model.SessionRevokedByAdmin
+		}
+
+		revoked, err := sessions.Revoke(ctx, sessionID, by, reason)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusConflict, gin.H{"error": "Sessão já encerrada"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao encerrar sessão"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, revoked)
+	}
+}
@@ -2,30 +2,29 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"log"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	helper "server/src/helpers"
 	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"golang.org/x/crypto/bcrypt"
 )
 
 func HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), 14)
-	if err != nil {
-		return "", err
-	}
-	return string(hashedPassword), nil
+	return helper.HashPassword(password)
 }
 
-func CreateUser() gin.HandlerFunc {
+func CreateUser(users repository.UserRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -37,17 +36,17 @@ func CreateUser() gin.HandlerFunc {
 			return
 		}
 
-		if len(user.Password) < 5 || len(user.Password) > 60 {
+		if !helper.ValidPasswordLength(user.Password) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Senha inválida"})
 			return
 		}
 
-		count, err := userCollection.CountDocuments(context.Background(), bson.M{"email": user.Email})
+		exists, err := users.ExistsByEmail(context.Background(), user.Email)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		if count > 0 {
+		if exists {
 			c.JSON(http.StatusConflict, gin.H{"error": "usuário já existe"})
 			return
 		}
@@ -61,6 +60,8 @@ func CreateUser() gin.HandlerFunc {
 		user.ID = primitive.NewObjectID()
 		user.IsActive = true
 		user.Password = newPassword
+		user.MustChangePassword = true
+		user.CNHCategories = normalizeCNHCategories(user.CNHCategories)
 		if user.UserType != "ADMIN" && user.UserType != "USER" {
 			user.UserType = "USER"
 		}
@@ -71,7 +72,7 @@ func CreateUser() gin.HandlerFunc {
 			return
 		}
 
-		_, err = userCollection.InsertOne(context.Background(), user)
+		err = users.Create(context.Background(), &user)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -81,7 +82,67 @@ func CreateUser() gin.HandlerFunc {
 	}
 }
 
-func GetUser() gin.HandlerFunc {
+// normalizeCNHCategories deixa as categorias em maiúsculas, sem repetição e
+// em ordem alfabética.
+func normalizeCNHCategories(categories []string) []string {
+	seen := make(map[string]bool)
+	normalized := []string{}
+	for _, category := range categories {
+		category = strings.ToUpper(strings.TrimSpace(category))
+		if category != "" && !seen[category] {
+			seen[category] = true
+			normalized = append(normalized, category)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// normalizeCNHUpdates valida os campos da CNH recebidos na atualização
+// parcial, que não passa pela validação do modelo, e converte a validade para
+// data. cnhExpiresAt nulo remove a validade.
+func normalizeCNHUpdates(updates map[string]interface{}) error {
+	if value, ok := updates["cnh"]; ok {
+		cnh, isString := value.(string)
+		if !isString || !helper.ValidCNH(cnh) {
+			return fmt.Errorf("CNH inválida")
+		}
+	}
+
+	if value, ok := updates["cnhCategories"]; ok {
+		list, isList := value.([]interface{})
+		if !isList {
+			return fmt.Errorf("Categorias da CNH inválidas")
+		}
+		categories := make([]string, 0, len(list))
+		for _, item := range list {
+			category, isString := item.(string)
+			if !isString {
+				return fmt.Errorf("Categorias da CNH inválidas")
+			}
+			categories = append(categories, category)
+		}
+		categories = normalizeCNHCategories(categories)
+		for _, category := range categories {
+			if !strings.Contains("ABCDE", category) || len(category) != 1 {
+				return fmt.Errorf("Categoria da CNH inválida: %s", category)
+			}
+		}
+		updates["cnhCategories"] = categories
+	}
+
+	if value, ok := updates["cnhExpiresAt"]; ok && value != nil {
+		raw, isString := value.(string)
+		expiresAt, err := time.Parse(time.RFC3339, raw)
+		if !isString || err != nil {
+			return fmt.Errorf("Validade da CNH inválida")
+		}
+		updates["cnhExpiresAt"] = expiresAt
+	}
+	return nil
+}
+
+func GetUser(users repository.UserRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -89,15 +150,13 @@ func GetUser() gin.HandlerFunc {
 
 		userId := c.Param("userId")
 
-		var user model.User
-
 		objectId, err := primitive.ObjectIDFromHex(userId)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
 			return
 		}
 
-		err = userCollection.FindOne(context.Background(), bson.M{"_id": objectId}).Decode(&user)
+		user, err := users.FindByID(context.Background(), objectId)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "usuário não encontrado", "erro": err.Error()})
 			return
@@ -109,7 +168,7 @@ func GetUser() gin.HandlerFunc {
 	}
 }
 
-func GetUsers() gin.HandlerFunc {
+func GetUsers(users repository.UserRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -118,48 +177,28 @@ func GetUsers() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		search := c.Query("search")
-		isActive := c.Query("active")
-
-		searchFilter := bson.M{}
-		if search != "" {
-			searchFilter["$or"] = []bson.M{
-				{"name": bson.M{"$regex": search, "$options": "i"}},
-				{"cnh": bson.M{"$regex": search, "$options": "i"}},
-				{"email": bson.M{"$regex": search, "$options": "i"}},
-			}
+		filter := repository.UserFilter{
+			Search:   c.Query("search"),
+			IsActive: c.Query("active") != "false",
 		}
 
-		active := true
-		if isActive == "false" {
-			active = false
-		}
-		searchFilter["isActive"] = active
-
-		var users []model.User
-		cursor, err := userCollection.Find(ctx, searchFilter)
+		result, err := users.List(ctx, filter)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar usuários"})
 			return
 		}
-		defer cursor.Close(ctx)
 
-		for cursor.Next(ctx) {
-			var user model.User
-			cursor.Decode(&user)
-			user.Password = ""
-			users = append(users, user)
-		}
-		if err = cursor.Err(); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar usuários"})
-			return
+		for i := range result {
+			result[i].Password = ""
 		}
 
-		c.JSON(http.StatusOK, users)
+		c.JSON(http.StatusOK, result)
 	}
 }
 
-func UpdateUser() gin.HandlerFunc {
+// UpdateUser aplica as alterações do usuário. Mudar o perfil invalida os
+// access tokens já emitidos e desativar o usuário encerra as suas sessões.
+func UpdateUser(users repository.UserRepository, tokens *service.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -172,7 +211,7 @@ func UpdateUser() gin.HandlerFunc {
 			return
 		}
 
-		var userUpdates bson.M
+		var userUpdates map[string]interface{}
 		if err := c.BindJSON(&userUpdates); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao ler os dados de atualização"})
 			return
@@ -180,11 +219,12 @@ func UpdateUser() gin.HandlerFunc {
 
 		delete(userUpdates, "id")
 		delete(userUpdates, "_id")
+		delete(userUpdates, "tokenVersion")
 
 		if userUpdates["password"] != nil {
 			passStr, ok := userUpdates["password"].(string)
 
-			if !ok || len(passStr) < 5 || len(passStr) > 60 {
+			if !ok || !helper.ValidPasswordLength(passStr) {
 				delete(userUpdates, "password")
 			} else {
 				newPassword, err := HashPassword(passStr)
@@ -193,10 +233,17 @@ func UpdateUser() gin.HandlerFunc {
 					return
 				}
 				userUpdates["password"] = newPassword
+				// A senha definida pelo administrador é provisória.
+				userUpdates["mustChangePassword"] = true
 			}
 
 		}
 
+		if err := normalizeCNHUpdates(userUpdates); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		if userUpdates["userType"] != nil {
 			if userType, ok := userUpdates["userType"].(string); ok {
 				if userType != "ADMIN" && userType != "USER" {
@@ -207,18 +254,22 @@ func UpdateUser() gin.HandlerFunc {
 			}
 		}
 
-		filter := bson.M{"_id": objectId}
-		update := bson.M{"$set": userUpdates}
-
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+		currentUser, err := users.FindByID(ctx, objectId)
+		if err != nil {
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar os dados do usuário"})
+			}
+			return
+		}
 
-		var updatedUser model.User
-		err = userCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updatedUser)
+		updatedUser, err := users.Update(ctx, objectId, userUpdates)
 		if err != nil {
-			if err == mongo.ErrNoDocuments {
+			if err == repository.ErrNotFound {
 				c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
 			} else {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar os dados do usuário"})
@@ -226,13 +277,25 @@ func UpdateUser() gin.HandlerFunc {
 			return
 		}
 
+		if updatedUser.UserType != currentUser.UserType {
+			if err := users.IncrementTokenVersion(ctx, objectId); err != nil {
+				log.Println("Erro ao invalidar tokens do usuário", userId, err)
+			}
+		}
+		if currentUser.IsActive && !updatedUser.IsActive {
+			if err := tokens.RevokeUser(ctx, objectId, model.RefreshTokenRevokedUserDisabled); err != nil {
+				log.Println("Erro ao revogar tokens do usuário", userId, err)
+			}
+		}
+		service.InvalidateUserAccess(objectId)
+
 		updatedUser.Password = ""
 
 		c.JSON(http.StatusOK, updatedUser)
 	}
 }
 
-func DeleteUser() gin.HandlerFunc {
+func DeleteUser(users repository.UserRepository, tokens *service.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -245,15 +308,18 @@ func DeleteUser() gin.HandlerFunc {
 			return
 		}
 
-		result, err := userCollection.DeleteOne(context.Background(), bson.M{"_id": objectId})
+		err = users.Delete(context.Background(), objectId)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar o usuário"})
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar o usuário"})
+			}
 			return
 		}
 
-		if result.DeletedCount == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
-			return
+		if err := tokens.RevokeUser(context.Background(), objectId, model.RefreshTokenRevokedUserDeleted); err != nil {
+			log.Println("Erro ao revogar tokens do usuário", userId, err)
 		}
 
 		c.JSON(http.StatusOK, gin.H{
@@ -262,7 +328,7 @@ func DeleteUser() gin.HandlerFunc {
 	}
 }
 
-func DisableUser() gin.HandlerFunc {
+func DisableUser(users repository.UserRepository, tokens *service.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -278,21 +344,25 @@ func DisableUser() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		result, err := userCollection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"isActive": false}})
+		err = users.SetActive(ctx, objectID, false)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao desativar usuário"})
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao desativar usuário"})
+			}
 			return
 		}
-		if result.MatchedCount == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
-			return
+
+		if err := tokens.RevokeUser(ctx, objectID, model.RefreshTokenRevokedUserDisabled); err != nil {
+			log.Println("Erro ao revogar tokens do usuário", userID, err)
 		}
 
 		c.JSON(http.StatusOK, gin.H{"message": "Usuário desativado com sucesso", "id": userID})
 	}
 }
 
-func EnableUser() gin.HandlerFunc {
+func EnableUser(users repository.UserRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
@@ -308,33 +378,67 @@ func EnableUser() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		result, err := userCollection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"isActive": true}})
+		err = users.SetActive(ctx, objectID, true)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao ativar usuário"})
+			if err == repository.ErrNotFound {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao ativar usuário"})
+			}
+			return
+		}
+		service.InvalidateUserAccess(objectID)
+
+		c.JSON(http.StatusOK, gin.H{"message": "Usuário ativado com sucesso", "id": userID})
+	}
+}
+
+// GetExpiringCNHs lista os motoristas cuja CNH vence nos próximos ?days=
+// dias (padrão em CNH_EXPIRY_WARNING_DAYS), incluindo as vencidas.
+func GetExpiringCNHs(licenses *service.DriverLicenseService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, _, _ := helper.CheckAdminOrUidPermission(c, ""); !ok {
 			return
 		}
-		if result.MatchedCount == 0 {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Usuário não encontrado"})
+
+		days := service.CNHExpiryDays()
+		if value := c.Query("days"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Prazo inválido"})
+				return
+			}
+			days = parsed
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		result, err := licenses.Expiring(ctx, days)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar CNHs a vencer"})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"message": "Usuário ativado com sucesso", "id": userID})
+		c.JSON(http.StatusOK, gin.H{"days": days, "users": result})
 	}
 }
 
-func GetCurrentUser() gin.HandlerFunc {
+func GetCurrentUser(users repository.UserRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userClaims, exists := c.Get("user")
 		if !exists {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
+			return
 		}
 
 		claims, ok := userClaims.(jwt.MapClaims)
 		if !ok {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar token"})
+			return
 		}
 
-		userId := claims["UserId"].(string)
+		userId, _ := claims["UserId"].(string)
 
 		objectID, err := primitive.ObjectIDFromHex(userId)
 		if err != nil {
@@ -345,17 +449,17 @@ func GetCurrentUser() gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		var user model.User
-
-		err = userCollection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&user)
+		user, err := users.FindByID(ctx, objectID)
 		if err != nil {
-			if err == mongo.ErrNoDocuments {
+			if err == repository.ErrNotFound {
 				c.JSON(http.StatusNotFound, gin.H{"error": "Usuário nao encontrado"})
 			} else {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar usuário"})
 			}
+			return
 		}
 
+		user.Password = ""
 		c.JSON(http.StatusOK, user)
 	}
 }
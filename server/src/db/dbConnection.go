@@ -3,29 +3,20 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"time"
 
-	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func DBInstance() *mongo.Client {
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("Warning: .env file not found. Falling back to environment variables.")
-	}
-
-	err = godotenv.Load(".env")
-	if err != nil {
-		log.Fatal("Error loading .env file")
-	}
-
+// DBInstance conecta ao MongoDB indicado em MONGODB_URL. A URL deve apontar
+// para um replica set (ex.: mongodb://host:27017/?replicaSet=rs0), pois as
+// gravações usam transações, que um mongod standalone não suporta.
+func DBInstance() (*mongo.Client, error) {
 	MongoDb := os.Getenv("MONGODB_URL")
 	if MongoDb == "" {
-		log.Fatal("MONGODB_URL não definido no .env")
+		return nil, fmt.Errorf("MONGODB_URL não definido no .env")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -33,21 +24,23 @@ func DBInstance() *mongo.Client {
 
 	client, err := mongo.Connect(ctx, options.Client().ApplyURI(MongoDb))
 	if err != nil {
-		log.Fatal("Erro ao conectar ao MongoDB:", err)
+		return nil, fmt.Errorf("erro ao conectar ao MongoDB: %v", err)
 	}
 
 	err = client.Ping(ctx, nil)
 	if err != nil {
-		log.Fatal("Erro ao verificar conexão com MongoDB:", err)
+		return nil, fmt.Errorf("erro ao verificar conexão com MongoDB: %v", err)
 	}
 
 	fmt.Println("Conectado ao MongoDB")
-	return client
+	return client, nil
 }
 
-var Client *mongo.Client = DBInstance()
+func OpenDatabase(client *mongo.Client) *mongo.Database {
+	return client.Database("Forms")
+}
 
 func OpenCollection(client *mongo.Client, collectionName string) *mongo.Collection {
-	var collection *mongo.Collection = client.Database("Forms").Collection(collectionName)
+	var collection *mongo.Collection = OpenDatabase(client).Collection(collectionName)
 	return collection
 }
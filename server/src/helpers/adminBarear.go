@@ -7,7 +7,9 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func CheckAdminOrUidPermission(c *gin.Context, targetUid string) (bool, string, string) {
+// CurrentUser retorna o tipo e o ID do usuário autenticado. Em caso de falha,
+// a resposta de erro já foi escrita.
+func CurrentUser(c *gin.Context) (bool, string, string) {
 	userClaims, exists := c.Get("user")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário não autenticado"})
@@ -20,8 +22,14 @@ func CheckAdminOrUidPermission(c *gin.Context, targetUid string) (bool, string,
 		return false, "", ""
 	}
 
-	userType := claims["UserType"].(string)
-	userId := claims["UserId"].(string)
+	return true, claims["UserType"].(string), claims["UserId"].(string)
+}
+
+func CheckAdminOrUidPermission(c *gin.Context, targetUid string) (bool, string, string) {
+	ok, userType, userId := CurrentUser(c)
+	if !ok {
+		return false, "", ""
+	}
 
 	if userType != "ADMIN" && userId != targetUid {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Você não tem permissão para acessar este recurso"})
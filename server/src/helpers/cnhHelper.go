@@ -0,0 +1,64 @@
+package helpers
+
+import (
+	"strings"
+)
+
+// ValidCNH confere o número de registro da CNH: 11 dígitos, não todos
+// iguais, com os dois dígitos verificadores calculados pelo módulo 11 do
+// Denatran.
+func ValidCNH(cnh string) bool {
+	if len(cnh) != 11 || strings.Count(cnh, cnh[:1]) == 11 {
+		return false
+	}
+
+	digits := make([]int, 11)
+	for i, r := range cnh {
+		if r < '0' || r > '9' {
+			return false
+		}
+		digits[i] = int(r - '0')
+	}
+
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += digits[i] * (9 - i)
+	}
+	first, discount := sum%11, 0
+	if first >= 10 {
+		first, discount = 0, 2
+	}
+
+	sum = 0
+	for i := 0; i < 9; i++ {
+		sum += digits[i] * (i + 1)
+	}
+	second := sum % 11
+	if second >= 10 {
+		second = 0
+	} else {
+		second -= discount
+	}
+
+	return digits[9] == first && digits[10] == second
+}
+
+// cnhCategoryRank ordena as categorias de veículos de quatro rodas: quem tem
+// uma delas pode conduzir as de posição inferior. A categoria A é à parte.
+var cnhCategoryRank = map[string]int{"B": 1, "C": 2, "D": 3, "E": 4}
+
+// CNHCovers indica se as categorias da CNH permitem conduzir um veículo da
+// categoria exigida.
+func CNHCovers(categories []string, required string) bool {
+	for _, category := range categories {
+		if category == required {
+			return true
+		}
+		held, ok := cnhCategoryRank[category]
+		needed, needsRank := cnhCategoryRank[required]
+		if ok && needsRank && held >= needed {
+			return true
+		}
+	}
+	return false
+}
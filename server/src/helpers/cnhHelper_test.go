@@ -0,0 +1,63 @@
+package helpers
+
+import "testing"
+
+func TestValidCNH(t *testing.T) {
+	tests := []struct {
+		name string
+		cnh  string
+		want bool
+	}{
+		{"dígitos comuns", "29141777683", true},
+		{"seed do administrador", "12345678900", true},
+		{"primeiro dígito 10 vira 0 e desconta 2 do segundo", "73662585100", true},
+		{"segundo dígito 10 vira 0", "70499962280", true},
+		{"ambos os dígitos 10", "54187955200", true},
+		{"primeiro dígito errado", "29141777693", false},
+		{"segundo dígito errado", "29141777684", false},
+		{"desconto não aplicado", "73662585102", false},
+		{"todos iguais", "00000000000", false},
+		{"todos iguais diferentes de zero", "11111111111", false},
+		{"curto", "2914177768", false},
+		{"longo", "291417776830", false},
+		{"com letra", "2914177768X", false},
+		{"com pontuação", "291.417.776-83", false},
+		{"vazio", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidCNH(tt.cnh); got != tt.want {
+				t.Errorf("ValidCNH(%q) = %v, esperado %v", tt.cnh, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCNHCovers(t *testing.T) {
+	tests := []struct {
+		categories []string
+		required   string
+		want       bool
+	}{
+		{[]string{"B"}, "B", true},
+		{[]string{"B"}, "C", false},
+		{[]string{"C"}, "B", true},
+		{[]string{"C"}, "D", false},
+		{[]string{"D"}, "C", true},
+		{[]string{"D"}, "E", false},
+		{[]string{"E"}, "B", true},
+		{[]string{"E"}, "D", true},
+		{[]string{"A"}, "A", true},
+		{[]string{"A"}, "B", false},
+		{[]string{"E"}, "A", false},
+		{[]string{"A", "C"}, "B", true},
+		{[]string{"A", "B"}, "C", false},
+		{nil, "B", false},
+		{[]string{"B"}, "", false},
+	}
+	for _, tt := range tests {
+		if got := CNHCovers(tt.categories, tt.required); got != tt.want {
+			t.Errorf("CNHCovers(%v, %q) = %v, esperado %v", tt.categories, tt.required, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Limites de tamanho aceitos para as senhas dos usuários.
+const (
+	MinPasswordLength = 5
+	MaxPasswordLength = 60
+)
+
+func ValidPasswordLength(password string) bool {
+	return len(password) >= MinPasswordLength && len(password) <= MaxPasswordLength
+}
+
+func HashPassword(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedPassword), nil
+}
+
+// CheckPassword confere a senha informada com o hash guardado.
+func CheckPassword(password, hash string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"errors"
 	"log"
 	"os"
 	"time"
@@ -8,54 +9,83 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	AccessTokenDuration  = time.Hour * 24
+	RefreshTokenDuration = time.Hour * 24 * 60
+
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
 type SignedDetails struct {
-	UserId   string
-	Name     string
-	UserType string
+	UserId       string
+	Name         string
+	UserType     string
+	TokenType    string
+	SessionId    string `json:",omitempty"`
+	TokenVersion int    `json:",omitempty"`
 	jwt.RegisteredClaims
 }
 
-var SECRET_KEY string = os.Getenv("SECRET_KEY")
-
-func GenerateTokens(userId string, name string, userType string, keepLogged bool) (signedAccessToken string, signedRefreshToken string, err error) {
-	accessTokenDuration := time.Hour * 24
-	refreshTokenDuration := time.Hour * 24 * 60
-
-	accessClaims := &SignedDetails{
-		UserId:   userId,
-		Name:     name,
-		UserType: userType,
+func GenerateAccessToken(userId string, name string, userType string, sessionId string, tokenVersion int) (string, error) {
+	claims := &SignedDetails{
+		UserId:       userId,
+		Name:         name,
+		UserType:     userType,
+		TokenType:    TokenTypeAccess,
+		SessionId:    sessionId,
+		TokenVersion: tokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTokenDuration)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenDuration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	refreshClaims := &SignedDetails{
-		UserId:   userId,
-		Name:     name,
-		UserType: userType,
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("SECRET_KEY")))
+	if err != nil {
+		log.Println("Erro ao criar Access Token:", err)
+		return "", err
+	}
+	return accessToken, nil
+}
+
+// GenerateRefreshToken assina o refresh token registrado no banco com o id
+// tokenId, que vai no claim jti para permitir rotação e revogação.
+func GenerateRefreshToken(userId string, tokenId string, expiresAt time.Time) (string, error) {
+	claims := &SignedDetails{
+		UserId:    userId,
+		TokenType: TokenTypeRefresh,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(refreshTokenDuration)),
+			ID:        tokenId,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims).SignedString([]byte(SECRET_KEY))
+	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("SECRET_KEY")))
 	if err != nil {
-		log.Println("Erro ao criar Access Token:", err)
-		return "", "", err
+		log.Println("Erro ao criar Refresh Token:", err)
+		return "", err
 	}
+	return refreshToken, nil
+}
 
-	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString([]byte(SECRET_KEY))
+// ParseToken valida a assinatura HMAC e a expiração do token e devolve seus
+// claims.
+func ParseToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrTokenMalformed
+		}
+		return []byte(os.Getenv("SECRET_KEY")), nil
+	})
 	if err != nil {
-		log.Println("Erro ao criar Refresh Token:", err)
-		return "", "", err
+		return nil, err
 	}
 
-	if keepLogged {
-		return accessToken, refreshToken, nil
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("token inválido")
 	}
-
-	return accessToken, "", nil
+	return claims, nil
 }
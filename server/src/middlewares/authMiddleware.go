@@ -1,17 +1,30 @@
 package middlewares
 
 import (
+	"context"
 	"log"
 	"net/http"
-	"os"
+	"time"
+
+	helper "server/src/helpers"
+	service "server/src/services"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-var SECRET_KEY string = os.Getenv("SECRET_KEY")
+// passwordChangeAllowed são as rotas liberadas ao usuário que ainda precisa
+// trocar a senha provisória.
+var passwordChangeAllowed = map[string]bool{
+	"/user/current":         true,
+	"/user/change-password": true,
+}
 
-func Authenticate() gin.HandlerFunc {
+// Authenticate valida o access token e recusa a requisição se o usuário
+// tiver sido desativado, se o perfil tiver mudado depois da emissão do token
+// ou se a sessão tiver sido encerrada. Enquanto o usuário não trocar a senha
+// provisória, só as rotas de passwordChangeAllowed são aceitas.
+func Authenticate(access *service.AccessService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		cookieToken, _ := c.Cookie("accessToken")
@@ -33,13 +46,7 @@ func Authenticate() gin.HandlerFunc {
 			tokenString = cookieToken
 		}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrTokenMalformed
-			}
-			return []byte(SECRET_KEY), nil
-		})
-
+		claims, err := helper.ParseToken(tokenString)
 		if err != nil {
 			log.Println("Erro ao validar o token:", err)
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido ou expirado"})
@@ -47,14 +54,60 @@ func Authenticate() gin.HandlerFunc {
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			c.Set("user", claims)
-		} else {
+		// Refresh tokens só servem para /auth/refresh-token.
+		if claims["TokenType"] == helper.TokenTypeRefresh {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
 			c.Abort()
 			return
 		}
 
+		userId, _ := claims["UserId"].(string)
+		userID, err := primitive.ObjectIDFromHex(userId)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+			c.Abort()
+			return
+		}
+		var sessionID *primitive.ObjectID
+		if sessionId, _ := claims["SessionId"].(string); sessionId != "" {
+			id, err := primitive.ObjectIDFromHex(sessionId)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+				c.Abort()
+				return
+			}
+			sessionID = &id
+		}
+		tokenVersion, _ := claims["TokenVersion"].(float64)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err = access.Authorize(ctx, userID, int(tokenVersion), sessionID, c.ClientIP())
+		if err == service.ErrPasswordChangeRequired && passwordChangeAllowed[c.Request.URL.Path] {
+			err = nil
+		}
+		if err != nil {
+			switch err {
+			case service.ErrUserDisabled:
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuário desativado"})
+			case service.ErrTokenOutdated:
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Permissões alteradas; faça login novamente"})
+			case service.ErrSessionRevoked:
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Sessão encerrada"})
+			case service.ErrPasswordChangeRequired:
+				c.JSON(http.StatusForbidden, gin.H{"error": "Troque a sua senha para continuar", "mustChangePassword": true})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao validar sessão"})
+			}
+			c.Abort()
+			return
+		}
+		if sessionID != nil {
+			c.Set("sessionId", *sessionID)
+		}
+		c.Set("user", claims)
+
 		c.Next()
 	}
 }
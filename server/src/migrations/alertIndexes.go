@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// alertIndexes garante no máximo um alerta ativo por carro e tipo e cobre a
+// listagem de alertas por status.
+var alertIndexes = Migration{
+	Version:     6,
+	Description: "índices da coleção de alertas",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("alerts").Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{
+				Keys: bson.D{{Key: "carID", Value: 1}, {Key: "type", Value: 1}},
+				Options: options.Index().
+					SetName("unique_active_alert_per_car_type").
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"active": true}),
+			},
+			{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}},
+				Options: options.Index().SetName("status_createdAt_desc"),
+			},
+		})
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		return dropIndexes(ctx, db.Collection("alerts"), "unique_active_alert_per_car_type", "status_createdAt_desc")
+	},
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// backfillCarEntryKMDriven calcula kmDriven das entradas encerradas que não o
+// possuem, a partir dos KMs de check-in e check-out. A reversão não apaga os
+// valores calculados, pois não há como distinguir os preenchidos aqui.
+var backfillCarEntryKMDriven = Migration{
+	Version:     4,
+	Description: "preenche kmDriven de entradas encerradas",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("carEntries").UpdateMany(ctx,
+			bson.M{"checkOut": bson.M{"$ne": nil}, "kmDriven": nil},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{
+				"kmDriven": bson.M{"$subtract": []interface{}{"$checkOut.actualKM", "$checkIn.actualKM"}},
+			}}}},
+		)
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		return nil
+	},
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// backfillOpenEntryDriverLock preenche a trava de motorista das entradas
+// abertas criadas antes da sua existência, que a migração 1 deixou apenas com
+// a trava de carro. Quando o motorista tem mais de uma entrada aberta, só a
+// mais recente recebe a trava; as demais são listadas no log para que um
+// administrador as encerre. Nada é feito com SINGLE_CAR_PER_DRIVER=false. A
+// reversão não remove as travas, pois não há como distinguir as preenchidas
+// aqui.
+var backfillOpenEntryDriverLock = Migration{
+	Version:     17,
+	Description: "preenche a trava de motorista das entradas abertas",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		if os.Getenv("SINGLE_CAR_PER_DRIVER") == "false" {
+			return nil
+		}
+		collection := db.Collection("carEntries")
+
+		locked := make(map[primitive.ObjectID]bool)
+		lockedIDs, err := collection.Distinct(ctx, "activeUserID", bson.M{"activeUserID": bson.M{"$exists": true}})
+		if err != nil {
+			return err
+		}
+		for _, id := range lockedIDs {
+			if userID, ok := id.(primitive.ObjectID); ok {
+				locked[userID] = true
+			}
+		}
+
+		opts := options.Find().
+			SetSort(bson.D{{Key: "startedAt", Value: -1}}).
+			SetProjection(bson.M{"_id": 1, "userID": 1})
+		cursor, err := collection.Find(ctx, bson.M{"checkOut": nil, "activeUserID": bson.M{"$exists": false}}, opts)
+		if err != nil {
+			return err
+		}
+		var entries []struct {
+			ID     primitive.ObjectID `bson:"_id"`
+			UserID primitive.ObjectID `bson:"userID"`
+		}
+		if err := cursor.All(ctx, &entries); err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if locked[entry.UserID] {
+				log.Printf("Entrada %s deixada sem trava: o motorista %s já tem outra entrada aberta", entry.ID.Hex(), entry.UserID.Hex())
+				continue
+			}
+			_, err := collection.UpdateOne(ctx, bson.M{"_id": entry.ID}, bson.M{"$set": bson.M{"activeUserID": entry.UserID}})
+			if err != nil {
+				return err
+			}
+			locked[entry.UserID] = true
+		}
+		return nil
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		return nil
+	},
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// carDocumentIndexes cobre a busca do documento vigente de cada carro e tipo
+// e a listagem dos documentos por vencimento.
+var carDocumentIndexes = Migration{
+	Version:     10,
+	Description: "índices dos documentos dos carros",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("carDocuments").Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "carID", Value: 1}, {Key: "type", Value: 1}, {Key: "expiresAt", Value: -1}},
+				Options: options.Index().SetName("carID_type_expiresAt_desc"),
+			},
+			{
+				Keys:    bson.D{{Key: "expiresAt", Value: -1}},
+				Options: options.Index().SetName("expiresAt_desc"),
+			},
+		})
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		return dropIndexes(ctx, db.Collection("carDocuments"), "carID_type_expiresAt_desc", "expiresAt_desc")
+	},
+}
@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// checklistTemplateVersions cria o índice único de versões por modelo e
+// registra como versão 1 os modelos criados antes do versionamento.
+var checklistTemplateVersions = Migration{
+	Version:     7,
+	Description: "versionamento dos modelos de checklist",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		versions := db.Collection("checklistTemplateVersions")
+		_, err := versions.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "templateID", Value: 1}, {Key: "version", Value: -1}},
+			Options: options.Index().SetName("unique_templateID_version").SetUnique(true),
+		})
+		if err != nil {
+			return err
+		}
+
+		templates := db.Collection("checklistTemplates")
+		cursor, err := templates.Find(ctx, bson.M{"version": bson.M{"$exists": false}})
+		if err != nil {
+			return err
+		}
+		var legacy []model.ChecklistTemplate
+		if err := cursor.All(ctx, &legacy); err != nil {
+			return err
+		}
+
+		for _, template := range legacy {
+			_, err := versions.InsertOne(ctx, model.ChecklistTemplateVersion{
+				ID:         primitive.NewObjectID(),
+				TemplateID: template.ID,
+				Version:    1,
+				Name:       template.Name,
+				Stages:     template.Stages,
+				Items:      template.Items,
+				CreatedAt:  time.Now(),
+			})
+			if err != nil && !mongo.IsDuplicateKeyError(err) {
+				return err
+			}
+			_, err = templates.UpdateOne(ctx, bson.M{"_id": template.ID}, bson.M{"$set": bson.M{"version": 1}})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		return dropIndexes(ctx, db.Collection("checklistTemplateVersions"), "unique_templateID_version")
+	},
+}
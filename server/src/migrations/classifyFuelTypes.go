@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"context"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// classifyFuelTypes preenche o tipo dos registros de combustível antigos. Os
+// créditos gerados por exclusão de entradas não eram distinguíveis de
+// abastecimentos e, por isso, são classificados como abastecimento.
+var classifyFuelTypes = Migration{
+	Version:     5,
+	Description: "classifica registros de combustível por tipo",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		collection := db.Collection("fuels")
+
+		_, err := collection.UpdateMany(ctx,
+			bson.M{"type": bson.M{"$exists": false}, "kmDriven": bson.M{"$gt": 0}},
+			bson.M{"$set": bson.M{"type": model.FuelTypeCheckOut}},
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = collection.UpdateMany(ctx,
+			bson.M{"type": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"type": model.FuelTypeRefuel}},
+		)
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("fuels").UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"type": ""}})
+		return err
+	},
+}
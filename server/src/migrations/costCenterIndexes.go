@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// costCenterIndexes garante códigos únicos no catálogo de centros de custo e
+// cobre o filtro e a soma de km das entradas por centro de custo.
+var costCenterIndexes = Migration{
+	Version:     12,
+	Description: "índices dos centros de custo",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("costCenters").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetName("code_unique").SetUnique(true),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection("carEntries").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "checkIn.costCenter", Value: 1}, {Key: "startedAt", Value: -1}},
+			Options: options.Index().SetName("checkIn.costCenter_startedAt"),
+		})
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		if err := dropIndexes(ctx, db.Collection("costCenters"), "code_unique"); err != nil {
+			return err
+		}
+		return dropIndexes(ctx, db.Collection("carEntries"), "checkIn.costCenter_startedAt")
+	},
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// damageIndexes cobre a listagem de avarias por carro e por status e a busca
+// das avarias relatadas em uma entrada.
+var damageIndexes = Migration{
+	Version:     8,
+	Description: "índices da coleção de avarias",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("damages").Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "carID", Value: 1}, {Key: "createdAt", Value: -1}},
+				Options: options.Index().SetName("carID_createdAt_desc"),
+			},
+			{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}},
+				Options: options.Index().SetName("status_createdAt_desc"),
+			},
+			{
+				Keys:    bson.D{{Key: "carEntryID", Value: 1}},
+				Options: options.Index().SetName("carEntryID"),
+			},
+		})
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		return dropIndexes(ctx, db.Collection("damages"), "carID_createdAt_desc", "status_createdAt_desc", "carEntryID")
+	},
+}
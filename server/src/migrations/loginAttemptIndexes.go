@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// loginAttemptRetentionSeconds é por quanto tempo as tentativas de login
+// ficam disponíveis para consulta: 90 dias.
+const loginAttemptRetentionSeconds = 90 * 24 * 60 * 60
+
+// loginAttemptIndexes cobre a consulta das tentativas de login por email e
+// por IP, descarta as tentativas antigas e remove os bloqueios de login
+// quando expiram.
+var loginAttemptIndexes = Migration{
+	Version:     15,
+	Description: "índices das tentativas e bloqueios de login",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("loginAttempts").Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "email", Value: 1}, {Key: "createdAt", Value: -1}},
+				Options: options.Index().SetName("email_createdAt"),
+			},
+			{
+				Keys:    bson.D{{Key: "ip", Value: 1}, {Key: "createdAt", Value: -1}},
+				Options: options.Index().SetName("ip_createdAt"),
+			},
+			{
+				Keys:    bson.D{{Key: "createdAt", Value: 1}},
+				Options: options.Index().SetName("createdAt_ttl").SetExpireAfterSeconds(loginAttemptRetentionSeconds),
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection("loginThrottles").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0),
+		})
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		if err := dropIndexes(ctx, db.Collection("loginAttempts"), "email_createdAt", "ip_createdAt", "createdAt_ttl"); err != nil {
+			return err
+		}
+		return dropIndexes(ctx, db.Collection("loginThrottles"), "expiresAt_ttl")
+	},
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maintenanceIndexes cobre a busca da última manutenção de cada carro e plano
+// e das manutenções em andamento.
+var maintenanceIndexes = Migration{
+	Version:     9,
+	Description: "índices das manutenções",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("maintenanceRecords").Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "carID", Value: 1}, {Key: "planID", Value: 1}, {Key: "completedAt", Value: -1}},
+				Options: options.Index().SetName("carID_planID_completedAt_desc"),
+			},
+			{
+				Keys:    bson.D{{Key: "completedAt", Value: 1}},
+				Options: options.Index().SetName("completedAt"),
+			},
+		})
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		return dropIndexes(ctx, db.Collection("maintenanceRecords"), "carID_planID_completedAt_desc", "completedAt")
+	},
+}
@@ -0,0 +1,169 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+	Down        func(ctx context.Context, db *mongo.Database) error
+}
+
+type AppliedMigration struct {
+	Version     int       `bson:"_id" json:"version"`
+	Description string    `bson:"description" json:"description"`
+	AppliedAt   time.Time `bson:"appliedAt" json:"appliedAt"`
+}
+
+type MigrationStatus struct {
+	Version     int        `json:"version"`
+	Description string     `json:"description"`
+	AppliedAt   *time.Time `json:"appliedAt"`
+}
+
+// all lista as migrações em ordem de versão. Novas migrações devem ser
+// adicionadas ao final, com versão maior que a última.
+var all = []Migration{
+	openCarEntryIndexes,
+	queryIndexes,
+	renameFuelFields,
+	backfillCarEntryKMDriven,
+	classifyFuelTypes,
+	alertIndexes,
+	checklistTemplateVersions,
+	damageIndexes,
+	maintenanceIndexes,
+	carDocumentIndexes,
+	reservationIndexes,
+	costCenterIndexes,
+	refreshTokenIndexes,
+	sessionIndexes,
+	loginAttemptIndexes,
+	passwordResetIndexes,
+	backfillOpenEntryDriverLock,
+}
+
+const collectionName = "migrations"
+
+func applied(ctx context.Context, db *mongo.Database) (map[int]AppliedMigration, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	cursor, err := db.Collection(collectionName).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []AppliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]AppliedMigration, len(records))
+	for _, record := range records {
+		result[record.Version] = record
+	}
+	return result, nil
+}
+
+func Pending(ctx context.Context, db *mongo.Database) ([]Migration, error) {
+	done, err := applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range all {
+		if _, ok := done[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+func Status(ctx context.Context, db *mongo.Database) ([]MigrationStatus, error) {
+	done, err := applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var status []MigrationStatus
+	for _, migration := range all {
+		item := MigrationStatus{Version: migration.Version, Description: migration.Description}
+		if record, ok := done[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			item.AppliedAt = &appliedAt
+		}
+		status = append(status, item)
+	}
+	return status, nil
+}
+
+// Up aplica, em ordem, todas as migrações pendentes e retorna as aplicadas.
+// Para na primeira falha, mantendo registradas as que já foram concluídas.
+func Up(ctx context.Context, db *mongo.Database) ([]Migration, error) {
+	pending, err := Pending(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range pending {
+		if err := migration.Up(ctx, db); err != nil {
+			return done, fmt.Errorf("migração %d (%s) falhou: %v", migration.Version, migration.Description, err)
+		}
+
+		record := AppliedMigration{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now(),
+		}
+		if _, err := db.Collection(collectionName).InsertOne(ctx, record); err != nil {
+			return done, fmt.Errorf("erro ao registrar migração %d: %v", migration.Version, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down desfaz a última migração aplicada. Retorna nil se nenhuma foi aplicada.
+func Down(ctx context.Context, db *mongo.Database) (*Migration, error) {
+	done, err := applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(all) - 1; i >= 0; i-- {
+		migration := all[i]
+		if _, ok := done[migration.Version]; !ok {
+			continue
+		}
+
+		if err := migration.Down(ctx, db); err != nil {
+			return nil, fmt.Errorf("reversão da migração %d (%s) falhou: %v", migration.Version, migration.Description, err)
+		}
+		if _, err := db.Collection(collectionName).DeleteOne(ctx, bson.M{"_id": migration.Version}); err != nil {
+			return nil, fmt.Errorf("erro ao remover registro da migração %d: %v", migration.Version, err)
+		}
+		return &migration, nil
+	}
+	return nil, nil
+}
+
+func dropIndexes(ctx context.Context, collection *mongo.Collection, names ...string) error {
+	for _, name := range names {
+		if _, err := collection.Indexes().DropOne(ctx, name); err != nil {
+			if cmdErr, ok := err.(mongo.CommandError); ok && cmdErr.Name == "IndexNotFound" {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
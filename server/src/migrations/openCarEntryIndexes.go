@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// openCarEntryIndexes preenche a trava de carro das entradas abertas criadas
+// antes da sua existência e cria os índices únicos parciais que garantem uma
+// única entrada aberta por carro e por motorista.
+var openCarEntryIndexes = Migration{
+	Version:     1,
+	Description: "índices de entrada aberta por carro e por motorista",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		collection := db.Collection("carEntries")
+
+		_, err := collection.UpdateMany(ctx,
+			bson.M{"checkOut": nil, "activeCarID": bson.M{"$exists": false}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{"activeCarID": "$carID"}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{
+				Keys: bson.D{{Key: "activeCarID", Value: 1}},
+				Options: options.Index().
+					SetName("unique_open_entry_per_car").
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"activeCarID": bson.M{"$exists": true}}),
+			},
+			{
+				Keys: bson.D{{Key: "activeUserID", Value: 1}},
+				Options: options.Index().
+					SetName("unique_open_entry_per_user").
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"activeUserID": bson.M{"$exists": true}}),
+			},
+		})
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		return dropIndexes(ctx, db.Collection("carEntries"), "unique_open_entry_per_car", "unique_open_entry_per_user")
+	},
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// passwordResetIndexes garante a busca pelo token, cobre o último pedido de
+// cada usuário e remove os pedidos do banco quando expiram.
+var passwordResetIndexes = Migration{
+	Version:     16,
+	Description: "índices dos pedidos de redefinição de senha",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("passwordResets").Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+				Options: options.Index().SetName("tokenHash_unique").SetUnique(true),
+			},
+			{
+				Keys:    bson.D{{Key: "userID", Value: 1}, {Key: "createdAt", Value: -1}},
+				Options: options.Index().SetName("userID_createdAt"),
+			},
+			{
+				Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+				Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0),
+			},
+		})
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		return dropIndexes(ctx, db.Collection("passwordResets"), "tokenHash_unique", "userID_createdAt", "expiresAt_ttl")
+	},
+}
@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// queryIndexes cobre as consultas mais frequentes: listagem de entradas por
+// startedAt, último registro de combustível por carro e login por email.
+var queryIndexes = Migration{
+	Version:     2,
+	Description: "índices de consulta de entradas, combustível e usuários",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("carEntries").Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "startedAt", Value: -1}},
+				Options: options.Index().SetName("startedAt_desc"),
+			},
+			{
+				Keys:    bson.D{{Key: "carID", Value: 1}, {Key: "startedAt", Value: -1}},
+				Options: options.Index().SetName("carID_startedAt_desc"),
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection("fuels").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "carID", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("carID_createdAt_desc"),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = db.Collection("users").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetName("unique_email").SetUnique(true),
+		})
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		if err := dropIndexes(ctx, db.Collection("carEntries"), "startedAt_desc", "carID_startedAt_desc"); err != nil {
+			return err
+		}
+		if err := dropIndexes(ctx, db.Collection("fuels"), "carID_createdAt_desc"); err != nil {
+			return err
+		}
+		return dropIndexes(ctx, db.Collection("users"), "unique_email")
+	},
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// refreshTokenIndexes cobre a revogação por família e por usuário e remove
+// os refresh tokens do banco quando expiram.
+var refreshTokenIndexes = Migration{
+	Version:     13,
+	Description: "índices dos refresh tokens",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("refreshTokens").Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "familyID", Value: 1}},
+				Options: options.Index().SetName("familyID"),
+			},
+			{
+				Keys:    bson.D{{Key: "userID", Value: 1}},
+				Options: options.Index().SetName("userID"),
+			},
+			{
+				Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+				Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0),
+			},
+		})
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		return dropIndexes(ctx, db.Collection("refreshTokens"), "familyID", "userID", "expiresAt_ttl")
+	},
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// renameFuelFields corrige os nomes legados previusFuel e KMDriven dos
+// registros de combustível.
+var renameFuelFields = Migration{
+	Version:     3,
+	Description: "renomeia previusFuel e KMDriven em fuels",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("fuels").UpdateMany(ctx, bson.M{}, bson.M{
+			"$rename": bson.M{
+				"previusFuel": "previousFuel",
+				"KMDriven":    "kmDriven",
+			},
+		})
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("fuels").UpdateMany(ctx, bson.M{}, bson.M{
+			"$rename": bson.M{
+				"previousFuel": "previusFuel",
+				"kmDriven":     "KMDriven",
+			},
+		})
+		return err
+	},
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// reservationIndexes cobre a busca de reservas sobrepostas de um carro, a
+// agenda de cada motorista e a marcação de reservas não utilizadas.
+var reservationIndexes = Migration{
+	Version:     11,
+	Description: "índices das reservas",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("reservations").Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "carID", Value: 1}, {Key: "start", Value: 1}, {Key: "end", Value: 1}},
+				Options: options.Index().SetName("carID_start_end"),
+			},
+			{
+				Keys:    bson.D{{Key: "userID", Value: 1}, {Key: "start", Value: 1}},
+				Options: options.Index().SetName("userID_start"),
+			},
+			{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "start", Value: 1}},
+				Options: options.Index().SetName("status_start"),
+			},
+		})
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		return dropIndexes(ctx, db.Collection("reservations"), "carID_start_end", "userID_start", "status_start")
+	},
+}
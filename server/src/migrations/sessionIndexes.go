@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sessionIndexes cobre a listagem das sessões de um usuário e remove as
+// sessões do banco quando expiram.
+var sessionIndexes = Migration{
+	Version:     14,
+	Description: "índices das sessões",
+	Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("sessions").Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "userID", Value: 1}, {Key: "lastSeenAt", Value: -1}},
+				Options: options.Index().SetName("userID_lastSeenAt"),
+			},
+			{
+				Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+				Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0),
+			},
+		})
+		return err
+	},
+	Down: func(ctx context.Context, db *mongo.Database) error {
+		return dropIndexes(ctx, db.Collection("sessions"), "userID_lastSeenAt", "expiresAt_ttl")
+	},
+}
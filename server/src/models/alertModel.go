@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	AlertTypeLowFuel      = "low_fuel"
+	AlertTypeNegativeFuel = "negative_fuel"
+	AlertTypeOverCapacity = "over_capacity"
+
+	AlertStatusOpen         = "open"
+	AlertStatusAcknowledged = "acknowledged"
+	AlertStatusResolved     = "resolved"
+)
+
+type Alert struct {
+	ID        primitive.ObjectID  `bson:"_id" json:"id"`
+	CarID     primitive.ObjectID  `bson:"carID" json:"carID"`
+	Type      string              `bson:"type" json:"type"`
+	Status    string              `bson:"status" json:"status"`
+	Message   string              `bson:"message" json:"message"`
+	FuelLevel float64             `bson:"fuelLevel" json:"fuelLevel"`
+	Capacity  int                 `bson:"capacity" json:"capacity"`
+	Threshold float64             `bson:"threshold,omitempty" json:"threshold,omitempty"`
+	Liters    float64             `bson:"liters,omitempty" json:"liters,omitempty"`
+	FuelID    *primitive.ObjectID `bson:"fuelID,omitempty" json:"fuelID,omitempty"`
+	CreatedAt time.Time           `bson:"createdAt" json:"createdAt"`
+
+	AcknowledgedAt *time.Time          `bson:"acknowledgedAt,omitempty" json:"acknowledgedAt,omitempty"`
+	AcknowledgedBy *primitive.ObjectID `bson:"acknowledgedBy,omitempty" json:"acknowledgedBy,omitempty"`
+	ResolvedAt     *time.Time          `bson:"resolvedAt,omitempty" json:"resolvedAt,omitempty"`
+	ResolvedBy     *primitive.ObjectID `bson:"resolvedBy,omitempty" json:"resolvedBy,omitempty"`
+
+	// Active marca alertas ainda não resolvidos; um índice único parcial
+	// garante no máximo um alerta ativo por carro e tipo.
+	Active bool `bson:"active,omitempty" json:"-"`
+}
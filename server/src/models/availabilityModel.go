@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CarAvailable     = "available"
+	CarInUse         = "in_use"
+	CarInMaintenance = "in_maintenance"
+	CarBlocked       = "blocked"
+
+	BlockReasonDamage      = "damage"
+	BlockReasonMaintenance = "maintenance"
+	BlockReasonDocument    = "document"
+)
+
+// CarAvailability é a situação do carro calculada a partir das entradas
+// abertas e das pendências que impedem o uso (Reasons).
+type CarAvailability struct {
+	CarID   primitive.ObjectID `json:"carID"`
+	Plate   string             `json:"plate"`
+	Status  string             `json:"status"`
+	Reasons []BlockReason      `json:"reasons"`
+}
+
+type BlockReason struct {
+	Kind    string              `bson:"kind" json:"kind"`
+	Message string              `bson:"message" json:"message"`
+	RefID   *primitive.ObjectID `bson:"refID,omitempty" json:"refID,omitempty"`
+}
+
+// AvailabilityOverride registra a liberação, por um administrador, de um
+// check-in em carro bloqueado e os motivos do bloqueio naquele momento.
+type AvailabilityOverride struct {
+	Reason  string             `bson:"reason" json:"reason" validate:"required,max=500"`
+	By      primitive.ObjectID `bson:"by" json:"by"`
+	At      time.Time          `bson:"at" json:"at"`
+	Blocked []BlockReason      `bson:"blocked" json:"blocked"`
+}
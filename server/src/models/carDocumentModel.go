@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CarDocumentLicensing          = "licensing"
+	CarDocumentMandatoryInsurance = "mandatory_insurance"
+	CarDocumentInsurance          = "insurance"
+	CarDocumentInspection         = "inspection"
+)
+
+// CarDocument é um documento do carro: licenciamento (CRLV), seguro
+// obrigatório, seguro particular ou certificado de inspeção. Renovações são
+// registradas como novos documentos; vale o de vencimento mais distante de
+// cada tipo.
+type CarDocument struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	CarID     primitive.ObjectID `bson:"carID" json:"carID" validate:"required"`
+	Type      string             `bson:"type" json:"type" validate:"required,oneof=licensing mandatory_insurance insurance inspection"`
+	Number    string             `bson:"number" json:"number" validate:"required,max=50"`
+	IssuedAt  time.Time          `bson:"issuedAt" json:"issuedAt" validate:"required"`
+	ExpiresAt time.Time          `bson:"expiresAt" json:"expiresAt" validate:"required,gtfield=IssuedAt"`
+	Files     []string           `bson:"files,omitempty" json:"files"`
+	Notes     string             `bson:"notes,omitempty" json:"notes,omitempty" validate:"max=500"`
+	CreatedBy primitive.ObjectID `bson:"createdBy" json:"createdBy"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// CarDocumentExpiry é o documento vigente de um tipo que vence dentro do
+// prazo consultado. DaysLeft é negativo para documentos vencidos.
+type CarDocumentExpiry struct {
+	Document CarDocument `json:"document"`
+	Plate    string      `json:"plate"`
+	DaysLeft int         `json:"daysLeft"`
+	Expired  bool        `json:"expired"`
+}
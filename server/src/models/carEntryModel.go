@@ -17,21 +17,49 @@ type CarEntry struct {
 	KMDriven   *float64           `bson:"kmDriven" json:"kmDriven"`
 	EndedAt    *time.Time         `bson:"endedAt" json:"endedAt"`
 	User       *User              `bson:"user,omitempty" json:"user"`
+
+	AvailabilityOverride *AvailabilityOverride `bson:"availabilityOverride,omitempty" json:"availabilityOverride,omitempty"`
+	// OdometerGapKM é a quilometragem rodada entre a última leitura conhecida
+	// e o check-in desta entrada, quando excede a tolerância.
+	OdometerGapKM *float64 `bson:"odometerGapKM,omitempty" json:"odometerGapKM,omitempty"`
+	// ReservationID é a reserva do motorista atendida por este check-in.
+	ReservationID *primitive.ObjectID `bson:"reservationID,omitempty" json:"reservationID,omitempty"`
+
+	ActiveCarID  *primitive.ObjectID `bson:"activeCarID,omitempty" json:"-"`
+	ActiveUserID *primitive.ObjectID `bson:"activeUserID,omitempty" json:"-"`
 }
 
+// CarState guarda observações livres; a inspeção estruturada fica em Checklist.
 type CheckIn struct {
-	Location     Location `bson:"location" json:"location" validate:"required"`
-	NextLocation string   `bson:"nextLocation" json:"nextLocation" validate:"required"`
-	CarState     string   `bson:"carState" json:"carState" validate:"required"`
-	ActualKM     float64  `bson:"actualKM" json:"actualKM" validate:"required"`
-	Images       []string `bson:"images,omitempty" json:"images" validate:"max=5"`
+	Location     Location   `bson:"location" json:"location" validate:"required"`
+	NextLocation string     `bson:"nextLocation" json:"nextLocation" validate:"required_without=Destination"`
+	CarState     string     `bson:"carState" json:"carState"`
+	ActualKM     float64    `bson:"actualKM" json:"actualKM" validate:"required"`
+	Images       []string   `bson:"images,omitempty" json:"images" validate:"max=5"`
+	Checklist    *Checklist `bson:"checklist,omitempty" json:"checklist,omitempty"`
+
+	// Purpose, Destination, CostCenter e Passengers descrevem a viagem.
+	// CostCenter guarda o código de um centro de custo ativo do catálogo.
+	Purpose     string       `bson:"purpose,omitempty" json:"purpose,omitempty" validate:"max=200"`
+	Destination *Destination `bson:"destination,omitempty" json:"destination,omitempty"`
+	CostCenter  string       `bson:"costCenter,omitempty" json:"costCenter,omitempty"`
+	Passengers  []string     `bson:"passengers,omitempty" json:"passengers,omitempty" validate:"max=8,dive,required,max=100"`
+}
+
+// Destination é o destino estruturado da viagem; as coordenadas são opcionais.
+type Destination struct {
+	Name     string    `bson:"name" json:"name" validate:"required,max=100"`
+	Address  string    `bson:"address,omitempty" json:"address,omitempty"`
+	City     string    `bson:"city,omitempty" json:"city,omitempty"`
+	Location *Location `bson:"location,omitempty" json:"location,omitempty"`
 }
 
 type CheckOut struct {
-	Location Location `bson:"location" json:"location" validate:"required"`
-	CarState string   `bson:"carState" json:"carState" validate:"required"`
-	ActualKM float64  `bson:"actualKM" json:"actualKM" validate:"required"`
-	Images   []string `bson:"images,omitempty" json:"images" validate:"max=5"`
+	Location  Location   `bson:"location" json:"location" validate:"required"`
+	CarState  string     `bson:"carState" json:"carState"`
+	ActualKM  float64    `bson:"actualKM" json:"actualKM" validate:"required"`
+	Images    []string   `bson:"images,omitempty" json:"images" validate:"max=5"`
+	Checklist *Checklist `bson:"checklist,omitempty" json:"checklist,omitempty"`
 }
 
 type Location struct {
@@ -1,17 +1,30 @@
 package models
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Car struct {
-	ID          primitive.ObjectID `bson:"_id" json:"id"`
-	Number      string             `bson:"number" json:"number" validate:"required"`
-	Plate       string             `bson:"plate" json:"plate" validate:"required,len=7"`
-	Model       string             `bson:"model" json:"model" validate:"required"`
-	Brand       string             `bson:"brand" json:"brand" validate:"required"`
-	Year        int                `bson:"year" json:"year" validate:"required"`
-	IsActive    bool               `bson:"isActive" json:"isActive" validate:"required"`
-	Capacity    int                `bson:"capacity" json:"capacity" validate:"required,gt=0"`
-	Consumption float64            `bson:"consumption" json:"consumption" validate:"required,gt=0"`
+	ID               primitive.ObjectID `bson:"_id" json:"id"`
+	Number           string             `bson:"number" json:"number" validate:"required"`
+	Plate            string             `bson:"plate" json:"plate" validate:"required,len=7"`
+	Model            string             `bson:"model" json:"model" validate:"required"`
+	Brand            string             `bson:"brand" json:"brand" validate:"required"`
+	Category         string             `bson:"category,omitempty" json:"category,omitempty" validate:"max=30"`
+	Year             int                `bson:"year" json:"year" validate:"required"`
+	IsActive         bool               `bson:"isActive" json:"isActive" validate:"required"`
+	Capacity         int                `bson:"capacity" json:"capacity" validate:"required,gt=0"`
+	Consumption      float64            `bson:"consumption" json:"consumption" validate:"required,gt=0"`
+	LowFuelThreshold float64            `bson:"lowFuelThreshold,omitempty" json:"lowFuelThreshold,omitempty" validate:"gte=0,lte=100"`
+	RequiredLicense  string             `bson:"requiredLicense,omitempty" json:"requiredLicense,omitempty" validate:"omitempty,oneof=A B C D E"`
+}
+
+type CarStatistics struct {
+	Car                 `bson:",inline"`
+	LastRefuelCreatedAt *time.Time `bson:"lastRefuelCreatedAt,omitempty" json:"lastRefuelCreatedAt"`
+	CurrentFuel         *float64   `bson:"currentFuel,omitempty" json:"currentFuel"`
+	Checkout            *CheckOut  `bson:"checkout,omitempty" json:"checkout"`
+	InUse               bool       `bson:"inUse" json:"inUse"`
 }
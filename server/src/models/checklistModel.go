@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ChecklistStageCheckIn  = "checkin"
+	ChecklistStageCheckOut = "checkout"
+
+	ChecklistResultPass = "pass"
+	ChecklistResultFail = "fail"
+	ChecklistResultNA   = "na"
+)
+
+// ChecklistTemplate é a versão atual de um modelo de checklist. Sem carros
+// nem categorias atribuídos, o modelo vale para toda a frota.
+type ChecklistTemplate struct {
+	ID         primitive.ObjectID      `bson:"_id" json:"id"`
+	Name       string                  `bson:"name" json:"name" validate:"required"`
+	Version    int                     `bson:"version" json:"version"`
+	Stages     []string                `bson:"stages" json:"stages" validate:"required,min=1,dive,oneof=checkin checkout"`
+	Categories []string                `bson:"categories,omitempty" json:"categories,omitempty" validate:"dive,required,max=30"`
+	CarIDs     []primitive.ObjectID    `bson:"carIds,omitempty" json:"carIds,omitempty"`
+	Items      []ChecklistTemplateItem `bson:"items" json:"items" validate:"required,min=1,dive"`
+	IsActive   bool                    `bson:"isActive" json:"isActive"`
+	CreatedAt  time.Time               `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time               `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ChecklistTemplateVersion guarda, sem alterações posteriores, cada versão
+// publicada de um modelo.
+type ChecklistTemplateVersion struct {
+	ID         primitive.ObjectID      `bson:"_id" json:"id"`
+	TemplateID primitive.ObjectID      `bson:"templateID" json:"templateID"`
+	Version    int                     `bson:"version" json:"version"`
+	Name       string                  `bson:"name" json:"name"`
+	Stages     []string                `bson:"stages" json:"stages"`
+	Categories []string                `bson:"categories,omitempty" json:"categories,omitempty"`
+	CarIDs     []primitive.ObjectID    `bson:"carIds,omitempty" json:"carIds,omitempty"`
+	Items      []ChecklistTemplateItem `bson:"items" json:"items"`
+	CreatedAt  time.Time               `bson:"createdAt" json:"createdAt"`
+}
+
+// ChecklistTemplateItem é um item a inspecionar. Key identifica o item nos
+// relatórios e deve ser única no modelo; itens obrigatórios não aceitam NA.
+type ChecklistTemplateItem struct {
+	Key      string `bson:"key" json:"key" validate:"required,max=50"`
+	Label    string `bson:"label" json:"label" validate:"required"`
+	Category string `bson:"category,omitempty" json:"category,omitempty"`
+	Required bool   `bson:"required" json:"required"`
+}
+
+// Checklist é o checklist preenchido em um check-in ou check-out. Nome,
+// versão e rótulos são copiados do modelo para que a entrada continue legível
+// mesmo se o modelo mudar depois.
+type Checklist struct {
+	TemplateID      primitive.ObjectID `bson:"templateID" json:"templateID" validate:"required"`
+	TemplateVersion int                `bson:"templateVersion" json:"templateVersion"`
+	TemplateName    string             `bson:"templateName" json:"templateName"`
+	Items           []ChecklistItem    `bson:"items" json:"items" validate:"required,min=1,dive"`
+}
+
+type ChecklistItem struct {
+	Key      string `bson:"key" json:"key" validate:"required"`
+	Label    string `bson:"label" json:"label"`
+	Category string `bson:"category,omitempty" json:"category,omitempty"`
+	Result   string `bson:"result" json:"result" validate:"required,oneof=pass fail na"`
+	Notes    string `bson:"notes,omitempty" json:"notes,omitempty" validate:"max=500"`
+	Photo    string `bson:"photo,omitempty" json:"photo,omitempty"`
+}
+
+// ChecklistFailureSummary conta as reprovações de um item em um período.
+type ChecklistFailureSummary struct {
+	Key      string `json:"key"`
+	Label    string `json:"label"`
+	Failures int    `json:"failures"`
+	Cars     int    `json:"cars"`
+}
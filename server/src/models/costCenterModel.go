@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CostCenter é um projeto ou centro de custo do catálogo mantido pelos
+// administradores. O código identifica o centro nas entradas e não muda
+// depois de criado.
+type CostCenter struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	Code        string             `bson:"code" json:"code" validate:"required,max=30"`
+	Name        string             `bson:"name" json:"name" validate:"required,max=100"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	IsActive    bool               `bson:"isActive" json:"isActive"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// CostCenterUsage soma as viagens concluídas de um centro de custo no
+// período. Code vazio agrupa as viagens sem centro de custo.
+type CostCenterUsage struct {
+	Code       string  `json:"code"`
+	Name       string  `json:"name"`
+	Trips      int     `json:"trips"`
+	KMDriven   float64 `json:"kmDriven"`
+	Passengers int     `json:"passengers"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+const (
+	CostGroupByCar    = "car"
+	CostGroupByDriver = "driver"
+	CostGroupByPeriod = "period"
+
+	CostIntervalDay   = "day"
+	CostIntervalMonth = "month"
+)
+
+// CostReportRow agrega custo de combustível e quilometragem de um carro, de
+// um motorista ou de um período. Key é o ID do carro/motorista ou o período
+// no formato 2006-01 (mês) ou 2006-01-02 (dia).
+type CostReportRow struct {
+	Key       string   `json:"key"`
+	Label     string   `json:"label"`
+	Refuels   int      `json:"refuels"`
+	Liters    float64  `json:"liters"`
+	FuelCost  float64  `json:"fuelCost"`
+	Trips     int      `json:"trips"`
+	KMDriven  float64  `json:"kmDriven"`
+	CostPerKM *float64 `json:"costPerKm"`
+}
+
+type CostReport struct {
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	GroupBy  string          `json:"groupBy"`
+	Interval string          `json:"interval,omitempty"`
+	Rows     []CostReportRow `json:"rows"`
+	Total    CostReportRow   `json:"total"`
+}
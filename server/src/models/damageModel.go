@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DamageStatusOpen     = "open"
+	DamageStatusInRepair = "in_repair"
+	DamageStatusResolved = "resolved"
+	DamageStatusRejected = "rejected"
+
+	DamageSeverityLow    = "low"
+	DamageSeverityMedium = "medium"
+	DamageSeverityHigh   = "high"
+)
+
+// Damage é uma avaria do veículo. Avarias abertas a partir de um item
+// reprovado do checklist guardam a etapa e a chave do item.
+type Damage struct {
+	ID               primitive.ObjectID  `bson:"_id" json:"id"`
+	CarID            primitive.ObjectID  `bson:"carID" json:"carID" validate:"required"`
+	CarEntryID       *primitive.ObjectID `bson:"carEntryID,omitempty" json:"carEntryID,omitempty"`
+	Stage            string              `bson:"stage,omitempty" json:"stage,omitempty" validate:"omitempty,oneof=checkin checkout"`
+	ChecklistItemKey string              `bson:"checklistItemKey,omitempty" json:"checklistItemKey,omitempty"`
+	Location         string              `bson:"location" json:"location" validate:"required,max=100"`
+	Description      string              `bson:"description" json:"description" validate:"required,max=1000"`
+	Severity         string              `bson:"severity" json:"severity" validate:"required,oneof=low medium high"`
+	Photos           []string            `bson:"photos,omitempty" json:"photos"`
+	Status           string              `bson:"status" json:"status"`
+	ReportedBy       primitive.ObjectID  `bson:"reportedBy" json:"reportedBy"`
+	AssigneeID       *primitive.ObjectID `bson:"assigneeID,omitempty" json:"assigneeID,omitempty"`
+	History          []DamageEvent       `bson:"history" json:"history"`
+	CreatedAt        time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt        time.Time           `bson:"updatedAt" json:"updatedAt"`
+}
+
+// DamageEvent registra uma mudança na avaria. Status vazio indica que o
+// status não mudou, como em atribuições ou em um novo relato da mesma avaria.
+type DamageEvent struct {
+	Status     string              `bson:"status,omitempty" json:"status,omitempty"`
+	AssigneeID *primitive.ObjectID `bson:"assigneeID,omitempty" json:"assigneeID,omitempty"`
+	CarEntryID *primitive.ObjectID `bson:"carEntryID,omitempty" json:"carEntryID,omitempty"`
+	Stage      string              `bson:"stage,omitempty" json:"stage,omitempty"`
+	Note       string              `bson:"note,omitempty" json:"note,omitempty"`
+	By         primitive.ObjectID  `bson:"by" json:"by"`
+	At         time.Time           `bson:"at" json:"at"`
+}
+
+// DamageComparison compara as avarias conhecidas no check-out anterior do
+// carro com as relatadas até o check-in da entrada.
+type DamageComparison struct {
+	CarID              primitive.ObjectID  `json:"carID"`
+	CarEntryID         primitive.ObjectID  `json:"carEntryID"`
+	CheckInAt          time.Time           `json:"checkInAt"`
+	PreviousEntryID    *primitive.ObjectID `json:"previousEntryID"`
+	PreviousCheckOutAt *time.Time          `json:"previousCheckOutAt"`
+	KnownAtCheckOut    []Damage            `json:"knownAtCheckOut"`
+	NewDamages         []Damage            `json:"newDamages"`
+	ReportedAgain      []Damage            `json:"reportedAgain"`
+}
@@ -6,11 +6,87 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	FuelTypeRefuel     = "refuel"
+	FuelTypeCheckOut   = "checkout"
+	FuelTypeAdjustment = "adjustment"
+)
+
+// Combustíveis aceitos em um abastecimento.
+const (
+	FuelKindGasoline = "gasolina"
+	FuelKindEthanol  = "etanol"
+	FuelKindDiesel   = "diesel"
+	FuelKindCNG      = "gnv"
+)
+
 type Fuel struct {
-	ID          primitive.ObjectID `bson:"_id" json:"id"`
-	CarID       primitive.ObjectID `bson:"carID" json:"carID" validate:"required"`
-	PreviusFuel float64            `bson:"previusFuel" json:"previusFuel" validate:"required"`
-	NewFuel     float64            `bson:"currentFuel" json:"currentFuel" validate:"required"`
-	KMDriven    float64            `bson:"KMDriven" json:"KMDriven" validate:"required"`
-	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt" validate:"required"`
+	ID           primitive.ObjectID  `bson:"_id" json:"id"`
+	CarID        primitive.ObjectID  `bson:"carID" json:"carID" validate:"required"`
+	Type         string              `bson:"type" json:"type" validate:"required,oneof=refuel checkout adjustment"`
+	CarEntryID   *primitive.ObjectID `bson:"carEntryID,omitempty" json:"carEntryID,omitempty"`
+	PreviousFuel float64             `bson:"previousFuel" json:"previousFuel" validate:"required"`
+	NewFuel      float64             `bson:"currentFuel" json:"currentFuel" validate:"required"`
+	KMDriven     float64             `bson:"kmDriven" json:"kmDriven" validate:"required"`
+	CreatedAt    time.Time           `bson:"createdAt" json:"createdAt" validate:"required"`
+
+	// Detalhes do abastecimento, preenchidos apenas quando Type é refuel.
+	Liters        float64             `bson:"liters,omitempty" json:"liters,omitempty"`
+	PricePerLiter float64             `bson:"pricePerLiter,omitempty" json:"pricePerLiter,omitempty"`
+	TotalCost     float64             `bson:"totalCost,omitempty" json:"totalCost,omitempty"`
+	FuelKind      string              `bson:"fuelKind,omitempty" json:"fuelKind,omitempty"`
+	Station       string              `bson:"station,omitempty" json:"station,omitempty"`
+	Odometer      float64             `bson:"odometer,omitempty" json:"odometer,omitempty"`
+	FullTank      bool                `bson:"fullTank,omitempty" json:"fullTank,omitempty"`
+	DriverID      *primitive.ObjectID `bson:"driverID,omitempty" json:"driverID,omitempty"`
+	ReceiptImage  string              `bson:"receiptImage,omitempty" json:"receiptImage,omitempty"`
+}
+
+type FuelLedgerDivergence struct {
+	FuelID       *primitive.ObjectID `json:"fuelId,omitempty"`
+	CarEntryID   *primitive.ObjectID `json:"carEntryId,omitempty"`
+	Type         string              `json:"type"`
+	Date         time.Time           `json:"date"`
+	Issue        string              `json:"issue"`
+	StoredFuel   *float64            `json:"storedFuel,omitempty"`
+	ExpectedFuel *float64            `json:"expectedFuel,omitempty"`
+}
+
+type FuelLedgerReport struct {
+	CarID               primitive.ObjectID     `json:"carId"`
+	Plate               string                 `json:"plate"`
+	StoredCurrentFuel   float64                `json:"storedCurrentFuel"`
+	ExpectedCurrentFuel float64                `json:"expectedCurrentFuel"`
+	Consistent          bool                   `json:"consistent"`
+	Divergences         []FuelLedgerDivergence `json:"divergences"`
+	Unmatched           []Fuel                 `json:"unmatched"`
+	Ledger              []Fuel                 `json:"ledger"`
+	Rewritten           bool                   `json:"rewritten"`
+}
+
+// ConsumptionSample é o consumo medido entre dois abastecimentos de tanque
+// cheio consecutivos.
+type ConsumptionSample struct {
+	StartFuelID   primitive.ObjectID `json:"startFuelId"`
+	EndFuelID     primitive.ObjectID `json:"endFuelId"`
+	StartedAt     time.Time          `json:"startedAt"`
+	EndedAt       time.Time          `json:"endedAt"`
+	StartOdometer float64            `json:"startOdometer"`
+	EndOdometer   float64            `json:"endOdometer"`
+	KM            float64            `json:"km"`
+	Liters        float64            `json:"liters"`
+	KMPerLiter    float64            `json:"kmPerLiter"`
+	Deviation     float64            `json:"deviation"`
+	Flagged       bool               `json:"flagged"`
+}
+
+type ConsumptionReport struct {
+	CarID              primitive.ObjectID  `json:"carId"`
+	Plate              string              `json:"plate"`
+	RatedConsumption   float64             `json:"ratedConsumption"`
+	MeasuredKMPerLiter *float64            `json:"measuredKmPerLiter"`
+	Deviation          *float64            `json:"deviation"`
+	ThresholdPercent   float64             `json:"thresholdPercent"`
+	Flagged            bool                `json:"flagged"`
+	Samples            []ConsumptionSample `json:"samples"`
 }
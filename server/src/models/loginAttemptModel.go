@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Resultado de uma tentativa de login.
+const (
+	LoginAttemptSuccess            = "success"
+	LoginAttemptInvalidCredentials = "invalid_credentials"
+	LoginAttemptThrottled          = "throttled"
+	LoginAttemptLocked             = "locked"
+)
+
+// LoginAttempt registra cada tentativa de login, inclusive as recusadas por
+// excesso de tentativas, para consulta pelos administradores. UserID só é
+// preenchido quando o email pertence a um usuário ativo.
+type LoginAttempt struct {
+	ID         primitive.ObjectID  `bson:"_id" json:"id"`
+	Email      string              `bson:"email" json:"email"`
+	UserID     *primitive.ObjectID `bson:"userID,omitempty" json:"userID,omitempty"`
+	IP         string              `bson:"ip" json:"ip"`
+	DeviceInfo DeviceInfo          `bson:"deviceInfo" json:"deviceInfo"`
+	Result     string              `bson:"result" json:"result"`
+	CreatedAt  time.Time           `bson:"createdAt" json:"createdAt"`
+}
+
+// LoginThrottle acumula as falhas de login recentes de uma chave, que é o
+// email ("email:...") ou o IP ("ip:...") da tentativa. O registro deixa de
+// valer em ExpiresAt.
+type LoginThrottle struct {
+	Key           string     `bson:"_id" json:"key"`
+	Failures      int        `bson:"failures" json:"failures"`
+	LastFailureAt time.Time  `bson:"lastFailureAt" json:"lastFailureAt"`
+	LockedUntil   *time.Time `bson:"lockedUntil,omitempty" json:"lockedUntil,omitempty"`
+	ExpiresAt     time.Time  `bson:"expiresAt" json:"expiresAt"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	MaintenanceDueOK      = "ok"
+	MaintenanceDueSoon    = "due_soon"
+	MaintenanceDueOverdue = "overdue"
+)
+
+// MaintenancePlan define uma manutenção preventiva periódica para um carro
+// (CarID) ou para todos os carros de um modelo (Model). Vence no que ocorrer
+// primeiro entre IntervalKM e IntervalMonths; zero desativa o critério.
+type MaintenancePlan struct {
+	ID             primitive.ObjectID  `bson:"_id" json:"id"`
+	Name           string              `bson:"name" json:"name" validate:"required,max=100"`
+	CarID          *primitive.ObjectID `bson:"carID,omitempty" json:"carID,omitempty"`
+	Model          string              `bson:"model,omitempty" json:"model,omitempty"`
+	IntervalKM     float64             `bson:"intervalKM" json:"intervalKM" validate:"gte=0"`
+	IntervalMonths int                 `bson:"intervalMonths" json:"intervalMonths" validate:"gte=0"`
+	DueSoonKM      float64             `bson:"dueSoonKM,omitempty" json:"dueSoonKM,omitempty" validate:"gte=0"`
+	DueSoonDays    int                 `bson:"dueSoonDays,omitempty" json:"dueSoonDays,omitempty" validate:"gte=0"`
+	IsActive       bool                `bson:"isActive" json:"isActive"`
+	CreatedAt      time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time           `bson:"updatedAt" json:"updatedAt"`
+}
+
+// MaintenanceRecord é uma manutenção feita no carro. Enquanto CompletedAt for
+// nulo a manutenção está em andamento e o carro fica em manutenção.
+type MaintenanceRecord struct {
+	ID          primitive.ObjectID  `bson:"_id" json:"id"`
+	CarID       primitive.ObjectID  `bson:"carID" json:"carID" validate:"required"`
+	PlanID      *primitive.ObjectID `bson:"planID,omitempty" json:"planID,omitempty"`
+	Description string              `bson:"description" json:"description" validate:"required,max=500"`
+	Workshop    string              `bson:"workshop" json:"workshop" validate:"max=100"`
+	Cost        float64             `bson:"cost" json:"cost" validate:"gte=0"`
+	Odometer    float64             `bson:"odometer" json:"odometer" validate:"gte=0"`
+	StartedAt   time.Time           `bson:"startedAt" json:"startedAt"`
+	CompletedAt *time.Time          `bson:"completedAt,omitempty" json:"completedAt"`
+	Invoices    []string            `bson:"invoices,omitempty" json:"invoices"`
+	Notes       string              `bson:"notes,omitempty" json:"notes,omitempty" validate:"max=1000"`
+	CreatedBy   primitive.ObjectID  `bson:"createdBy" json:"createdBy"`
+	CreatedAt   time.Time           `bson:"createdAt" json:"createdAt"`
+}
+
+// MaintenanceDue é a situação de um plano para um carro, calculada a partir
+// da última manutenção concluída e da quilometragem atual.
+type MaintenanceDue struct {
+	CarID           primitive.ObjectID  `json:"carID"`
+	Plate           string              `json:"plate"`
+	PlanID          primitive.ObjectID  `json:"planID"`
+	PlanName        string              `json:"planName"`
+	CurrentOdometer float64             `json:"currentOdometer"`
+	LastRecordID    *primitive.ObjectID `json:"lastRecordID"`
+	LastCompletedAt *time.Time          `json:"lastCompletedAt"`
+	LastOdometer    float64             `json:"lastOdometer"`
+	NextDueKM       *float64            `json:"nextDueKM"`
+	NextDueDate     *time.Time          `json:"nextDueDate"`
+	RemainingKM     *float64            `json:"remainingKM"`
+	RemainingDays   *int                `json:"remainingDays"`
+	Status          string              `json:"status"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	OdometerSourceCheckIn     = "checkin"
+	OdometerSourceCheckOut    = "checkout"
+	OdometerSourceRefuel      = "refuel"
+	OdometerSourceMaintenance = "maintenance"
+)
+
+// OdometerReading é uma leitura do odômetro registrada no check-in, no
+// check-out, num abastecimento ou numa manutenção concluída (RefID aponta
+// para o registro de origem). Inconsistent marca leituras menores que a
+// anterior além da tolerância.
+type OdometerReading struct {
+	At           time.Time           `json:"at"`
+	KM           float64             `json:"km"`
+	Source       string              `json:"source"`
+	RefID        primitive.ObjectID  `json:"refID"`
+	UserID       *primitive.ObjectID `json:"userID,omitempty"`
+	Inconsistent bool                `json:"inconsistent"`
+}
+
+// OdometerGap é uma quilometragem rodada fora de qualquer entrada: entre a
+// última leitura fora de uso (From) e o check-in ou manutenção seguinte (To)
+// o odômetro avançou além da tolerância.
+type OdometerGap struct {
+	From OdometerReading `json:"from"`
+	To   OdometerReading `json:"to"`
+	KM   float64         `json:"km"`
+}
+
+type OdometerTimeline struct {
+	CarID       primitive.ObjectID `json:"carID"`
+	Plate       string             `json:"plate"`
+	CurrentKM   float64            `json:"currentKM"`
+	ToleranceKM float64            `json:"toleranceKM"`
+	Readings    []OdometerReading  `json:"readings"`
+	Gaps        []OdometerGap      `json:"gaps"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PasswordReset é um pedido de redefinição de senha. Só o hash SHA-256 do
+// token enviado ao usuário é guardado; o token vale uma única vez, até
+// ExpiresAt, e um novo pedido invalida os anteriores.
+type PasswordReset struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	UserID    primitive.ObjectID `bson:"userID" json:"userID"`
+	TokenHash string             `bson:"tokenHash" json:"-"`
+	RequestIP string             `bson:"requestIP" json:"requestIP"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	ExpiresAt time.Time          `bson:"expiresAt" json:"expiresAt"`
+	UsedAt    *time.Time         `bson:"usedAt,omitempty" json:"usedAt,omitempty"`
+}
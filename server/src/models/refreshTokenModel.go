@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Motivos de revogação de um refresh token.
+const (
+	RefreshTokenRevokedLogout       = "logout"
+	RefreshTokenRevokedReuse        = "reuse"
+	RefreshTokenRevokedUserDisabled = "user_disabled"
+	RefreshTokenRevokedUserDeleted  = "user_deleted"
+
+	RefreshTokenRevokedPasswordChanged = "password_changed"
+	RefreshTokenRevokedPasswordReset   = "password_reset"
+)
+
+// RefreshToken registra um refresh token emitido; o ID é o jti do JWT. Cada
+// login com keepConnection abre uma família, e cada uso troca o token por
+// outro da mesma família. Reapresentar um token já usado revoga a família
+// inteira.
+type RefreshToken struct {
+	ID            primitive.ObjectID  `bson:"_id" json:"id"`
+	FamilyID      primitive.ObjectID  `bson:"familyID" json:"familyID"`
+	UserID        primitive.ObjectID  `bson:"userID" json:"userID"`
+	ExpiresAt     time.Time           `bson:"expiresAt" json:"expiresAt"`
+	CreatedAt     time.Time           `bson:"createdAt" json:"createdAt"`
+	UsedAt        *time.Time          `bson:"usedAt,omitempty" json:"usedAt,omitempty"`
+	ReplacedBy    *primitive.ObjectID `bson:"replacedBy,omitempty" json:"replacedBy,omitempty"`
+	RevokedAt     *time.Time          `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+	RevokedReason string              `bson:"revokedReason,omitempty" json:"revokedReason,omitempty"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ReservationPending   = "pending"
+	ReservationApproved  = "approved"
+	ReservationRejected  = "rejected"
+	ReservationCancelled = "cancelled"
+	ReservationCheckedIn = "checked_in"
+	ReservationCompleted = "completed"
+	ReservationNoShow    = "no_show"
+)
+
+// Reservation reserva o carro para um motorista no intervalo [Start, End).
+// Reservas pendentes, aprovadas e em uso ocupam o horário; o check-in do
+// motorista dentro da janela vincula a entrada (CarEntryID).
+type Reservation struct {
+	ID           primitive.ObjectID  `bson:"_id" json:"id"`
+	CarID        primitive.ObjectID  `bson:"carID" json:"carID"`
+	UserID       primitive.ObjectID  `bson:"userID" json:"userID"`
+	Start        time.Time           `bson:"start" json:"start"`
+	End          time.Time           `bson:"end" json:"end"`
+	Notes        string              `bson:"notes,omitempty" json:"notes,omitempty"`
+	Status       string              `bson:"status" json:"status"`
+	CarEntryID   *primitive.ObjectID `bson:"carEntryID,omitempty" json:"carEntryID,omitempty"`
+	CreatedBy    primitive.ObjectID  `bson:"createdBy" json:"createdBy"`
+	DecidedBy    *primitive.ObjectID `bson:"decidedBy,omitempty" json:"decidedBy,omitempty"`
+	DecidedAt    *time.Time          `bson:"decidedAt,omitempty" json:"decidedAt,omitempty"`
+	DecisionNote string              `bson:"decisionNote,omitempty" json:"decisionNote,omitempty"`
+	CreatedAt    time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time           `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ReservationChange descreve uma mudança de status da reserva. Campos vazios
+// não são alterados.
+type ReservationChange struct {
+	Status     string
+	CarEntryID *primitive.ObjectID
+	DecidedBy  *primitive.ObjectID
+	Note       string
+	At         time.Time
+}
+
+// CarSlot indica se o carro está livre no intervalo consultado e, se não
+// estiver, quais reservas o ocupam.
+type CarSlot struct {
+	CarID     primitive.ObjectID `json:"carID"`
+	Plate     string             `json:"plate"`
+	Model     string             `json:"model"`
+	Free      bool               `json:"free"`
+	Conflicts []Reservation      `json:"conflicts"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Motivos de encerramento de uma sessão.
+const (
+	SessionRevokedByUser  = "revoked_by_user"
+	SessionRevokedByAdmin = "revoked_by_admin"
+)
+
+// Session registra um login. O ID vai no claim SessionId do access token e é
+// também a família dos refresh tokens emitidos para a sessão, de modo que
+// encerrar a sessão invalida os dois.
+type Session struct {
+	ID             primitive.ObjectID  `bson:"_id" json:"id"`
+	UserID         primitive.ObjectID  `bson:"userID" json:"userID"`
+	DeviceInfo     DeviceInfo          `bson:"deviceInfo" json:"deviceInfo"`
+	KeepConnection bool                `bson:"keepConnection" json:"keepConnection"`
+	CreatedAt      time.Time           `bson:"createdAt" json:"createdAt"`
+	LastSeenAt     time.Time           `bson:"lastSeenAt" json:"lastSeenAt"`
+	LastSeenIP     string              `bson:"lastSeenIP" json:"lastSeenIP"`
+	ExpiresAt      time.Time           `bson:"expiresAt" json:"expiresAt"`
+	RevokedAt      *time.Time          `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+	RevokedBy      *primitive.ObjectID `bson:"revokedBy,omitempty" json:"revokedBy,omitempty"`
+	RevokedReason  string              `bson:"revokedReason,omitempty" json:"revokedReason,omitempty"`
+
+	// Current marca, na listagem, a sessão usada na própria requisição.
+	Current bool `bson:"-" json:"current"`
+}
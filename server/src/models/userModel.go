@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -10,6 +12,30 @@ type User struct {
 	Email    string             `bson:"email" json:"email" validate:"required"`
 	Password string             `bson:"password" json:"password" validate:"required"`
 	UserType string             `bson:"userType" json:"userType" validate:"required"`
-	CNH      string             `bson:"cnh" json:"cnh" validate:"required,len=11,numeric"`
+	CNH      string             `bson:"cnh" json:"cnh" validate:"required,len=11,numeric,cnh"`
 	IsActive bool               `bson:"isActive" json:"isActive" validate:"required"`
+
+	CNHCategories []string   `bson:"cnhCategories,omitempty" json:"cnhCategories" validate:"dive,oneof=A B C D E"`
+	CNHExpiresAt  *time.Time `bson:"cnhExpiresAt,omitempty" json:"cnhExpiresAt"`
+
+	// MustChangePassword obriga o usuário a trocar a senha definida pelo
+	// administrador antes de usar o sistema.
+	MustChangePassword bool `bson:"mustChangePassword" json:"mustChangePassword"`
+
+	// TokenVersion vai no access token; incrementá-lo invalida os tokens já
+	// emitidos na próxima requisição.
+	TokenVersion int `bson:"tokenVersion" json:"-"`
+}
+
+// CNHExpiry é um motorista ativo cuja CNH vence dentro do prazo consultado.
+// DaysLeft é negativo para CNHs vencidas.
+type CNHExpiry struct {
+	UserID     primitive.ObjectID `json:"userID"`
+	Name       string             `json:"name"`
+	Email      string             `json:"email"`
+	CNH        string             `json:"cnh"`
+	Categories []string           `json:"categories"`
+	ExpiresAt  time.Time          `json:"expiresAt"`
+	DaysLeft   int                `json:"daysLeft"`
+	Expired    bool               `json:"expired"`
 }
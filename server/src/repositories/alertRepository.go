@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrAlertAlreadyActive = errors.New("já existe um alerta ativo deste tipo para o carro")
+
+type AlertFilter struct {
+	Status string
+	CarID  *primitive.ObjectID
+}
+
+type AlertRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.Alert, error)
+	FindActive(ctx context.Context, carID primitive.ObjectID, alertType string) (*model.Alert, error)
+	List(ctx context.Context, filter AlertFilter) ([]model.Alert, error)
+	// Create retorna ErrAlertAlreadyActive se o carro já tiver um alerta
+	// ativo do mesmo tipo.
+	Create(ctx context.Context, alert *model.Alert) error
+	// UpdateStatus muda o status do alerta desde que o atual esteja em from.
+	// Retorna ErrNotFound se o alerta não existir ou não estiver em from.
+	UpdateStatus(ctx context.Context, id primitive.ObjectID, from []string, status string, by *primitive.ObjectID, at time.Time) (*model.Alert, error)
+}
+
+type mongoAlertRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoAlertRepository(db *mongo.Database) AlertRepository {
+	return &mongoAlertRepository{collection: db.Collection("alerts")}
+}
+
+func (r *mongoAlertRepository) findOne(ctx context.Context, filter bson.M) (*model.Alert, error) {
+	var alert model.Alert
+	err := r.collection.FindOne(ctx, filter).Decode(&alert)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (r *mongoAlertRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Alert, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *mongoAlertRepository) FindActive(ctx context.Context, carID primitive.ObjectID, alertType string) (*model.Alert, error) {
+	return r.findOne(ctx, bson.M{"carID": carID, "type": alertType, "active": true})
+}
+
+func (r *mongoAlertRepository) List(ctx context.Context, filter AlertFilter) ([]model.Alert, error) {
+	query := bson.M{}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.CarID != nil {
+		query["carID"] = *filter.CarID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []model.Alert
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+func (r *mongoAlertRepository) Create(ctx context.Context, alert *model.Alert) error {
+	alert.Active = alert.Status != model.AlertStatusResolved
+
+	_, err := r.collection.InsertOne(ctx, alert)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrAlertAlreadyActive
+	}
+	return err
+}
+
+func (r *mongoAlertRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, from []string, status string, by *primitive.ObjectID, at time.Time) (*model.Alert, error) {
+	set := bson.M{"status": status}
+	update := bson.M{"$set": set}
+	switch status {
+	case model.AlertStatusAcknowledged:
+		set["acknowledgedAt"] = at
+		set["acknowledgedBy"] = by
+	case model.AlertStatusResolved:
+		set["resolvedAt"] = at
+		set["resolvedBy"] = by
+		update["$unset"] = bson.M{"active": ""}
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var alert model.Alert
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id, "status": bson.M{"$in": from}}, update, opts).Decode(&alert)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
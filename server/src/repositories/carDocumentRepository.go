@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CarDocumentFilter struct {
+	CarID *primitive.ObjectID
+	Type  string
+}
+
+type CarDocumentRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.CarDocument, error)
+	// List ordena por vencimento, do mais distante para o mais próximo.
+	List(ctx context.Context, filter CarDocumentFilter) ([]model.CarDocument, error)
+	Create(ctx context.Context, document *model.CarDocument) error
+	Replace(ctx context.Context, document *model.CarDocument) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	AddFiles(ctx context.Context, id primitive.ObjectID, paths []string, updatedAt time.Time) error
+}
+
+type mongoCarDocumentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoCarDocumentRepository(db *mongo.Database) CarDocumentRepository {
+	return &mongoCarDocumentRepository{collection: db.Collection("carDocuments")}
+}
+
+func (r *mongoCarDocumentRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.CarDocument, error) {
+	var document model.CarDocument
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&document)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+func (r *mongoCarDocumentRepository) List(ctx context.Context, filter CarDocumentFilter) ([]model.CarDocument, error) {
+	query := bson.M{}
+	if filter.CarID != nil {
+		query["carID"] = *filter.CarID
+	}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "expiresAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []model.CarDocument
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+func (r *mongoCarDocumentRepository) Create(ctx context.Context, document *model.CarDocument) error {
+	_, err := r.collection.InsertOne(ctx, document)
+	return err
+}
+
+func (r *mongoCarDocumentRepository) Replace(ctx context.Context, document *model.CarDocument) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": document.ID}, document)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoCarDocumentRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoCarDocumentRepository) AddFiles(ctx context.Context, id primitive.ObjectID, paths []string, updatedAt time.Time) error {
+	update := bson.M{
+		"$push": bson.M{"files": bson.M{"$each": paths}},
+		"$set":  bson.M{"updatedAt": updatedAt},
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrCarHasOpenEntry  = errors.New("já existe uma entrada de carro ativa para este carro")
+	ErrUserHasOpenEntry = errors.New("motorista já possui uma entrada de carro ativa")
+)
+
+// OpenEntryConflictError indica que a entrada não pôde ser aberta porque o
+// carro (ou o motorista) já possui outra entrada sem check-out.
+type OpenEntryConflictError struct {
+	Err     error
+	EntryID primitive.ObjectID
+}
+
+func (e *OpenEntryConflictError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *OpenEntryConflictError) Unwrap() error {
+	return e.Err
+}
+
+// CarEntryFilter seleciona entradas; From e To limitam o check-in a
+// [From, To).
+type CarEntryFilter struct {
+	CarID      *primitive.ObjectID
+	UserID     *primitive.ObjectID
+	CostCenter string
+	From       *time.Time
+	To         *time.Time
+}
+
+type CarEntryRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.CarEntry, error)
+	FindByIDWithUser(ctx context.Context, id primitive.ObjectID) (*model.CarEntry, error)
+	FindOpenByCar(ctx context.Context, carID primitive.ObjectID) (*model.CarEntry, error)
+	FindOpenByCarAndUser(ctx context.Context, carID, userID primitive.ObjectID) (*model.CarEntry, error)
+	List(ctx context.Context, filter CarEntryFilter) ([]model.CarEntry, error)
+	ListByCar(ctx context.Context, carID primitive.ObjectID) ([]model.CarEntry, error)
+	// ListClosedBetween retorna as entradas com check-out em [from, to).
+	ListClosedBetween(ctx context.Context, from, to time.Time) ([]model.CarEntry, error)
+	// ListStartedBetween retorna as entradas com check-in em [from, to).
+	ListStartedBetween(ctx context.Context, from, to time.Time) ([]model.CarEntry, error)
+	// Create abre a entrada reservando o carro e, se lockUser for verdadeiro,
+	// também o motorista. Retorna *OpenEntryConflictError se algum já estiver em uso.
+	Create(ctx context.Context, entry *model.CarEntry, lockUser bool) error
+	Close(ctx context.Context, id primitive.ObjectID, checkOut model.CheckOut, kmDriven float64, endedAt time.Time) (*model.CarEntry, error)
+	AddCheckInImages(ctx context.Context, id primitive.ObjectID, paths []string) error
+	AddCheckOutImages(ctx context.Context, id primitive.ObjectID, paths []string) error
+	// SetChecklistPhoto associa a foto ao item key do checklist da etapa
+	// (checkin ou checkout). Retorna ErrNotFound se o item não existir.
+	SetChecklistPhoto(ctx context.Context, id primitive.ObjectID, stage, key, path string) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	Count(ctx context.Context) (int64, error)
+}
+
+type mongoCarEntryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoCarEntryRepository(db *mongo.Database) CarEntryRepository {
+	return &mongoCarEntryRepository{collection: db.Collection("carEntries")}
+}
+
+func (r *mongoCarEntryRepository) findOne(ctx context.Context, filter bson.M, opts ...*options.FindOneOptions) (*model.CarEntry, error) {
+	var entry model.CarEntry
+	err := r.collection.FindOne(ctx, filter, opts...).Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *mongoCarEntryRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.CarEntry, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *mongoCarEntryRepository) FindByIDWithUser(ctx context.Context, id primitive.ObjectID) (*model.CarEntry, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "_id", Value: id}}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "users"},
+			{Key: "localField", Value: "userID"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "user"},
+		}}},
+		{{Key: "$unwind", Value: bson.D{
+			{Key: "path", Value: "$user"},
+			{Key: "preserveNullAndEmptyArrays", Value: true},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "user.password", Value: 0},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []model.CarEntry
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+	return &results[0], nil
+}
+
+func (r *mongoCarEntryRepository) FindOpenByCar(ctx context.Context, carID primitive.ObjectID) (*model.CarEntry, error) {
+	return r.findOne(ctx, bson.M{"carID": carID, "checkOut": nil})
+}
+
+func (r *mongoCarEntryRepository) FindOpenByCarAndUser(ctx context.Context, carID, userID primitive.ObjectID) (*model.CarEntry, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "startedAt", Value: -1}})
+	return r.findOne(ctx, bson.M{"carID": carID, "userID": userID, "checkOut": nil}, opts)
+}
+
+func (r *mongoCarEntryRepository) List(ctx context.Context, filter CarEntryFilter) ([]model.CarEntry, error) {
+	query := bson.M{}
+	if filter.CarID != nil {
+		query["carID"] = *filter.CarID
+	}
+	if filter.UserID != nil {
+		query["userID"] = *filter.UserID
+	}
+	if filter.CostCenter != "" {
+		query["checkIn.costCenter"] = filter.CostCenter
+	}
+	startedAt := bson.M{}
+	if filter.From != nil {
+		startedAt["$gte"] = *filter.From
+	}
+	if filter.To != nil {
+		startedAt["$lt"] = *filter.To
+	}
+	if len(startedAt) > 0 {
+		query["startedAt"] = startedAt
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "startedAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []model.CarEntry
+	for cursor.Next(ctx) {
+		var entry model.CarEntry
+		cursor.Decode(&entry)
+		entries = append(entries, entry)
+	}
+	return entries, cursor.Err()
+}
+
+func (r *mongoCarEntryRepository) ListByCar(ctx context.Context, carID primitive.ObjectID) ([]model.CarEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "startedAt", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"carID": carID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []model.CarEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *mongoCarEntryRepository) ListClosedBetween(ctx context.Context, from, to time.Time) ([]model.CarEntry, error) {
+	filter := bson.M{
+		"checkOut": bson.M{"$ne": nil},
+		"endedAt":  bson.M{"$gte": from, "$lt": to},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "endedAt", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []model.CarEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *mongoCarEntryRepository) ListStartedBetween(ctx context.Context, from, to time.Time) ([]model.CarEntry, error) {
+	filter := bson.M{"startedAt": bson.M{"$gte": from, "$lt": to}}
+	opts := options.Find().SetSort(bson.D{{Key: "startedAt", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []model.CarEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *mongoCarEntryRepository) Create(ctx context.Context, entry *model.CarEntry, lockUser bool) error {
+	entry.ActiveCarID = &entry.CarID
+	entry.ActiveUserID = nil
+	if lockUser {
+		entry.ActiveUserID = &entry.UserID
+	}
+
+	_, err := r.collection.InsertOne(ctx, entry)
+	if err == nil || !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	if open, findErr := r.findOne(ctx, bson.M{"activeCarID": entry.CarID}); findErr == nil {
+		return &OpenEntryConflictError{Err: ErrCarHasOpenEntry, EntryID: open.ID}
+	}
+	if open, findErr := r.findOne(ctx, bson.M{"activeUserID": entry.UserID}); findErr == nil {
+		return &OpenEntryConflictError{Err: ErrUserHasOpenEntry, EntryID: open.ID}
+	}
+	return &OpenEntryConflictError{Err: ErrCarHasOpenEntry}
+}
+
+func (r *mongoCarEntryRepository) Close(ctx context.Context, id primitive.ObjectID, checkOut model.CheckOut, kmDriven float64, endedAt time.Time) (*model.CarEntry, error) {
+	filter := bson.M{"_id": id, "checkOut": nil}
+	update := bson.M{
+		"$set": bson.M{
+			"checkOut": checkOut,
+			"endedAt":  endedAt,
+			"kmDriven": kmDriven,
+		},
+		"$unset": bson.M{
+			"activeCarID":  "",
+			"activeUserID": "",
+		},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var entry model.CarEntry
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *mongoCarEntryRepository) addImages(ctx context.Context, id primitive.ObjectID, field string, paths []string) error {
+	update := bson.M{
+		"$push": bson.M{
+			field: bson.M{"$each": paths},
+		},
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoCarEntryRepository) SetChecklistPhoto(ctx context.Context, id primitive.ObjectID, stage, key, path string) error {
+	field := "checkIn.checklist.items"
+	if stage == model.ChecklistStageCheckOut {
+		field = "checkOut.checklist.items"
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, field + ".key": key},
+		bson.M{"$set": bson.M{field + ".$.photo": path}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoCarEntryRepository) AddCheckInImages(ctx context.Context, id primitive.ObjectID, paths []string) error {
+	return r.addImages(ctx, id, "checkIn.images", paths)
+}
+
+func (r *mongoCarEntryRepository) AddCheckOutImages(ctx context.Context, id primitive.ObjectID, paths []string) error {
+	return r.addImages(ctx, id, "checkOut.images", paths)
+}
+
+func (r *mongoCarEntryRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoCarEntryRepository) Count(ctx context.Context) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{})
+}
@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"context"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CarFilter struct {
+	Search   string
+	IsActive bool
+}
+
+type CarRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.Car, error)
+	List(ctx context.Context, filter CarFilter) ([]model.Car, error)
+	Create(ctx context.Context, car *model.Car) error
+	Update(ctx context.Context, id primitive.ObjectID, updates map[string]interface{}) (*model.Car, error)
+	SetActive(ctx context.Context, id primitive.ObjectID, active bool) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	CountActive(ctx context.Context) (int64, error)
+	Statistics(ctx context.Context) ([]model.CarStatistics, error)
+}
+
+type mongoCarRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoCarRepository(db *mongo.Database) CarRepository {
+	return &mongoCarRepository{collection: db.Collection("cars")}
+}
+
+func (r *mongoCarRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Car, error) {
+	var car model.Car
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&car)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &car, nil
+}
+
+func (r *mongoCarRepository) List(ctx context.Context, filter CarFilter) ([]model.Car, error) {
+	searchFilter := bson.M{}
+	if filter.Search != "" {
+		searchFilter["$or"] = []bson.M{
+			{"plate": bson.M{"$regex": filter.Search, "$options": "i"}},
+			{"brand": bson.M{"$regex": filter.Search, "$options": "i"}},
+			{"model": bson.M{"$regex": filter.Search, "$options": "i"}},
+		}
+	}
+	searchFilter["isActive"] = filter.IsActive
+
+	cursor, err := r.collection.Find(ctx, searchFilter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var cars []model.Car
+	for cursor.Next(ctx) {
+		var car model.Car
+		cursor.Decode(&car)
+		cars = append(cars, car)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return cars, nil
+}
+
+func (r *mongoCarRepository) Create(ctx context.Context, car *model.Car) error {
+	_, err := r.collection.InsertOne(ctx, car)
+	return err
+}
+
+func (r *mongoCarRepository) Update(ctx context.Context, id primitive.ObjectID, updates map[string]interface{}) (*model.Car, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated model.Car
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": updates}, opts).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *mongoCarRepository) SetActive(ctx context.Context, id primitive.ObjectID, active bool) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"isActive": active}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoCarRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoCarRepository) CountActive(ctx context.Context) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"isActive": true})
+}
+
+func (r *mongoCarRepository) Statistics(ctx context.Context) ([]model.CarStatistics, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"isActive": true}}},
+		{{
+			Key: "$lookup", Value: bson.M{
+				"from": "fuels",
+				"let":  bson.M{"carId": "$_id"},
+				"pipeline": []bson.M{
+					{
+						"$match": bson.M{
+							"$expr": bson.M{
+								"$and": []bson.M{
+									{"$eq": []interface{}{"$carID", "$$carId"}},
+									{"$eq": []interface{}{"$type", model.FuelTypeRefuel}},
+								},
+							},
+						},
+					},
+					{"$sort": bson.M{"createdAt": -1}},
+					{"$limit": 1},
+				},
+				"as": "lastRefuel",
+			},
+		}},
+		{{
+			Key: "$lookup", Value: bson.M{
+				"from": "fuels",
+				"let":  bson.M{"carId": "$_id"},
+				"pipeline": []bson.M{
+					{
+						"$match": bson.M{
+							"$expr": bson.M{"$eq": []interface{}{"$carID", "$$carId"}},
+						},
+					},
+					{"$sort": bson.M{"createdAt": -1}},
+					{"$limit": 1},
+				},
+				"as": "lastFuel",
+			},
+		}},
+		{{
+			Key: "$lookup", Value: bson.M{
+				"from": "carEntries",
+				"let":  bson.M{"carId": "$_id"},
+				"pipeline": []bson.M{
+					{
+						"$match": bson.M{
+							"$expr": bson.M{"$eq": []interface{}{"$carID", "$$carId"}},
+						},
+					},
+					{"$sort": bson.M{"startedAt": -1}},
+					{"$limit": 1},
+				},
+				"as": "lastCarEntry",
+			},
+		}},
+		{{
+			Key: "$project", Value: bson.M{
+				"_id":              1,
+				"number":           1,
+				"plate":            1,
+				"model":            1,
+				"brand":            1,
+				"year":             1,
+				"isActive":         1,
+				"capacity":         1,
+				"consumption":      1,
+				"lowFuelThreshold": 1,
+				"lastRefuelCreatedAt": bson.M{
+					"$arrayElemAt": []interface{}{"$lastRefuel.createdAt", 0},
+				},
+				"currentFuel": bson.M{
+					"$arrayElemAt": []interface{}{"$lastFuel.currentFuel", 0},
+				},
+				"checkout": bson.M{
+					"$arrayElemAt": []interface{}{"$lastCarEntry.checkOut", 0},
+				},
+				"inUse": bson.M{
+					"$and": []interface{}{
+						bson.M{"$gt": []interface{}{bson.M{"$size": "$lastCarEntry"}, 0}},
+						bson.M{"$eq": []interface{}{bson.M{"$arrayElemAt": []interface{}{"$lastCarEntry.checkOut", 0}}, nil}},
+					},
+				},
+			},
+		}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var cars []model.CarStatistics
+	if err := cursor.All(ctx, &cars); err != nil {
+		return nil, err
+	}
+	return cars, nil
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrChecklistVersionExists = errors.New("versão do modelo de checklist já registrada")
+
+type ChecklistTemplateFilter struct {
+	IsActive bool
+	// Stage, quando informado, restringe aos modelos aplicáveis à etapa.
+	Stage string
+}
+
+type ChecklistTemplateRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.ChecklistTemplate, error)
+	List(ctx context.Context, filter ChecklistTemplateFilter) ([]model.ChecklistTemplate, error)
+	Create(ctx context.Context, template *model.ChecklistTemplate) error
+	Replace(ctx context.Context, template *model.ChecklistTemplate) error
+	SetActive(ctx context.Context, id primitive.ObjectID, active bool) error
+	// CreateVersion retorna ErrChecklistVersionExists se o modelo já tiver
+	// a versão informada, o que ocorre em edições concorrentes.
+	CreateVersion(ctx context.Context, version *model.ChecklistTemplateVersion) error
+	FindVersion(ctx context.Context, templateID primitive.ObjectID, version int) (*model.ChecklistTemplateVersion, error)
+	ListVersions(ctx context.Context, templateID primitive.ObjectID) ([]model.ChecklistTemplateVersion, error)
+}
+
+type mongoChecklistTemplateRepository struct {
+	collection *mongo.Collection
+	versions   *mongo.Collection
+}
+
+func NewMongoChecklistTemplateRepository(db *mongo.Database) ChecklistTemplateRepository {
+	return &mongoChecklistTemplateRepository{
+		collection: db.Collection("checklistTemplates"),
+		versions:   db.Collection("checklistTemplateVersions"),
+	}
+}
+
+func (r *mongoChecklistTemplateRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.ChecklistTemplate, error) {
+	var template model.ChecklistTemplate
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&template)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *mongoChecklistTemplateRepository) List(ctx context.Context, filter ChecklistTemplateFilter) ([]model.ChecklistTemplate, error) {
+	query := bson.M{"isActive": filter.IsActive}
+	if filter.Stage != "" {
+		query["stages"] = filter.Stage
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []model.ChecklistTemplate
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *mongoChecklistTemplateRepository) Create(ctx context.Context, template *model.ChecklistTemplate) error {
+	_, err := r.collection.InsertOne(ctx, template)
+	return err
+}
+
+func (r *mongoChecklistTemplateRepository) Replace(ctx context.Context, template *model.ChecklistTemplate) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": template.ID}, template)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoChecklistTemplateRepository) SetActive(ctx context.Context, id primitive.ObjectID, active bool) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"isActive": active}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoChecklistTemplateRepository) CreateVersion(ctx context.Context, version *model.ChecklistTemplateVersion) error {
+	_, err := r.versions.InsertOne(ctx, version)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrChecklistVersionExists
+	}
+	return err
+}
+
+func (r *mongoChecklistTemplateRepository) FindVersion(ctx context.Context, templateID primitive.ObjectID, version int) (*model.ChecklistTemplateVersion, error) {
+	var result model.ChecklistTemplateVersion
+	err := r.versions.FindOne(ctx, bson.M{"templateID": templateID, "version": version}).Decode(&result)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *mongoChecklistTemplateRepository) ListVersions(ctx context.Context, templateID primitive.ObjectID) ([]model.ChecklistTemplateVersion, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: -1}})
+	cursor, err := r.versions.Find(ctx, bson.M{"templateID": templateID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []model.ChecklistTemplateVersion
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
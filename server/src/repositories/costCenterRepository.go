@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrCostCenterCodeExists = errors.New("código de centro de custo já cadastrado")
+
+type CostCenterRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.CostCenter, error)
+	FindByCode(ctx context.Context, code string) (*model.CostCenter, error)
+	// List ordena pelo código.
+	List(ctx context.Context, isActive bool) ([]model.CostCenter, error)
+	// Create retorna ErrCostCenterCodeExists se o código já existir.
+	Create(ctx context.Context, costCenter *model.CostCenter) error
+	Replace(ctx context.Context, costCenter *model.CostCenter) error
+	SetActive(ctx context.Context, id primitive.ObjectID, active bool) error
+}
+
+type mongoCostCenterRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoCostCenterRepository(db *mongo.Database) CostCenterRepository {
+	return &mongoCostCenterRepository{collection: db.Collection("costCenters")}
+}
+
+func (r *mongoCostCenterRepository) findOne(ctx context.Context, filter bson.M) (*model.CostCenter, error) {
+	var costCenter model.CostCenter
+	err := r.collection.FindOne(ctx, filter).Decode(&costCenter)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &costCenter, nil
+}
+
+func (r *mongoCostCenterRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.CostCenter, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *mongoCostCenterRepository) FindByCode(ctx context.Context, code string) (*model.CostCenter, error) {
+	return r.findOne(ctx, bson.M{"code": code})
+}
+
+func (r *mongoCostCenterRepository) List(ctx context.Context, isActive bool) ([]model.CostCenter, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "code", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"isActive": isActive}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var costCenters []model.CostCenter
+	if err := cursor.All(ctx, &costCenters); err != nil {
+		return nil, err
+	}
+	return costCenters, nil
+}
+
+func (r *mongoCostCenterRepository) Create(ctx context.Context, costCenter *model.CostCenter) error {
+	_, err := r.collection.InsertOne(ctx, costCenter)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrCostCenterCodeExists
+	}
+	return err
+}
+
+func (r *mongoCostCenterRepository) Replace(ctx context.Context, costCenter *model.CostCenter) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": costCenter.ID}, costCenter)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoCostCenterRepository) SetActive(ctx context.Context, id primitive.ObjectID, active bool) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"isActive": active}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DamageFilter struct {
+	CarID            *primitive.ObjectID
+	CarEntryID       *primitive.ObjectID
+	Stage            string
+	ChecklistItemKey string
+	Status           []string
+}
+
+type DamageRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.Damage, error)
+	List(ctx context.Context, filter DamageFilter) ([]model.Damage, error)
+	Create(ctx context.Context, damage *model.Damage) error
+	// AddEvent registra o evento no histórico desde que o status atual esteja
+	// em from, aplicando o status e o responsável do evento quando
+	// informados. Retorna ErrNotFound se a avaria não existir ou não estiver
+	// em from.
+	AddEvent(ctx context.Context, id primitive.ObjectID, from []string, event model.DamageEvent) (*model.Damage, error)
+	AddPhotos(ctx context.Context, id primitive.ObjectID, paths []string) error
+}
+
+type mongoDamageRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoDamageRepository(db *mongo.Database) DamageRepository {
+	return &mongoDamageRepository{collection: db.Collection("damages")}
+}
+
+func (r *mongoDamageRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Damage, error) {
+	var damage model.Damage
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&damage)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &damage, nil
+}
+
+func (r *mongoDamageRepository) List(ctx context.Context, filter DamageFilter) ([]model.Damage, error) {
+	query := bson.M{}
+	if filter.CarID != nil {
+		query["carID"] = *filter.CarID
+	}
+	if filter.CarEntryID != nil {
+		query["carEntryID"] = *filter.CarEntryID
+	}
+	if filter.Stage != "" {
+		query["stage"] = filter.Stage
+	}
+	if filter.ChecklistItemKey != "" {
+		query["checklistItemKey"] = filter.ChecklistItemKey
+	}
+	if len(filter.Status) > 0 {
+		query["status"] = bson.M{"$in": filter.Status}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var damages []model.Damage
+	if err := cursor.All(ctx, &damages); err != nil {
+		return nil, err
+	}
+	return damages, nil
+}
+
+func (r *mongoDamageRepository) Create(ctx context.Context, damage *model.Damage) error {
+	_, err := r.collection.InsertOne(ctx, damage)
+	return err
+}
+
+func (r *mongoDamageRepository) AddEvent(ctx context.Context, id primitive.ObjectID, from []string, event model.DamageEvent) (*model.Damage, error) {
+	set := bson.M{"updatedAt": event.At}
+	if event.Status != "" {
+		set["status"] = event.Status
+	}
+	if event.AssigneeID != nil {
+		set["assigneeID"] = event.AssigneeID
+	}
+	update := bson.M{"$set": set, "$push": bson.M{"history": event}}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var damage model.Damage
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id, "status": bson.M{"$in": from}}, update, opts).Decode(&damage)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &damage, nil
+}
+
+func (r *mongoDamageRepository) AddPhotos(ctx context.Context, id primitive.ObjectID, paths []string) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$push": bson.M{"photos": bson.M{"$each": paths}}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
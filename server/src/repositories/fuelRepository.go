@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FuelRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.Fuel, error)
+	FindLatestByCar(ctx context.Context, carID primitive.ObjectID) (*model.Fuel, error)
+	ListByCar(ctx context.Context, carID primitive.ObjectID) ([]model.Fuel, error)
+	// ListRefuelsBetween retorna os abastecimentos registrados em [from, to).
+	ListRefuelsBetween(ctx context.Context, from, to time.Time) ([]model.Fuel, error)
+	Create(ctx context.Context, fuel *model.Fuel) error
+	// LockCar serializa, dentro de uma transação, os lançamentos no histórico
+	// de combustível do mesmo carro: duas transações que o chamem para o
+	// mesmo carro entram em conflito de escrita, e a segunda é refeita já
+	// enxergando o registro da primeira.
+	LockCar(ctx context.Context, carID primitive.ObjectID) error
+	// ReplaceByCar substitui todo o histórico de combustível do carro.
+	ReplaceByCar(ctx context.Context, carID primitive.ObjectID, fuels []model.Fuel) error
+	SetReceiptImage(ctx context.Context, id primitive.ObjectID, path string) error
+}
+
+type mongoFuelRepository struct {
+	collection *mongo.Collection
+	locks      *mongo.Collection
+}
+
+func NewMongoFuelRepository(db *mongo.Database) FuelRepository {
+	return &mongoFuelRepository{
+		collection: db.Collection("fuels"),
+		locks:      db.Collection("fuelLocks"),
+	}
+}
+
+func (r *mongoFuelRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Fuel, error) {
+	var fuel model.Fuel
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&fuel)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &fuel, nil
+}
+
+func (r *mongoFuelRepository) FindLatestByCar(ctx context.Context, carID primitive.ObjectID) (*model.Fuel, error) {
+	var fuel model.Fuel
+	opts := options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	err := r.collection.FindOne(ctx, bson.M{"carID": carID}, opts).Decode(&fuel)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &fuel, nil
+}
+
+func (r *mongoFuelRepository) Create(ctx context.Context, fuel *model.Fuel) error {
+	_, err := r.collection.InsertOne(ctx, fuel)
+	return err
+}
+
+func (r *mongoFuelRepository) ListByCar(ctx context.Context, carID primitive.ObjectID) ([]model.Fuel, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"carID": carID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var fuels []model.Fuel
+	if err := cursor.All(ctx, &fuels); err != nil {
+		return nil, err
+	}
+	return fuels, nil
+}
+
+func (r *mongoFuelRepository) ListRefuelsBetween(ctx context.Context, from, to time.Time) ([]model.Fuel, error) {
+	filter := bson.M{
+		"type":      model.FuelTypeRefuel,
+		"createdAt": bson.M{"$gte": from, "$lt": to},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var fuels []model.Fuel
+	if err := cursor.All(ctx, &fuels); err != nil {
+		return nil, err
+	}
+	return fuels, nil
+}
+
+func (r *mongoFuelRepository) LockCar(ctx context.Context, carID primitive.ObjectID) error {
+	_, err := r.locks.UpdateOne(ctx, bson.M{"_id": carID}, bson.M{"$inc": bson.M{"version": 1}}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *mongoFuelRepository) ReplaceByCar(ctx context.Context, carID primitive.ObjectID, fuels []model.Fuel) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"carID": carID}); err != nil {
+		return err
+	}
+	if len(fuels) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, len(fuels))
+	for i := range fuels {
+		documents[i] = fuels[i]
+	}
+	_, err := r.collection.InsertMany(ctx, documents)
+	return err
+}
+
+func (r *mongoFuelRepository) SetReceiptImage(ctx context.Context, id primitive.ObjectID, path string) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"receiptImage": path}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LoginAttemptFilter restringe a listagem das tentativas de login. Campos
+// vazios não filtram; Limit zero devolve todas.
+type LoginAttemptFilter struct {
+	Email  string
+	IP     string
+	UserID *primitive.ObjectID
+	Result string
+	From   *time.Time
+	To     *time.Time
+	Limit  int64
+}
+
+type LoginAttemptRepository interface {
+	// ListAttempts ordena da tentativa mais recente para a mais antiga.
+	ListAttempts(ctx context.Context, filter LoginAttemptFilter) ([]model.LoginAttempt, error)
+	CreateAttempt(ctx context.Context, attempt *model.LoginAttempt) error
+	FindThrottle(ctx context.Context, key string) (*model.LoginThrottle, error)
+	// RegisterFailure soma uma falha à chave numa única operação atômica e
+	// devolve o estado resultante. Um registro já expirado recomeça do zero;
+	// ao atingir maxFailures, a chave fica bloqueada até now+window. O
+	// registro passa a expirar em now+window ou no fim do bloqueio, o que
+	// for mais tarde.
+	RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration, maxFailures int) (*model.LoginThrottle, error)
+	DeleteThrottle(ctx context.Context, key string) error
+}
+
+type mongoLoginAttemptRepository struct {
+	attempts  *mongo.Collection
+	throttles *mongo.Collection
+}
+
+func NewMongoLoginAttemptRepository(db *mongo.Database) LoginAttemptRepository {
+	return &mongoLoginAttemptRepository{
+		attempts:  db.Collection("loginAttempts"),
+		throttles: db.Collection("loginThrottles"),
+	}
+}
+
+func (r *mongoLoginAttemptRepository) ListAttempts(ctx context.Context, filter LoginAttemptFilter) ([]model.LoginAttempt, error) {
+	query := bson.M{}
+	if filter.Email != "" {
+		query["email"] = filter.Email
+	}
+	if filter.IP != "" {
+		query["ip"] = filter.IP
+	}
+	if filter.UserID != nil {
+		query["userID"] = *filter.UserID
+	}
+	if filter.Result != "" {
+		query["result"] = filter.Result
+	}
+	createdAt := bson.M{}
+	if filter.From != nil {
+		createdAt["$gte"] = *filter.From
+	}
+	if filter.To != nil {
+		createdAt["$lt"] = *filter.To
+	}
+	if len(createdAt) > 0 {
+		query["createdAt"] = createdAt
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+	cursor, err := r.attempts.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var attempts []model.LoginAttempt
+	if err := cursor.All(ctx, &attempts); err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+func (r *mongoLoginAttemptRepository) CreateAttempt(ctx context.Context, attempt *model.LoginAttempt) error {
+	_, err := r.attempts.InsertOne(ctx, attempt)
+	return err
+}
+
+func (r *mongoLoginAttemptRepository) FindThrottle(ctx context.Context, key string) (*model.LoginThrottle, error) {
+	var throttle model.LoginThrottle
+	err := r.throttles.FindOne(ctx, bson.M{"_id": key}).Decode(&throttle)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *mongoLoginAttemptRepository) RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration, maxFailures int) (*model.LoginThrottle, error) {
+	active := bson.M{"$gt": bson.A{"$expiresAt", now}}
+	lockedUntil := now.Add(window)
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"failures":      bson.M{"$cond": bson.A{active, bson.M{"$add": bson.A{"$failures", 1}}, 1}},
+			"lockedUntil":   bson.M{"$cond": bson.A{active, "$lockedUntil", "$$REMOVE"}},
+			"lastFailureAt": now,
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"lockedUntil": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{bson.M{"$type": "$lockedUntil"}, "date"}},
+				"$lockedUntil",
+				bson.M{"$cond": bson.A{bson.M{"$gte": bson.A{"$failures", maxFailures}}, lockedUntil, "$$REMOVE"}},
+			}},
+			"expiresAt": bson.M{"$max": bson.A{lockedUntil, "$lockedUntil"}},
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var throttle model.LoginThrottle
+	if err := r.throttles.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&throttle); err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *mongoLoginAttemptRepository) DeleteThrottle(ctx context.Context, key string) error {
+	_, err := r.throttles.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
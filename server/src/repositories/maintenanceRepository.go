@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MaintenanceRecordFilter struct {
+	CarID  *primitive.ObjectID
+	PlanID *primitive.ObjectID
+	// InProgress, quando informado, separa as manutenções em andamento
+	// (true) das concluídas (false).
+	InProgress *bool
+}
+
+type MaintenanceRepository interface {
+	FindPlanByID(ctx context.Context, id primitive.ObjectID) (*model.MaintenancePlan, error)
+	ListPlans(ctx context.Context, isActive bool) ([]model.MaintenancePlan, error)
+	CreatePlan(ctx context.Context, plan *model.MaintenancePlan) error
+	ReplacePlan(ctx context.Context, plan *model.MaintenancePlan) error
+	SetPlanActive(ctx context.Context, id primitive.ObjectID, active bool) error
+
+	FindRecordByID(ctx context.Context, id primitive.ObjectID) (*model.MaintenanceRecord, error)
+	// ListRecords ordena pela conclusão, da mais recente para a mais antiga,
+	// com as manutenções em andamento no fim.
+	ListRecords(ctx context.Context, filter MaintenanceRecordFilter) ([]model.MaintenanceRecord, error)
+	CreateRecord(ctx context.Context, record *model.MaintenanceRecord) error
+	// CompleteRecord conclui uma manutenção em andamento. Retorna ErrNotFound
+	// se ela não existir ou já estiver concluída.
+	CompleteRecord(ctx context.Context, id primitive.ObjectID, completedAt time.Time, odometer, cost float64) (*model.MaintenanceRecord, error)
+	AddRecordInvoices(ctx context.Context, id primitive.ObjectID, paths []string) error
+}
+
+type mongoMaintenanceRepository struct {
+	plans   *mongo.Collection
+	records *mongo.Collection
+}
+
+func NewMongoMaintenanceRepository(db *mongo.Database) MaintenanceRepository {
+	return &mongoMaintenanceRepository{
+		plans:   db.Collection("maintenancePlans"),
+		records: db.Collection("maintenanceRecords"),
+	}
+}
+
+func (r *mongoMaintenanceRepository) FindPlanByID(ctx context.Context, id primitive.ObjectID) (*model.MaintenancePlan, error) {
+	var plan model.MaintenancePlan
+	err := r.plans.FindOne(ctx, bson.M{"_id": id}).Decode(&plan)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *mongoMaintenanceRepository) ListPlans(ctx context.Context, isActive bool) ([]model.MaintenancePlan, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.plans.Find(ctx, bson.M{"isActive": isActive}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []model.MaintenancePlan
+	if err := cursor.All(ctx, &plans); err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+func (r *mongoMaintenanceRepository) CreatePlan(ctx context.Context, plan *model.MaintenancePlan) error {
+	_, err := r.plans.InsertOne(ctx, plan)
+	return err
+}
+
+func (r *mongoMaintenanceRepository) ReplacePlan(ctx context.Context, plan *model.MaintenancePlan) error {
+	result, err := r.plans.ReplaceOne(ctx, bson.M{"_id": plan.ID}, plan)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoMaintenanceRepository) SetPlanActive(ctx context.Context, id primitive.ObjectID, active bool) error {
+	result, err := r.plans.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"isActive": active}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoMaintenanceRepository) FindRecordByID(ctx context.Context, id primitive.ObjectID) (*model.MaintenanceRecord, error) {
+	var record model.MaintenanceRecord
+	err := r.records.FindOne(ctx, bson.M{"_id": id}).Decode(&record)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *mongoMaintenanceRepository) ListRecords(ctx context.Context, filter MaintenanceRecordFilter) ([]model.MaintenanceRecord, error) {
+	query := bson.M{}
+	if filter.CarID != nil {
+		query["carID"] = *filter.CarID
+	}
+	if filter.PlanID != nil {
+		query["planID"] = *filter.PlanID
+	}
+	if filter.InProgress != nil {
+		if *filter.InProgress {
+			query["completedAt"] = nil
+		} else {
+			query["completedAt"] = bson.M{"$ne": nil}
+		}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "completedAt", Value: -1}, {Key: "startedAt", Value: -1}})
+	cursor, err := r.records.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []model.MaintenanceRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (r *mongoMaintenanceRepository) CreateRecord(ctx context.Context, record *model.MaintenanceRecord) error {
+	_, err := r.records.InsertOne(ctx, record)
+	return err
+}
+
+func (r *mongoMaintenanceRepository) CompleteRecord(ctx context.Context, id primitive.ObjectID, completedAt time.Time, odometer, cost float64) (*model.MaintenanceRecord, error) {
+	update := bson.M{"$set": bson.M{"completedAt": completedAt, "odometer": odometer, "cost": cost}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var record model.MaintenanceRecord
+	err := r.records.FindOneAndUpdate(ctx, bson.M{"_id": id, "completedAt": nil}, update, opts).Decode(&record)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *mongoMaintenanceRepository) AddRecordInvoices(ctx context.Context, id primitive.ObjectID, paths []string) error {
+	result, err := r.records.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$push": bson.M{"invoices": bson.M{"$each": paths}}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryAlertRepository struct {
+	db *memoryDB
+}
+
+func (r *memoryAlertRepository) indexOf(id primitive.ObjectID) int {
+	for i, alert := range r.db.alerts {
+		if alert.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *memoryAlertRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Alert, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	alert := r.db.alerts[i]
+	return &alert, nil
+}
+
+func (r *memoryAlertRepository) FindActive(ctx context.Context, carID primitive.ObjectID, alertType string) (*model.Alert, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, alert := range r.db.alerts {
+		if alert.CarID == carID && alert.Type == alertType && alert.Active {
+			return &alert, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryAlertRepository) List(ctx context.Context, filter AlertFilter) ([]model.Alert, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var alerts []model.Alert
+	for _, alert := range r.db.alerts {
+		if filter.Status != "" && alert.Status != filter.Status {
+			continue
+		}
+		if filter.CarID != nil && alert.CarID != *filter.CarID {
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].CreatedAt.After(alerts[j].CreatedAt)
+	})
+	return alerts, nil
+}
+
+func (r *memoryAlertRepository) Create(ctx context.Context, alert *model.Alert) error {
+	defer r.db.lock(ctx)()
+
+	alert.Active = alert.Status != model.AlertStatusResolved
+	if alert.Active {
+		for _, active := range r.db.alerts {
+			if active.Active && active.CarID == alert.CarID && active.Type == alert.Type {
+				return ErrAlertAlreadyActive
+			}
+		}
+	}
+
+	r.db.alerts = append(r.db.alerts, *alert)
+	return nil
+}
+
+func (r *memoryAlertRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, from []string, status string, by *primitive.ObjectID, at time.Time) (*model.Alert, error) {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	alert := &r.db.alerts[i]
+	allowed := false
+	for _, current := range from {
+		if alert.Status == current {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return nil, ErrNotFound
+	}
+
+	alert.Status = status
+	switch status {
+	case model.AlertStatusAcknowledged:
+		alert.AcknowledgedAt = &at
+		alert.AcknowledgedBy = by
+	case model.AlertStatusResolved:
+		alert.ResolvedAt = &at
+		alert.ResolvedBy = by
+		alert.Active = false
+	}
+
+	updated := *alert
+	return &updated, nil
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryCarDocumentRepository struct {
+	db *memoryDB
+}
+
+func cloneCarDocument(document model.CarDocument) model.CarDocument {
+	document.Files = append([]string(nil), document.Files...)
+	return document
+}
+
+func (r *memoryCarDocumentRepository) indexOf(id primitive.ObjectID) int {
+	for i, document := range r.db.carDocuments {
+		if document.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *memoryCarDocumentRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.CarDocument, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	document := cloneCarDocument(r.db.carDocuments[i])
+	return &document, nil
+}
+
+func (r *memoryCarDocumentRepository) List(ctx context.Context, filter CarDocumentFilter) ([]model.CarDocument, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var documents []model.CarDocument
+	for _, document := range r.db.carDocuments {
+		if filter.CarID != nil && document.CarID != *filter.CarID {
+			continue
+		}
+		if filter.Type != "" && document.Type != filter.Type {
+			continue
+		}
+		documents = append(documents, cloneCarDocument(document))
+	}
+	sort.SliceStable(documents, func(i, j int) bool {
+		return documents[i].ExpiresAt.After(documents[j].ExpiresAt)
+	})
+	return documents, nil
+}
+
+func (r *memoryCarDocumentRepository) Create(ctx context.Context, document *model.CarDocument) error {
+	defer r.db.lock(ctx)()
+
+	r.db.carDocuments = append(r.db.carDocuments, cloneCarDocument(*document))
+	return nil
+}
+
+func (r *memoryCarDocumentRepository) Replace(ctx context.Context, document *model.CarDocument) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(document.ID)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.carDocuments[i] = cloneCarDocument(*document)
+	return nil
+}
+
+func (r *memoryCarDocumentRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.carDocuments = append(r.db.carDocuments[:i], r.db.carDocuments[i+1:]...)
+	return nil
+}
+
+func (r *memoryCarDocumentRepository) AddFiles(ctx context.Context, id primitive.ObjectID, paths []string, updatedAt time.Time) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.carDocuments[i].Files = append(r.db.carDocuments[i].Files, paths...)
+	r.db.carDocuments[i].UpdatedAt = updatedAt
+	return nil
+}
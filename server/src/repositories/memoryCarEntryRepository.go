@@ -0,0 +1,342 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryCarEntryRepository struct {
+	db *memoryDB
+}
+
+func cloneChecklist(checklist *model.Checklist) *model.Checklist {
+	if checklist == nil {
+		return nil
+	}
+	clone := *checklist
+	clone.Items = append([]model.ChecklistItem(nil), checklist.Items...)
+	return &clone
+}
+
+func cloneCarEntry(entry model.CarEntry) model.CarEntry {
+	entry.CheckIn.Images = append([]string(nil), entry.CheckIn.Images...)
+	entry.CheckIn.Checklist = cloneChecklist(entry.CheckIn.Checklist)
+	entry.CheckIn.Passengers = append([]string(nil), entry.CheckIn.Passengers...)
+	if entry.CheckIn.Destination != nil {
+		destination := *entry.CheckIn.Destination
+		if destination.Location != nil {
+			location := *destination.Location
+			destination.Location = &location
+		}
+		entry.CheckIn.Destination = &destination
+	}
+	if entry.CheckOut != nil {
+		checkOut := *entry.CheckOut
+		checkOut.Images = append([]string(nil), checkOut.Images...)
+		checkOut.Checklist = cloneChecklist(checkOut.Checklist)
+		entry.CheckOut = &checkOut
+	}
+	if entry.KMDriven != nil {
+		kmDriven := *entry.KMDriven
+		entry.KMDriven = &kmDriven
+	}
+	if entry.EndedAt != nil {
+		endedAt := *entry.EndedAt
+		entry.EndedAt = &endedAt
+	}
+	if entry.ActiveCarID != nil {
+		carID := *entry.ActiveCarID
+		entry.ActiveCarID = &carID
+	}
+	if entry.ActiveUserID != nil {
+		userID := *entry.ActiveUserID
+		entry.ActiveUserID = &userID
+	}
+	entry.User = nil
+	return entry
+}
+
+func (r *memoryCarEntryRepository) indexOf(id primitive.ObjectID) int {
+	for i, entry := range r.db.carEntries {
+		if entry.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *memoryCarEntryRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.CarEntry, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	entry := cloneCarEntry(r.db.carEntries[i])
+	return &entry, nil
+}
+
+func (r *memoryCarEntryRepository) FindByIDWithUser(ctx context.Context, id primitive.ObjectID) (*model.CarEntry, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	entry := cloneCarEntry(r.db.carEntries[i])
+	for _, user := range r.db.users {
+		if user.ID == entry.UserID {
+			user.Password = ""
+			entry.User = &user
+			break
+		}
+	}
+	return &entry, nil
+}
+
+func (r *memoryCarEntryRepository) findOpen(match func(model.CarEntry) bool) (*model.CarEntry, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var latest *model.CarEntry
+	for i := range r.db.carEntries {
+		entry := &r.db.carEntries[i]
+		if entry.CheckOut != nil || !match(*entry) {
+			continue
+		}
+		if latest == nil || entry.StartedAt.After(latest.StartedAt) {
+			latest = entry
+		}
+	}
+	if latest == nil {
+		return nil, ErrNotFound
+	}
+
+	entry := cloneCarEntry(*latest)
+	return &entry, nil
+}
+
+func (r *memoryCarEntryRepository) FindOpenByCar(ctx context.Context, carID primitive.ObjectID) (*model.CarEntry, error) {
+	return r.findOpen(func(entry model.CarEntry) bool {
+		return entry.CarID == carID
+	})
+}
+
+func (r *memoryCarEntryRepository) FindOpenByCarAndUser(ctx context.Context, carID, userID primitive.ObjectID) (*model.CarEntry, error) {
+	return r.findOpen(func(entry model.CarEntry) bool {
+		return entry.CarID == carID && entry.UserID == userID
+	})
+}
+
+func (r *memoryCarEntryRepository) List(ctx context.Context, filter CarEntryFilter) ([]model.CarEntry, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var entries []model.CarEntry
+	for _, entry := range r.db.carEntries {
+		if filter.CarID != nil && entry.CarID != *filter.CarID {
+			continue
+		}
+		if filter.UserID != nil && entry.UserID != *filter.UserID {
+			continue
+		}
+		if filter.CostCenter != "" && entry.CheckIn.CostCenter != filter.CostCenter {
+			continue
+		}
+		if filter.From != nil && entry.StartedAt.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && !entry.StartedAt.Before(*filter.To) {
+			continue
+		}
+		entries = append(entries, cloneCarEntry(entry))
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedAt.After(entries[j].StartedAt)
+	})
+	return entries, nil
+}
+
+func (r *memoryCarEntryRepository) ListByCar(ctx context.Context, carID primitive.ObjectID) ([]model.CarEntry, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var entries []model.CarEntry
+	for _, entry := range r.db.carEntries {
+		if entry.CarID == carID {
+			entries = append(entries, cloneCarEntry(entry))
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedAt.Before(entries[j].StartedAt)
+	})
+	return entries, nil
+}
+
+func (r *memoryCarEntryRepository) ListClosedBetween(ctx context.Context, from, to time.Time) ([]model.CarEntry, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var entries []model.CarEntry
+	for _, entry := range r.db.carEntries {
+		if entry.CheckOut == nil || entry.EndedAt == nil {
+			continue
+		}
+		if entry.EndedAt.Before(from) || !entry.EndedAt.Before(to) {
+			continue
+		}
+		entries = append(entries, cloneCarEntry(entry))
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].EndedAt.Before(*entries[j].EndedAt)
+	})
+	return entries, nil
+}
+
+func (r *memoryCarEntryRepository) ListStartedBetween(ctx context.Context, from, to time.Time) ([]model.CarEntry, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var entries []model.CarEntry
+	for _, entry := range r.db.carEntries {
+		if entry.StartedAt.Before(from) || !entry.StartedAt.Before(to) {
+			continue
+		}
+		entries = append(entries, cloneCarEntry(entry))
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedAt.Before(entries[j].StartedAt)
+	})
+	return entries, nil
+}
+
+func (r *memoryCarEntryRepository) Create(ctx context.Context, entry *model.CarEntry, lockUser bool) error {
+	defer r.db.lock(ctx)()
+
+	for _, open := range r.db.carEntries {
+		if open.ActiveCarID != nil && *open.ActiveCarID == entry.CarID {
+			return &OpenEntryConflictError{Err: ErrCarHasOpenEntry, EntryID: open.ID}
+		}
+	}
+	if lockUser {
+		for _, open := range r.db.carEntries {
+			if open.ActiveUserID != nil && *open.ActiveUserID == entry.UserID {
+				return &OpenEntryConflictError{Err: ErrUserHasOpenEntry, EntryID: open.ID}
+			}
+		}
+	}
+
+	entry.ActiveCarID = &entry.CarID
+	entry.ActiveUserID = nil
+	if lockUser {
+		entry.ActiveUserID = &entry.UserID
+	}
+
+	r.db.carEntries = append(r.db.carEntries, cloneCarEntry(*entry))
+	return nil
+}
+
+func (r *memoryCarEntryRepository) Close(ctx context.Context, id primitive.ObjectID, checkOut model.CheckOut, kmDriven float64, endedAt time.Time) (*model.CarEntry, error) {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 || r.db.carEntries[i].CheckOut != nil {
+		return nil, ErrNotFound
+	}
+
+	entry := &r.db.carEntries[i]
+	checkOut.Images = append([]string(nil), checkOut.Images...)
+	entry.CheckOut = &checkOut
+	entry.KMDriven = &kmDriven
+	entry.EndedAt = &endedAt
+	entry.ActiveCarID = nil
+	entry.ActiveUserID = nil
+
+	closed := cloneCarEntry(*entry)
+	return &closed, nil
+}
+
+func (r *memoryCarEntryRepository) AddCheckInImages(ctx context.Context, id primitive.ObjectID, paths []string) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	entry := &r.db.carEntries[i]
+	entry.CheckIn.Images = append(entry.CheckIn.Images, paths...)
+	return nil
+}
+
+func (r *memoryCarEntryRepository) AddCheckOutImages(ctx context.Context, id primitive.ObjectID, paths []string) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	entry := &r.db.carEntries[i]
+	if entry.CheckOut == nil {
+		return errors.New("entrada de carro sem check-out")
+	}
+	entry.CheckOut.Images = append(entry.CheckOut.Images, paths...)
+	return nil
+}
+
+func (r *memoryCarEntryRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.carEntries = append(r.db.carEntries[:i], r.db.carEntries[i+1:]...)
+	return nil
+}
+
+func (r *memoryCarEntryRepository) Count(ctx context.Context) (int64, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	return int64(len(r.db.carEntries)), nil
+}
+
+func (r *memoryCarEntryRepository) SetChecklistPhoto(ctx context.Context, id primitive.ObjectID, stage, key, path string) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	entry := &r.db.carEntries[i]
+	checklist := entry.CheckIn.Checklist
+	if stage == model.ChecklistStageCheckOut {
+		checklist = nil
+		if entry.CheckOut != nil {
+			checklist = entry.CheckOut.Checklist
+		}
+	}
+	if checklist == nil {
+		return ErrNotFound
+	}
+
+	for j := range checklist.Items {
+		if checklist.Items[j].Key == key {
+			checklist.Items[j].Photo = path
+			return nil
+		}
+	}
+	return ErrNotFound
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryCarRepository struct {
+	db *memoryDB
+}
+
+func (r *memoryCarRepository) indexOf(id primitive.ObjectID) int {
+	for i, car := range r.db.cars {
+		if car.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *memoryCarRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Car, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	car := r.db.cars[i]
+	return &car, nil
+}
+
+func (r *memoryCarRepository) List(ctx context.Context, filter CarFilter) ([]model.Car, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var cars []model.Car
+	for _, car := range r.db.cars {
+		if car.IsActive != filter.IsActive {
+			continue
+		}
+		if !matchesSearch(filter.Search, car.Plate, car.Brand, car.Model) {
+			continue
+		}
+		cars = append(cars, car)
+	}
+	return cars, nil
+}
+
+func (r *memoryCarRepository) Create(ctx context.Context, car *model.Car) error {
+	defer r.db.lock(ctx)()
+
+	r.db.cars = append(r.db.cars, *car)
+	return nil
+}
+
+func (r *memoryCarRepository) Update(ctx context.Context, id primitive.ObjectID, updates map[string]interface{}) (*model.Car, error) {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	car := r.db.cars[i]
+	if err := applyUpdates(&car, updates); err != nil {
+		return nil, err
+	}
+	r.db.cars[i] = car
+	return &car, nil
+}
+
+func (r *memoryCarRepository) SetActive(ctx context.Context, id primitive.ObjectID, active bool) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.cars[i].IsActive = active
+	return nil
+}
+
+func (r *memoryCarRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.cars = append(r.db.cars[:i], r.db.cars[i+1:]...)
+	return nil
+}
+
+func (r *memoryCarRepository) CountActive(ctx context.Context) (int64, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var count int64
+	for _, car := range r.db.cars {
+		if car.IsActive {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryCarRepository) Statistics(ctx context.Context) ([]model.CarStatistics, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var cars []model.CarStatistics
+	for _, car := range r.db.cars {
+		if !car.IsActive {
+			continue
+		}
+
+		stats := model.CarStatistics{Car: car}
+
+		var lastRefuel, lastFuel *model.Fuel
+		for i := range r.db.fuels {
+			fuel := &r.db.fuels[i]
+			if fuel.CarID != car.ID {
+				continue
+			}
+			if lastFuel == nil || !fuel.CreatedAt.Before(lastFuel.CreatedAt) {
+				lastFuel = fuel
+			}
+			if fuel.Type == model.FuelTypeRefuel && (lastRefuel == nil || !fuel.CreatedAt.Before(lastRefuel.CreatedAt)) {
+				lastRefuel = fuel
+			}
+		}
+		if lastRefuel != nil {
+			createdAt := lastRefuel.CreatedAt
+			stats.LastRefuelCreatedAt = &createdAt
+		}
+		if lastFuel != nil {
+			currentFuel := lastFuel.NewFuel
+			stats.CurrentFuel = &currentFuel
+		}
+
+		var lastEntry *model.CarEntry
+		for i := range r.db.carEntries {
+			entry := &r.db.carEntries[i]
+			if entry.CarID != car.ID {
+				continue
+			}
+			if lastEntry == nil || !entry.StartedAt.Before(lastEntry.StartedAt) {
+				lastEntry = entry
+			}
+		}
+		if lastEntry != nil {
+			if lastEntry.CheckOut != nil {
+				checkOut := *lastEntry.CheckOut
+				stats.Checkout = &checkOut
+			}
+			stats.InUse = lastEntry.CheckOut == nil
+		}
+
+		cars = append(cars, stats)
+	}
+	return cars, nil
+}
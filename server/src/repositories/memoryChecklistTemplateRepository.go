@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryChecklistTemplateRepository struct {
+	db *memoryDB
+}
+
+func cloneChecklistTemplate(template model.ChecklistTemplate) model.ChecklistTemplate {
+	template.Stages = append([]string(nil), template.Stages...)
+	template.Categories = append([]string(nil), template.Categories...)
+	template.CarIDs = append([]primitive.ObjectID(nil), template.CarIDs...)
+	template.Items = append([]model.ChecklistTemplateItem(nil), template.Items...)
+	return template
+}
+
+func cloneChecklistTemplateVersion(version model.ChecklistTemplateVersion) model.ChecklistTemplateVersion {
+	version.Stages = append([]string(nil), version.Stages...)
+	version.Categories = append([]string(nil), version.Categories...)
+	version.CarIDs = append([]primitive.ObjectID(nil), version.CarIDs...)
+	version.Items = append([]model.ChecklistTemplateItem(nil), version.Items...)
+	return version
+}
+
+func (r *memoryChecklistTemplateRepository) indexOf(id primitive.ObjectID) int {
+	for i, template := range r.db.checklistTemplates {
+		if template.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *memoryChecklistTemplateRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.ChecklistTemplate, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	template := cloneChecklistTemplate(r.db.checklistTemplates[i])
+	return &template, nil
+}
+
+func (r *memoryChecklistTemplateRepository) List(ctx context.Context, filter ChecklistTemplateFilter) ([]model.ChecklistTemplate, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var templates []model.ChecklistTemplate
+	for _, template := range r.db.checklistTemplates {
+		if template.IsActive != filter.IsActive {
+			continue
+		}
+		if filter.Stage != "" && !containsString(template.Stages, filter.Stage) {
+			continue
+		}
+		templates = append(templates, cloneChecklistTemplate(template))
+	}
+	sort.SliceStable(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+func (r *memoryChecklistTemplateRepository) Create(ctx context.Context, template *model.ChecklistTemplate) error {
+	defer r.db.lock(ctx)()
+
+	r.db.checklistTemplates = append(r.db.checklistTemplates, cloneChecklistTemplate(*template))
+	return nil
+}
+
+func (r *memoryChecklistTemplateRepository) Replace(ctx context.Context, template *model.ChecklistTemplate) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(template.ID)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.checklistTemplates[i] = cloneChecklistTemplate(*template)
+	return nil
+}
+
+func (r *memoryChecklistTemplateRepository) SetActive(ctx context.Context, id primitive.ObjectID, active bool) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.checklistTemplates[i].IsActive = active
+	return nil
+}
+
+func (r *memoryChecklistTemplateRepository) CreateVersion(ctx context.Context, version *model.ChecklistTemplateVersion) error {
+	defer r.db.lock(ctx)()
+
+	for _, v := range r.db.checklistTemplateVersions {
+		if v.TemplateID == version.TemplateID && v.Version == version.Version {
+			return ErrChecklistVersionExists
+		}
+	}
+	r.db.checklistTemplateVersions = append(r.db.checklistTemplateVersions, cloneChecklistTemplateVersion(*version))
+	return nil
+}
+
+func (r *memoryChecklistTemplateRepository) FindVersion(ctx context.Context, templateID primitive.ObjectID, version int) (*model.ChecklistTemplateVersion, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, v := range r.db.checklistTemplateVersions {
+		if v.TemplateID == templateID && v.Version == version {
+			result := cloneChecklistTemplateVersion(v)
+			return &result, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryChecklistTemplateRepository) ListVersions(ctx context.Context, templateID primitive.ObjectID) ([]model.ChecklistTemplateVersion, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var versions []model.ChecklistTemplateVersion
+	for _, v := range r.db.checklistTemplateVersions {
+		if v.TemplateID == templateID {
+			versions = append(versions, cloneChecklistTemplateVersion(v))
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+	return versions, nil
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryCostCenterRepository struct {
+	db *memoryDB
+}
+
+func (r *memoryCostCenterRepository) find(match func(model.CostCenter) bool) (*model.CostCenter, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, costCenter := range r.db.costCenters {
+		if match(costCenter) {
+			return &costCenter, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryCostCenterRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.CostCenter, error) {
+	return r.find(func(costCenter model.CostCenter) bool { return costCenter.ID == id })
+}
+
+func (r *memoryCostCenterRepository) FindByCode(ctx context.Context, code string) (*model.CostCenter, error) {
+	return r.find(func(costCenter model.CostCenter) bool { return costCenter.Code == code })
+}
+
+func (r *memoryCostCenterRepository) List(ctx context.Context, isActive bool) ([]model.CostCenter, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var costCenters []model.CostCenter
+	for _, costCenter := range r.db.costCenters {
+		if costCenter.IsActive == isActive {
+			costCenters = append(costCenters, costCenter)
+		}
+	}
+	sort.SliceStable(costCenters, func(i, j int) bool {
+		return costCenters[i].Code < costCenters[j].Code
+	})
+	return costCenters, nil
+}
+
+func (r *memoryCostCenterRepository) Create(ctx context.Context, costCenter *model.CostCenter) error {
+	defer r.db.lock(ctx)()
+
+	for _, existing := range r.db.costCenters {
+		if existing.Code == costCenter.Code {
+			return ErrCostCenterCodeExists
+		}
+	}
+	r.db.costCenters = append(r.db.costCenters, *costCenter)
+	return nil
+}
+
+func (r *memoryCostCenterRepository) Replace(ctx context.Context, costCenter *model.CostCenter) error {
+	defer r.db.lock(ctx)()
+
+	for i, existing := range r.db.costCenters {
+		if existing.ID == costCenter.ID {
+			r.db.costCenters[i] = *costCenter
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryCostCenterRepository) SetActive(ctx context.Context, id primitive.ObjectID, active bool) error {
+	defer r.db.lock(ctx)()
+
+	for i, existing := range r.db.costCenters {
+		if existing.ID == id {
+			r.db.costCenters[i].IsActive = active
+			return nil
+		}
+	}
+	return ErrNotFound
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryDamageRepository struct {
+	db *memoryDB
+}
+
+func cloneDamage(damage model.Damage) model.Damage {
+	damage.Photos = append([]string(nil), damage.Photos...)
+	damage.History = append([]model.DamageEvent(nil), damage.History...)
+	return damage
+}
+
+func (r *memoryDamageRepository) indexOf(id primitive.ObjectID) int {
+	for i, damage := range r.db.damages {
+		if damage.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *memoryDamageRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Damage, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	damage := cloneDamage(r.db.damages[i])
+	return &damage, nil
+}
+
+func (r *memoryDamageRepository) List(ctx context.Context, filter DamageFilter) ([]model.Damage, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var damages []model.Damage
+	for _, damage := range r.db.damages {
+		if filter.CarID != nil && damage.CarID != *filter.CarID {
+			continue
+		}
+		if filter.CarEntryID != nil && (damage.CarEntryID == nil || *damage.CarEntryID != *filter.CarEntryID) {
+			continue
+		}
+		if filter.Stage != "" && damage.Stage != filter.Stage {
+			continue
+		}
+		if filter.ChecklistItemKey != "" && damage.ChecklistItemKey != filter.ChecklistItemKey {
+			continue
+		}
+		if len(filter.Status) > 0 && !containsString(filter.Status, damage.Status) {
+			continue
+		}
+		damages = append(damages, cloneDamage(damage))
+	}
+	sort.SliceStable(damages, func(i, j int) bool {
+		return damages[i].CreatedAt.After(damages[j].CreatedAt)
+	})
+	return damages, nil
+}
+
+func (r *memoryDamageRepository) Create(ctx context.Context, damage *model.Damage) error {
+	defer r.db.lock(ctx)()
+
+	r.db.damages = append(r.db.damages, cloneDamage(*damage))
+	return nil
+}
+
+func (r *memoryDamageRepository) AddEvent(ctx context.Context, id primitive.ObjectID, from []string, event model.DamageEvent) (*model.Damage, error) {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 || !containsString(from, r.db.damages[i].Status) {
+		return nil, ErrNotFound
+	}
+
+	damage := &r.db.damages[i]
+	if event.Status != "" {
+		damage.Status = event.Status
+	}
+	if event.AssigneeID != nil {
+		damage.AssigneeID = event.AssigneeID
+	}
+	damage.UpdatedAt = event.At
+	damage.History = append(damage.History, event)
+
+	updated := cloneDamage(*damage)
+	return &updated, nil
+}
+
+func (r *memoryDamageRepository) AddPhotos(ctx context.Context, id primitive.ObjectID, paths []string) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.damages[i].Photos = append(r.db.damages[i].Photos, paths...)
+	return nil
+}
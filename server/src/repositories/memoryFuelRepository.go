@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryFuelRepository struct {
+	db *memoryDB
+}
+
+func (r *memoryFuelRepository) indexOf(id primitive.ObjectID) int {
+	for i, fuel := range r.db.fuels {
+		if fuel.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *memoryFuelRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Fuel, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	fuel := r.db.fuels[i]
+	return &fuel, nil
+}
+
+func (r *memoryFuelRepository) FindLatestByCar(ctx context.Context, carID primitive.ObjectID) (*model.Fuel, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var latest *model.Fuel
+	for i := range r.db.fuels {
+		fuel := &r.db.fuels[i]
+		if fuel.CarID != carID {
+			continue
+		}
+		if latest == nil || !fuel.CreatedAt.Before(latest.CreatedAt) {
+			latest = fuel
+		}
+	}
+	if latest == nil {
+		return nil, ErrNotFound
+	}
+
+	fuel := *latest
+	return &fuel, nil
+}
+
+func (r *memoryFuelRepository) Create(ctx context.Context, fuel *model.Fuel) error {
+	defer r.db.lock(ctx)()
+
+	r.db.fuels = append(r.db.fuels, *fuel)
+	return nil
+}
+
+func (r *memoryFuelRepository) ListByCar(ctx context.Context, carID primitive.ObjectID) ([]model.Fuel, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var fuels []model.Fuel
+	for _, fuel := range r.db.fuels {
+		if fuel.CarID == carID {
+			fuels = append(fuels, fuel)
+		}
+	}
+	sort.SliceStable(fuels, func(i, j int) bool {
+		return fuels[i].CreatedAt.Before(fuels[j].CreatedAt)
+	})
+	return fuels, nil
+}
+
+func (r *memoryFuelRepository) ListRefuelsBetween(ctx context.Context, from, to time.Time) ([]model.Fuel, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var fuels []model.Fuel
+	for _, fuel := range r.db.fuels {
+		if fuel.Type != model.FuelTypeRefuel {
+			continue
+		}
+		if fuel.CreatedAt.Before(from) || !fuel.CreatedAt.Before(to) {
+			continue
+		}
+		fuels = append(fuels, fuel)
+	}
+	sort.SliceStable(fuels, func(i, j int) bool {
+		return fuels[i].CreatedAt.Before(fuels[j].CreatedAt)
+	})
+	return fuels, nil
+}
+
+// LockCar não faz nada: o memoryTransactor já serializa as transações.
+func (r *memoryFuelRepository) LockCar(ctx context.Context, carID primitive.ObjectID) error {
+	return nil
+}
+
+func (r *memoryFuelRepository) ReplaceByCar(ctx context.Context, carID primitive.ObjectID, fuels []model.Fuel) error {
+	defer r.db.lock(ctx)()
+
+	kept := r.db.fuels[:0:0]
+	for _, fuel := range r.db.fuels {
+		if fuel.CarID != carID {
+			kept = append(kept, fuel)
+		}
+	}
+	r.db.fuels = append(kept, fuels...)
+	return nil
+}
+
+func (r *memoryFuelRepository) SetReceiptImage(ctx context.Context, id primitive.ObjectID, path string) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.fuels[i].ReceiptImage = path
+	return nil
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	model "server/src/models"
+)
+
+type memoryLoginAttemptRepository struct {
+	db *memoryDB
+}
+
+func cloneLoginThrottle(throttle model.LoginThrottle) model.LoginThrottle {
+	if throttle.LockedUntil != nil {
+		lockedUntil := *throttle.LockedUntil
+		throttle.LockedUntil = &lockedUntil
+	}
+	return throttle
+}
+
+func (r *memoryLoginAttemptRepository) ListAttempts(ctx context.Context, filter LoginAttemptFilter) ([]model.LoginAttempt, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var attempts []model.LoginAttempt
+	for _, attempt := range r.db.loginAttempts {
+		if filter.Email != "" && attempt.Email != filter.Email {
+			continue
+		}
+		if filter.IP != "" && attempt.IP != filter.IP {
+			continue
+		}
+		if filter.UserID != nil && (attempt.UserID == nil || *attempt.UserID != *filter.UserID) {
+			continue
+		}
+		if filter.Result != "" && attempt.Result != filter.Result {
+			continue
+		}
+		if filter.From != nil && attempt.CreatedAt.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && !attempt.CreatedAt.Before(*filter.To) {
+			continue
+		}
+		attempts = append(attempts, attempt)
+	}
+	sort.SliceStable(attempts, func(i, j int) bool {
+		return attempts[i].CreatedAt.After(attempts[j].CreatedAt)
+	})
+	if filter.Limit > 0 && int64(len(attempts)) > filter.Limit {
+		attempts = attempts[:filter.Limit]
+	}
+	return attempts, nil
+}
+
+func (r *memoryLoginAttemptRepository) CreateAttempt(ctx context.Context, attempt *model.LoginAttempt) error {
+	defer r.db.lock(ctx)()
+
+	r.db.loginAttempts = append(r.db.loginAttempts, *attempt)
+	return nil
+}
+
+func (r *memoryLoginAttemptRepository) FindThrottle(ctx context.Context, key string) (*model.LoginThrottle, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, throttle := range r.db.loginThrottles {
+		if throttle.Key == key {
+			throttle = cloneLoginThrottle(throttle)
+			return &throttle, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryLoginAttemptRepository) RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration, maxFailures int) (*model.LoginThrottle, error) {
+	defer r.db.lock(ctx)()
+
+	var throttle *model.LoginThrottle
+	for i := range r.db.loginThrottles {
+		if r.db.loginThrottles[i].Key == key {
+			throttle = &r.db.loginThrottles[i]
+			break
+		}
+	}
+	if throttle == nil {
+		r.db.loginThrottles = append(r.db.loginThrottles, model.LoginThrottle{Key: key})
+		throttle = &r.db.loginThrottles[len(r.db.loginThrottles)-1]
+	}
+	if !now.Before(throttle.ExpiresAt) {
+		throttle.Failures = 0
+		throttle.LockedUntil = nil
+	}
+
+	throttle.Failures++
+	throttle.LastFailureAt = now
+	throttle.ExpiresAt = now.Add(window)
+	if throttle.LockedUntil == nil && throttle.Failures >= maxFailures {
+		lockedUntil := now.Add(window)
+		throttle.LockedUntil = &lockedUntil
+	}
+	if throttle.LockedUntil != nil && throttle.LockedUntil.After(throttle.ExpiresAt) {
+		throttle.ExpiresAt = *throttle.LockedUntil
+	}
+
+	result := cloneLoginThrottle(*throttle)
+	return &result, nil
+}
+
+func (r *memoryLoginAttemptRepository) DeleteThrottle(ctx context.Context, key string) error {
+	defer r.db.lock(ctx)()
+
+	for i := range r.db.loginThrottles {
+		if r.db.loginThrottles[i].Key == key {
+			r.db.loginThrottles = append(r.db.loginThrottles[:i], r.db.loginThrottles[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryMaintenanceRepository struct {
+	db *memoryDB
+}
+
+func cloneMaintenanceRecord(record model.MaintenanceRecord) model.MaintenanceRecord {
+	record.Invoices = append([]string(nil), record.Invoices...)
+	return record
+}
+
+func (r *memoryMaintenanceRepository) planIndex(id primitive.ObjectID) int {
+	for i, plan := range r.db.maintenancePlans {
+		if plan.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *memoryMaintenanceRepository) recordIndex(id primitive.ObjectID) int {
+	for i, record := range r.db.maintenanceRecords {
+		if record.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *memoryMaintenanceRepository) FindPlanByID(ctx context.Context, id primitive.ObjectID) (*model.MaintenancePlan, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i := r.planIndex(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	plan := r.db.maintenancePlans[i]
+	return &plan, nil
+}
+
+func (r *memoryMaintenanceRepository) ListPlans(ctx context.Context, isActive bool) ([]model.MaintenancePlan, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var plans []model.MaintenancePlan
+	for _, plan := range r.db.maintenancePlans {
+		if plan.IsActive == isActive {
+			plans = append(plans, plan)
+		}
+	}
+	sort.SliceStable(plans, func(i, j int) bool {
+		return plans[i].Name < plans[j].Name
+	})
+	return plans, nil
+}
+
+func (r *memoryMaintenanceRepository) CreatePlan(ctx context.Context, plan *model.MaintenancePlan) error {
+	defer r.db.lock(ctx)()
+
+	r.db.maintenancePlans = append(r.db.maintenancePlans, *plan)
+	return nil
+}
+
+func (r *memoryMaintenanceRepository) ReplacePlan(ctx context.Context, plan *model.MaintenancePlan) error {
+	defer r.db.lock(ctx)()
+
+	i := r.planIndex(plan.ID)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.maintenancePlans[i] = *plan
+	return nil
+}
+
+func (r *memoryMaintenanceRepository) SetPlanActive(ctx context.Context, id primitive.ObjectID, active bool) error {
+	defer r.db.lock(ctx)()
+
+	i := r.planIndex(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.maintenancePlans[i].IsActive = active
+	return nil
+}
+
+func (r *memoryMaintenanceRepository) FindRecordByID(ctx context.Context, id primitive.ObjectID) (*model.MaintenanceRecord, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i := r.recordIndex(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	record := cloneMaintenanceRecord(r.db.maintenanceRecords[i])
+	return &record, nil
+}
+
+func (r *memoryMaintenanceRepository) ListRecords(ctx context.Context, filter MaintenanceRecordFilter) ([]model.MaintenanceRecord, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var records []model.MaintenanceRecord
+	for _, record := range r.db.maintenanceRecords {
+		if filter.CarID != nil && record.CarID != *filter.CarID {
+			continue
+		}
+		if filter.PlanID != nil && (record.PlanID == nil || *record.PlanID != *filter.PlanID) {
+			continue
+		}
+		if filter.InProgress != nil && *filter.InProgress != (record.CompletedAt == nil) {
+			continue
+		}
+		records = append(records, cloneMaintenanceRecord(record))
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if (a.CompletedAt == nil) != (b.CompletedAt == nil) {
+			return b.CompletedAt == nil
+		}
+		if a.CompletedAt != nil && !a.CompletedAt.Equal(*b.CompletedAt) {
+			return a.CompletedAt.After(*b.CompletedAt)
+		}
+		return a.StartedAt.After(b.StartedAt)
+	})
+	return records, nil
+}
+
+func (r *memoryMaintenanceRepository) CreateRecord(ctx context.Context, record *model.MaintenanceRecord) error {
+	defer r.db.lock(ctx)()
+
+	r.db.maintenanceRecords = append(r.db.maintenanceRecords, cloneMaintenanceRecord(*record))
+	return nil
+}
+
+func (r *memoryMaintenanceRepository) CompleteRecord(ctx context.Context, id primitive.ObjectID, completedAt time.Time, odometer, cost float64) (*model.MaintenanceRecord, error) {
+	defer r.db.lock(ctx)()
+
+	i := r.recordIndex(id)
+	if i < 0 || r.db.maintenanceRecords[i].CompletedAt != nil {
+		return nil, ErrNotFound
+	}
+
+	record := &r.db.maintenanceRecords[i]
+	record.CompletedAt = &completedAt
+	record.Odometer = odometer
+	record.Cost = cost
+
+	updated := cloneMaintenanceRecord(*record)
+	return &updated, nil
+}
+
+func (r *memoryMaintenanceRepository) AddRecordInvoices(ctx context.Context, id primitive.ObjectID, paths []string) error {
+	defer r.db.lock(ctx)()
+
+	i := r.recordIndex(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.maintenanceRecords[i].Invoices = append(r.db.maintenanceRecords[i].Invoices, paths...)
+	return nil
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryPasswordResetRepository struct {
+	db *memoryDB
+}
+
+func clonePasswordReset(reset model.PasswordReset) model.PasswordReset {
+	if reset.UsedAt != nil {
+		usedAt := *reset.UsedAt
+		reset.UsedAt = &usedAt
+	}
+	return reset
+}
+
+func (r *memoryPasswordResetRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.PasswordReset, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, reset := range r.db.passwordResets {
+		if reset.TokenHash == tokenHash {
+			reset = clonePasswordReset(reset)
+			return &reset, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryPasswordResetRepository) FindLatestByUser(ctx context.Context, userID primitive.ObjectID) (*model.PasswordReset, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var latest *model.PasswordReset
+	for _, reset := range r.db.passwordResets {
+		if reset.UserID == userID && (latest == nil || reset.CreatedAt.After(latest.CreatedAt)) {
+			reset = clonePasswordReset(reset)
+			latest = &reset
+		}
+	}
+	if latest == nil {
+		return nil, ErrNotFound
+	}
+	return latest, nil
+}
+
+func (r *memoryPasswordResetRepository) Create(ctx context.Context, reset *model.PasswordReset) error {
+	defer r.db.lock(ctx)()
+
+	r.db.passwordResets = append(r.db.passwordResets, clonePasswordReset(*reset))
+	return nil
+}
+
+func (r *memoryPasswordResetRepository) MarkUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	defer r.db.lock(ctx)()
+
+	for i := range r.db.passwordResets {
+		reset := &r.db.passwordResets[i]
+		if reset.ID == id && reset.UsedAt == nil {
+			reset.UsedAt = &at
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryPasswordResetRepository) InvalidateUser(ctx context.Context, userID primitive.ObjectID, at time.Time) error {
+	defer r.db.lock(ctx)()
+
+	for i := range r.db.passwordResets {
+		reset := &r.db.passwordResets[i]
+		if reset.UserID == userID && reset.UsedAt == nil {
+			usedAt := at
+			reset.UsedAt = &usedAt
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryRefreshTokenRepository struct {
+	db *memoryDB
+}
+
+func cloneRefreshToken(token model.RefreshToken) model.RefreshToken {
+	if token.UsedAt != nil {
+		usedAt := *token.UsedAt
+		token.UsedAt = &usedAt
+	}
+	if token.ReplacedBy != nil {
+		replacedBy := *token.ReplacedBy
+		token.ReplacedBy = &replacedBy
+	}
+	if token.RevokedAt != nil {
+		revokedAt := *token.RevokedAt
+		token.RevokedAt = &revokedAt
+	}
+	return token
+}
+
+func (r *memoryRefreshTokenRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.RefreshToken, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, token := range r.db.refreshTokens {
+		if token.ID == id {
+			token = cloneRefreshToken(token)
+			return &token, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryRefreshTokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
+	defer r.db.lock(ctx)()
+
+	r.db.refreshTokens = append(r.db.refreshTokens, cloneRefreshToken(*token))
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) MarkUsed(ctx context.Context, id, replacedBy primitive.ObjectID, at time.Time) error {
+	defer r.db.lock(ctx)()
+
+	for i := range r.db.refreshTokens {
+		token := &r.db.refreshTokens[i]
+		if token.ID != id {
+			continue
+		}
+		if token.UsedAt != nil || token.RevokedAt != nil {
+			return ErrNotFound
+		}
+		token.UsedAt = &at
+		token.ReplacedBy = &replacedBy
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (r *memoryRefreshTokenRepository) revoke(ctx context.Context, match func(model.RefreshToken) bool, reason string, at time.Time) {
+	defer r.db.lock(ctx)()
+
+	for i := range r.db.refreshTokens {
+		token := &r.db.refreshTokens[i]
+		if token.RevokedAt == nil && match(*token) {
+			token.RevokedAt = &at
+			token.RevokedReason = reason
+		}
+	}
+}
+
+func (r *memoryRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID primitive.ObjectID, reason string, at time.Time) error {
+	r.revoke(ctx, func(token model.RefreshToken) bool { return token.FamilyID == familyID }, reason, at)
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) RevokeUser(ctx context.Context, userID primitive.ObjectID, reason string, at time.Time) error {
+	r.revoke(ctx, func(token model.RefreshToken) bool { return token.UserID == userID }, reason, at)
+	return nil
+}
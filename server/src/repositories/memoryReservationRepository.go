@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryReservationRepository struct {
+	db *memoryDB
+}
+
+func (r *memoryReservationRepository) indexOf(id primitive.ObjectID) int {
+	for i, reservation := range r.db.reservations {
+		if reservation.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *memoryReservationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Reservation, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	reservation := r.db.reservations[i]
+	return &reservation, nil
+}
+
+func (r *memoryReservationRepository) List(ctx context.Context, filter ReservationFilter) ([]model.Reservation, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var reservations []model.Reservation
+	for _, reservation := range r.db.reservations {
+		if filter.CarID != nil && reservation.CarID != *filter.CarID {
+			continue
+		}
+		if filter.UserID != nil && reservation.UserID != *filter.UserID {
+			continue
+		}
+		if len(filter.Status) > 0 && !containsString(filter.Status, reservation.Status) {
+			continue
+		}
+		if filter.From != nil && !reservation.End.After(*filter.From) {
+			continue
+		}
+		if filter.To != nil && !reservation.Start.Before(*filter.To) {
+			continue
+		}
+		reservations = append(reservations, reservation)
+	}
+	sort.SliceStable(reservations, func(i, j int) bool {
+		return reservations[i].Start.Before(reservations[j].Start)
+	})
+	return reservations, nil
+}
+
+func (r *memoryReservationRepository) Create(ctx context.Context, reservation *model.Reservation) error {
+	defer r.db.lock(ctx)()
+
+	r.db.reservations = append(r.db.reservations, *reservation)
+	return nil
+}
+
+// LockCar não faz nada: o memoryTransactor já serializa as transações.
+func (r *memoryReservationRepository) LockCar(ctx context.Context, carID primitive.ObjectID) error {
+	return nil
+}
+
+func (r *memoryReservationRepository) Transition(ctx context.Context, id primitive.ObjectID, from []string, change model.ReservationChange) (*model.Reservation, error) {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 || !containsString(from, r.db.reservations[i].Status) {
+		return nil, ErrNotFound
+	}
+
+	reservation := &r.db.reservations[i]
+	reservation.Status = change.Status
+	reservation.UpdatedAt = change.At
+	if change.CarEntryID != nil {
+		reservation.CarEntryID = change.CarEntryID
+	}
+	if change.DecidedBy != nil {
+		at := change.At
+		reservation.DecidedBy = change.DecidedBy
+		reservation.DecidedAt = &at
+		reservation.DecisionNote = change.Note
+	}
+
+	updated := *reservation
+	return &updated, nil
+}
+
+func (r *memoryReservationRepository) MarkNoShows(ctx context.Context, startedBefore, now time.Time) (int64, error) {
+	defer r.db.lock(ctx)()
+
+	var count int64
+	for i := range r.db.reservations {
+		reservation := &r.db.reservations[i]
+		if reservation.Status == model.ReservationApproved && reservation.Start.Before(startedBefore) {
+			reservation.Status = model.ReservationNoShow
+			reservation.UpdatedAt = now
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memorySessionRepository struct {
+	db *memoryDB
+}
+
+func cloneSession(session model.Session) model.Session {
+	if session.RevokedAt != nil {
+		revokedAt := *session.RevokedAt
+		session.RevokedAt = &revokedAt
+	}
+	if session.RevokedBy != nil {
+		revokedBy := *session.RevokedBy
+		session.RevokedBy = &revokedBy
+	}
+	return session
+}
+
+func (r *memorySessionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Session, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, session := range r.db.sessions {
+		if session.ID == id {
+			session = cloneSession(session)
+			return &session, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memorySessionRepository) ListByUser(ctx context.Context, userID primitive.ObjectID) ([]model.Session, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var sessions []model.Session
+	for _, session := range r.db.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, cloneSession(session))
+		}
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	return sessions, nil
+}
+
+func (r *memorySessionRepository) Create(ctx context.Context, session *model.Session) error {
+	defer r.db.lock(ctx)()
+
+	r.db.sessions = append(r.db.sessions, cloneSession(*session))
+	return nil
+}
+
+func (r *memorySessionRepository) Touch(ctx context.Context, id primitive.ObjectID, at time.Time, ip string, expiresAt *time.Time) error {
+	defer r.db.lock(ctx)()
+
+	for i := range r.db.sessions {
+		session := &r.db.sessions[i]
+		if session.ID == id {
+			session.LastSeenAt = at
+			session.LastSeenIP = ip
+			if expiresAt != nil {
+				session.ExpiresAt = *expiresAt
+			}
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func revokeMemorySession(session *model.Session, by *primitive.ObjectID, reason string, at time.Time) {
+	session.RevokedAt = &at
+	session.RevokedReason = reason
+	if by != nil {
+		revokedBy := *by
+		session.RevokedBy = &revokedBy
+	}
+}
+
+func (r *memorySessionRepository) Revoke(ctx context.Context, id primitive.ObjectID, by *primitive.ObjectID, reason string, at time.Time) error {
+	defer r.db.lock(ctx)()
+
+	for i := range r.db.sessions {
+		session := &r.db.sessions[i]
+		if session.ID == id && session.RevokedAt == nil {
+			revokeMemorySession(session, by, reason, at)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memorySessionRepository) RevokeUser(ctx context.Context, userID primitive.ObjectID, by *primitive.ObjectID, reason string, at time.Time) error {
+	defer r.db.lock(ctx)()
+
+	for i := range r.db.sessions {
+		session := &r.db.sessions[i]
+		if session.UserID == userID && session.RevokedAt == nil {
+			revokeMemorySession(session, by, reason, at)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type memoryDB struct {
+	txMu                      sync.Mutex
+	mu                        sync.RWMutex
+	cars                      []model.Car
+	users                     []model.User
+	carEntries                []model.CarEntry
+	fuels                     []model.Fuel
+	alerts                    []model.Alert
+	checklistTemplates        []model.ChecklistTemplate
+	checklistTemplateVersions []model.ChecklistTemplateVersion
+	damages                   []model.Damage
+	maintenancePlans          []model.MaintenancePlan
+	maintenanceRecords        []model.MaintenanceRecord
+	carDocuments              []model.CarDocument
+	reservations              []model.Reservation
+	costCenters               []model.CostCenter
+	refreshTokens             []model.RefreshToken
+	sessions                  []model.Session
+	loginAttempts             []model.LoginAttempt
+	loginThrottles            []model.LoginThrottle
+	passwordResets            []model.PasswordReset
+}
+
+func NewMemoryStore() *Store {
+	db := &memoryDB{}
+
+	return &Store{
+		Cars:               &memoryCarRepository{db: db},
+		Users:              &memoryUserRepository{db: db},
+		CarEntries:         &memoryCarEntryRepository{db: db},
+		Fuels:              &memoryFuelRepository{db: db},
+		Alerts:             &memoryAlertRepository{db: db},
+		ChecklistTemplates: &memoryChecklistTemplateRepository{db: db},
+		Damages:            &memoryDamageRepository{db: db},
+		Maintenance:        &memoryMaintenanceRepository{db: db},
+		CarDocuments:       &memoryCarDocumentRepository{db: db},
+		Reservations:       &memoryReservationRepository{db: db},
+		CostCenters:        &memoryCostCenterRepository{db: db},
+		RefreshTokens:      &memoryRefreshTokenRepository{db: db},
+		Sessions:           &memorySessionRepository{db: db},
+		LoginAttempts:      &memoryLoginAttemptRepository{db: db},
+		PasswordResets:     &memoryPasswordResetRepository{db: db},
+		Transactor:         &memoryTransactor{db: db},
+	}
+}
+
+type memorySnapshot struct {
+	cars                      []model.Car
+	users                     []model.User
+	carEntries                []model.CarEntry
+	fuels                     []model.Fuel
+	alerts                    []model.Alert
+	checklistTemplates        []model.ChecklistTemplate
+	checklistTemplateVersions []model.ChecklistTemplateVersion
+	damages                   []model.Damage
+	maintenancePlans          []model.MaintenancePlan
+	maintenanceRecords        []model.MaintenanceRecord
+	carDocuments              []model.CarDocument
+	reservations              []model.Reservation
+	costCenters               []model.CostCenter
+	refreshTokens             []model.RefreshToken
+	sessions                  []model.Session
+	loginAttempts             []model.LoginAttempt
+	loginThrottles            []model.LoginThrottle
+	passwordResets            []model.PasswordReset
+}
+
+func (db *memoryDB) snapshot() memorySnapshot {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	s := memorySnapshot{
+		cars:   append([]model.Car(nil), db.cars...),
+		users:  append([]model.User(nil), db.users...),
+		fuels:  append([]model.Fuel(nil), db.fuels...),
+		alerts: append([]model.Alert(nil), db.alerts...),
+	}
+	for _, entry := range db.carEntries {
+		s.carEntries = append(s.carEntries, cloneCarEntry(entry))
+	}
+	for _, template := range db.checklistTemplates {
+		s.checklistTemplates = append(s.checklistTemplates, cloneChecklistTemplate(template))
+	}
+	for _, version := range db.checklistTemplateVersions {
+		s.checklistTemplateVersions = append(s.checklistTemplateVersions, cloneChecklistTemplateVersion(version))
+	}
+	for _, damage := range db.damages {
+		s.damages = append(s.damages, cloneDamage(damage))
+	}
+	s.maintenancePlans = append([]model.MaintenancePlan(nil), db.maintenancePlans...)
+	for _, record := range db.maintenanceRecords {
+		s.maintenanceRecords = append(s.maintenanceRecords, cloneMaintenanceRecord(record))
+	}
+	for _, document := range db.carDocuments {
+		s.carDocuments = append(s.carDocuments, cloneCarDocument(document))
+	}
+	s.reservations = append([]model.Reservation(nil), db.reservations...)
+	s.costCenters = append([]model.CostCenter(nil), db.costCenters...)
+	for _, token := range db.refreshTokens {
+		s.refreshTokens = append(s.refreshTokens, cloneRefreshToken(token))
+	}
+	for _, session := range db.sessions {
+		s.sessions = append(s.sessions, cloneSession(session))
+	}
+	s.loginAttempts = append([]model.LoginAttempt(nil), db.loginAttempts...)
+	for _, throttle := range db.loginThrottles {
+		s.loginThrottles = append(s.loginThrottles, cloneLoginThrottle(throttle))
+	}
+	for _, reset := range db.passwordResets {
+		s.passwordResets = append(s.passwordResets, clonePasswordReset(reset))
+	}
+	return s
+}
+
+func (db *memoryDB) restore(s memorySnapshot) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.cars = s.cars
+	db.users = s.users
+	db.carEntries = s.carEntries
+	db.fuels = s.fuels
+	db.alerts = s.alerts
+	db.checklistTemplates = s.checklistTemplates
+	db.checklistTemplateVersions = s.checklistTemplateVersions
+	db.damages = s.damages
+	db.maintenancePlans = s.maintenancePlans
+	db.maintenanceRecords = s.maintenanceRecords
+	db.carDocuments = s.carDocuments
+	db.reservations = s.reservations
+	db.costCenters = s.costCenters
+	db.refreshTokens = s.refreshTokens
+	db.sessions = s.sessions
+	db.loginAttempts = s.loginAttempts
+	db.loginThrottles = s.loginThrottles
+	db.passwordResets = s.passwordResets
+}
+
+// memoryTxKey marca o ctx repassado ao fn de uma transação em andamento.
+type memoryTxKey struct{}
+
+// lock toma o lock de escrita e devolve a função que o libera. Fora de
+// transação, a escrita também espera as transações em andamento terminarem,
+// para que um rollback não descarte o que foi gravado nesse intervalo.
+func (db *memoryDB) lock(ctx context.Context) func() {
+	if ctx.Value(memoryTxKey{}) != nil {
+		db.mu.Lock()
+		return db.mu.Unlock
+	}
+
+	db.txMu.Lock()
+	db.mu.Lock()
+	return func() {
+		db.mu.Unlock()
+		db.txMu.Unlock()
+	}
+}
+
+// memoryTransactor serializa as transações e, em caso de erro, restaura o
+// estado anterior. Enquanto uma transação está em andamento, as escritas
+// feitas fora dela aguardam em txMu; as leituras não bloqueiam e podem ver
+// dados ainda não confirmados. Transações aninhadas não são suportadas.
+type memoryTransactor struct {
+	db *memoryDB
+}
+
+func (t *memoryTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.db.txMu.Lock()
+	defer t.db.txMu.Unlock()
+
+	before := t.db.snapshot()
+	if err := fn(context.WithValue(ctx, memoryTxKey{}, true)); err != nil {
+		t.db.restore(before)
+		return err
+	}
+	return nil
+}
+
+// applyUpdates reproduz o $set do Mongo: converte o documento para BSON,
+// sobrescreve os campos recebidos e decodifica de volta na struct.
+func applyUpdates(doc interface{}, updates map[string]interface{}) error {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	var fields bson.M
+	if err := bson.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+	for key, value := range updates {
+		fields[key] = value
+	}
+
+	raw, err = bson.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(raw, doc)
+}
+
+// matchesSearch imita o filtro $regex com $options "i" usado nas buscas.
+func matchesSearch(search string, values ...string) bool {
+	if search == "" {
+		return true
+	}
+
+	re, err := regexp.Compile("(?i)" + search)
+	for _, value := range values {
+		if err != nil {
+			if strings.Contains(strings.ToLower(value), strings.ToLower(search)) {
+				return true
+			}
+		} else if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
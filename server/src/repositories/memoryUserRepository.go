@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryUserRepository struct {
+	db *memoryDB
+}
+
+func (r *memoryUserRepository) indexOf(id primitive.ObjectID) int {
+	for i, user := range r.db.users {
+		if user.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *memoryUserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	user := r.db.users[i]
+	return &user, nil
+}
+
+func (r *memoryUserRepository) FindActiveByEmail(ctx context.Context, email string) (*model.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, user := range r.db.users {
+		if user.Email == email && user.IsActive {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, user := range r.db.users {
+		if user.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryUserRepository) List(ctx context.Context, filter UserFilter) ([]model.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var users []model.User
+	for _, user := range r.db.users {
+		if user.IsActive != filter.IsActive {
+			continue
+		}
+		if !matchesSearch(filter.Search, user.Name, user.CNH, user.Email) {
+			continue
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *model.User) error {
+	defer r.db.lock(ctx)()
+
+	r.db.users = append(r.db.users, *user)
+	return nil
+}
+
+func (r *memoryUserRepository) Update(ctx context.Context, id primitive.ObjectID, updates map[string]interface{}) (*model.User, error) {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+
+	user := r.db.users[i]
+	if err := applyUpdates(&user, updates); err != nil {
+		return nil, err
+	}
+	r.db.users[i] = user
+	return &user, nil
+}
+
+func (r *memoryUserRepository) SetActive(ctx context.Context, id primitive.ObjectID, active bool) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.users[i].IsActive = active
+	return nil
+}
+
+func (r *memoryUserRepository) IncrementTokenVersion(ctx context.Context, id primitive.ObjectID) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.users[i].TokenVersion++
+	return nil
+}
+
+func (r *memoryUserRepository) SetPassword(ctx context.Context, id primitive.ObjectID, hash string, mustChange bool) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.users[i].Password = hash
+	r.db.users[i].MustChangePassword = mustChange
+	return nil
+}
+
+func (r *memoryUserRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	defer r.db.lock(ctx)()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.db.users = append(r.db.users[:i], r.db.users[i+1:]...)
+	return nil
+}
+
+func (r *memoryUserRepository) CountActive(ctx context.Context) (int64, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var count int64
+	for _, user := range r.db.users {
+		if user.IsActive {
+			count++
+		}
+	}
+	return count, nil
+}
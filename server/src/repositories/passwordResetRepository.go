@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PasswordResetRepository interface {
+	FindByTokenHash(ctx context.Context, tokenHash string) (*model.PasswordReset, error)
+	FindLatestByUser(ctx context.Context, userID primitive.ObjectID) (*model.PasswordReset, error)
+	Create(ctx context.Context, reset *model.PasswordReset) error
+	// MarkUsed retorna ErrNotFound se o pedido não existir ou já tiver sido
+	// usado, o que garante o uso único mesmo com requisições concorrentes.
+	MarkUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	// InvalidateUser marca como usados os pedidos pendentes do usuário.
+	InvalidateUser(ctx context.Context, userID primitive.ObjectID, at time.Time) error
+}
+
+type mongoPasswordResetRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoPasswordResetRepository(db *mongo.Database) PasswordResetRepository {
+	return &mongoPasswordResetRepository{collection: db.Collection("passwordResets")}
+}
+
+func (r *mongoPasswordResetRepository) findOne(ctx context.Context, filter bson.M, opts ...*options.FindOneOptions) (*model.PasswordReset, error) {
+	var reset model.PasswordReset
+	err := r.collection.FindOne(ctx, filter, opts...).Decode(&reset)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reset, nil
+}
+
+func (r *mongoPasswordResetRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.PasswordReset, error) {
+	return r.findOne(ctx, bson.M{"tokenHash": tokenHash})
+}
+
+func (r *mongoPasswordResetRepository) FindLatestByUser(ctx context.Context, userID primitive.ObjectID) (*model.PasswordReset, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	return r.findOne(ctx, bson.M{"userID": userID}, opts)
+}
+
+func (r *mongoPasswordResetRepository) Create(ctx context.Context, reset *model.PasswordReset) error {
+	_, err := r.collection.InsertOne(ctx, reset)
+	return err
+}
+
+func (r *mongoPasswordResetRepository) MarkUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "usedAt": nil}, bson.M{"$set": bson.M{"usedAt": at}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoPasswordResetRepository) InvalidateUser(ctx context.Context, userID primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"userID": userID, "usedAt": nil}, bson.M{"$set": bson.M{"usedAt": at}})
+	return err
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type RefreshTokenRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.RefreshToken, error)
+	Create(ctx context.Context, token *model.RefreshToken) error
+	// MarkUsed registra a troca do token por replacedBy. Retorna ErrNotFound
+	// se o token já tiver sido usado ou revogado, o que identifica dois usos
+	// concorrentes do mesmo token.
+	MarkUsed(ctx context.Context, id, replacedBy primitive.ObjectID, at time.Time) error
+	RevokeFamily(ctx context.Context, familyID primitive.ObjectID, reason string, at time.Time) error
+	RevokeUser(ctx context.Context, userID primitive.ObjectID, reason string, at time.Time) error
+}
+
+type mongoRefreshTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRefreshTokenRepository(db *mongo.Database) RefreshTokenRepository {
+	return &mongoRefreshTokenRepository{collection: db.Collection("refreshTokens")}
+}
+
+func (r *mongoRefreshTokenRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *mongoRefreshTokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
+	_, err := r.collection.InsertOne(ctx, token)
+	return err
+}
+
+func (r *mongoRefreshTokenRepository) MarkUsed(ctx context.Context, id, replacedBy primitive.ObjectID, at time.Time) error {
+	filter := bson.M{"_id": id, "usedAt": nil, "revokedAt": nil}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"usedAt": at, "replacedBy": replacedBy}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoRefreshTokenRepository) revoke(ctx context.Context, filter bson.M, reason string, at time.Time) error {
+	filter["revokedAt"] = nil
+	_, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"revokedAt": at, "revokedReason": reason}})
+	return err
+}
+
+func (r *mongoRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID primitive.ObjectID, reason string, at time.Time) error {
+	return r.revoke(ctx, bson.M{"familyID": familyID}, reason, at)
+}
+
+func (r *mongoRefreshTokenRepository) RevokeUser(ctx context.Context, userID primitive.ObjectID, reason string, at time.Time) error {
+	return r.revoke(ctx, bson.M{"userID": userID}, reason, at)
+}
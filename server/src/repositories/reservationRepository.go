@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReservationFilter seleciona reservas; From e To, quando informados, mantêm
+// apenas as que se sobrepõem a [From, To).
+type ReservationFilter struct {
+	CarID  *primitive.ObjectID
+	UserID *primitive.ObjectID
+	Status []string
+	From   *time.Time
+	To     *time.Time
+}
+
+type ReservationRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.Reservation, error)
+	// List ordena pelo início da reserva.
+	List(ctx context.Context, filter ReservationFilter) ([]model.Reservation, error)
+	Create(ctx context.Context, reservation *model.Reservation) error
+	// LockCar serializa, dentro de uma transação, as reservas do mesmo carro:
+	// duas transações que o chamem para o mesmo carro entram em conflito de
+	// escrita, e a segunda é refeita já enxergando a reserva da primeira.
+	LockCar(ctx context.Context, carID primitive.ObjectID) error
+	// Transition aplica a mudança desde que o status atual esteja em from.
+	// Retorna ErrNotFound se a reserva não existir ou não estiver em from.
+	Transition(ctx context.Context, id primitive.ObjectID, from []string, change model.ReservationChange) (*model.Reservation, error)
+	// MarkNoShows passa para no_show as reservas aprovadas que começaram
+	// antes de startedBefore sem check-in.
+	MarkNoShows(ctx context.Context, startedBefore, now time.Time) (int64, error)
+}
+
+type mongoReservationRepository struct {
+	collection *mongo.Collection
+	locks      *mongo.Collection
+}
+
+func NewMongoReservationRepository(db *mongo.Database) ReservationRepository {
+	return &mongoReservationRepository{
+		collection: db.Collection("reservations"),
+		locks:      db.Collection("reservationLocks"),
+	}
+}
+
+func (r *mongoReservationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Reservation, error) {
+	var reservation model.Reservation
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&reservation)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *mongoReservationRepository) List(ctx context.Context, filter ReservationFilter) ([]model.Reservation, error) {
+	query := bson.M{}
+	if filter.CarID != nil {
+		query["carID"] = *filter.CarID
+	}
+	if filter.UserID != nil {
+		query["userID"] = *filter.UserID
+	}
+	if len(filter.Status) > 0 {
+		query["status"] = bson.M{"$in": filter.Status}
+	}
+	if filter.From != nil {
+		query["end"] = bson.M{"$gt": *filter.From}
+	}
+	if filter.To != nil {
+		query["start"] = bson.M{"$lt": *filter.To}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start", Value: 1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var reservations []model.Reservation
+	if err := cursor.All(ctx, &reservations); err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+func (r *mongoReservationRepository) Create(ctx context.Context, reservation *model.Reservation) error {
+	_, err := r.collection.InsertOne(ctx, reservation)
+	return err
+}
+
+func (r *mongoReservationRepository) LockCar(ctx context.Context, carID primitive.ObjectID) error {
+	_, err := r.locks.UpdateOne(ctx, bson.M{"_id": carID}, bson.M{"$inc": bson.M{"version": 1}}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *mongoReservationRepository) Transition(ctx context.Context, id primitive.ObjectID, from []string, change model.ReservationChange) (*model.Reservation, error) {
+	set := bson.M{"status": change.Status, "updatedAt": change.At}
+	if change.CarEntryID != nil {
+		set["carEntryID"] = change.CarEntryID
+	}
+	if change.DecidedBy != nil {
+		set["decidedBy"] = change.DecidedBy
+		set["decidedAt"] = change.At
+		set["decisionNote"] = change.Note
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var reservation model.Reservation
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id, "status": bson.M{"$in": from}}, bson.M{"$set": set}, opts).Decode(&reservation)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *mongoReservationRepository) MarkNoShows(ctx context.Context, startedBefore, now time.Time) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"status": model.ReservationApproved, "start": bson.M{"$lt": startedBefore}},
+		bson.M{"$set": bson.M{"status": model.ReservationNoShow, "updatedAt": now}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SessionRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.Session, error)
+	// ListByUser ordena pelo último acesso, do mais recente para o mais antigo.
+	ListByUser(ctx context.Context, userID primitive.ObjectID) ([]model.Session, error)
+	Create(ctx context.Context, session *model.Session) error
+	// Touch registra um acesso e, com expiresAt informado, prorroga a sessão.
+	Touch(ctx context.Context, id primitive.ObjectID, at time.Time, ip string, expiresAt *time.Time) error
+	// Revoke retorna ErrNotFound se a sessão não existir ou já estiver encerrada.
+	Revoke(ctx context.Context, id primitive.ObjectID, by *primitive.ObjectID, reason string, at time.Time) error
+	RevokeUser(ctx context.Context, userID primitive.ObjectID, by *primitive.ObjectID, reason string, at time.Time) error
+}
+
+type mongoSessionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoSessionRepository(db *mongo.Database) SessionRepository {
+	return &mongoSessionRepository{collection: db.Collection("sessions")}
+}
+
+func (r *mongoSessionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Session, error) {
+	var session model.Session
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&session)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *mongoSessionRepository) ListByUser(ctx context.Context, userID primitive.ObjectID) ([]model.Session, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "lastSeenAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"userID": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []model.Session
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *mongoSessionRepository) Create(ctx context.Context, session *model.Session) error {
+	_, err := r.collection.InsertOne(ctx, session)
+	return err
+}
+
+func (r *mongoSessionRepository) Touch(ctx context.Context, id primitive.ObjectID, at time.Time, ip string, expiresAt *time.Time) error {
+	set := bson.M{"lastSeenAt": at, "lastSeenIP": ip}
+	if expiresAt != nil {
+		set["expiresAt"] = *expiresAt
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func revokeSessionUpdate(by *primitive.ObjectID, reason string, at time.Time) bson.M {
+	set := bson.M{"revokedAt": at, "revokedReason": reason}
+	if by != nil {
+		set["revokedBy"] = *by
+	}
+	return bson.M{"$set": set}
+}
+
+func (r *mongoSessionRepository) Revoke(ctx context.Context, id primitive.ObjectID, by *primitive.ObjectID, reason string, at time.Time) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "revokedAt": nil}, revokeSessionUpdate(by, reason, at))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoSessionRepository) RevokeUser(ctx context.Context, userID primitive.ObjectID, by *primitive.ObjectID, reason string, at time.Time) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"userID": userID, "revokedAt": nil}, revokeSessionUpdate(by, reason, at))
+	return err
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrNotFound = errors.New("registro não encontrado")
+
+// Transactor executa fn como uma unidade de trabalho: se fn retornar erro,
+// nenhuma das escritas feitas pelos repositórios com o ctx recebido persiste.
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type Store struct {
+	Cars               CarRepository
+	Users              UserRepository
+	CarEntries         CarEntryRepository
+	Fuels              FuelRepository
+	Alerts             AlertRepository
+	ChecklistTemplates ChecklistTemplateRepository
+	Damages            DamageRepository
+	Maintenance        MaintenanceRepository
+	CarDocuments       CarDocumentRepository
+	Reservations       ReservationRepository
+	CostCenters        CostCenterRepository
+	RefreshTokens      RefreshTokenRepository
+	Sessions           SessionRepository
+	LoginAttempts      LoginAttemptRepository
+	PasswordResets     PasswordResetRepository
+	Transactor         Transactor
+}
+
+func NewMongoStore(db *mongo.Database) *Store {
+	return &Store{
+		Cars:               NewMongoCarRepository(db),
+		Users:              NewMongoUserRepository(db),
+		CarEntries:         NewMongoCarEntryRepository(db),
+		Fuels:              NewMongoFuelRepository(db),
+		Alerts:             NewMongoAlertRepository(db),
+		ChecklistTemplates: NewMongoChecklistTemplateRepository(db),
+		Damages:            NewMongoDamageRepository(db),
+		Maintenance:        NewMongoMaintenanceRepository(db),
+		CarDocuments:       NewMongoCarDocumentRepository(db),
+		Reservations:       NewMongoReservationRepository(db),
+		CostCenters:        NewMongoCostCenterRepository(db),
+		RefreshTokens:      NewMongoRefreshTokenRepository(db),
+		Sessions:           NewMongoSessionRepository(db),
+		LoginAttempts:      NewMongoLoginAttemptRepository(db),
+		PasswordResets:     NewMongoPasswordResetRepository(db),
+		Transactor:         &mongoTransactor{client: db.Client()},
+	}
+}
+
+// mongoTransactor executa fn numa transação multi-documento do MongoDB. O
+// servidor precisa rodar como replica set (um nó só basta, iniciado com
+// --replSet e rs.initiate()); num mongod standalone toda transação falha e
+// check-out, remoção de entradas e login deixam de funcionar.
+type mongoTransactor struct {
+	client *mongo.Client
+}
+
+func (t *mongoTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
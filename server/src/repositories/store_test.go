@@ -0,0 +1,464 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	migration "server/src/migrations"
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// forEachStore executa o teste contra o armazenamento em memória e, quando
+// MONGODB_TEST_URL aponta para um replica set, contra um banco MongoDB
+// descartável com as migrações aplicadas, garantindo que as duas
+// implementações se comportem da mesma forma.
+func forEachStore(t *testing.T, run func(t *testing.T, store *Store)) {
+	t.Run("memory", func(t *testing.T) {
+		run(t, NewMemoryStore())
+	})
+	t.Run("mongo", func(t *testing.T) {
+		url := os.Getenv("MONGODB_TEST_URL")
+		if url == "" {
+			t.Skip("MONGODB_TEST_URL não definido")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(url))
+		if err != nil {
+			t.Fatal(err)
+		}
+		db := client.Database("test_" + primitive.NewObjectID().Hex())
+		t.Cleanup(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			db.Drop(ctx)
+			client.Disconnect(ctx)
+		})
+		if _, err := migration.Up(ctx, db); err != nil {
+			t.Fatal(err)
+		}
+
+		run(t, NewMongoStore(db))
+	})
+}
+
+// base é truncado em milissegundos, a precisão das datas no MongoDB.
+var base = time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+func at(hours int) time.Time {
+	return base.Add(time.Duration(hours) * time.Hour)
+}
+
+func newCar(t *testing.T, store *Store, plate string, active bool) *model.Car {
+	t.Helper()
+	car := &model.Car{ID: primitive.NewObjectID(), Number: plate[:1], Plate: plate, Model: "Gol", Brand: "VW", Year: 2020, IsActive: active, Capacity: 50, Consumption: 10}
+	if err := store.Cars.Create(context.Background(), car); err != nil {
+		t.Fatal(err)
+	}
+	return car
+}
+
+func newEntry(carID, userID primitive.ObjectID, startedAt time.Time) *model.CarEntry {
+	return &model.CarEntry{
+		ID:        primitive.NewObjectID(),
+		CarID:     carID,
+		UserID:    userID,
+		CheckIn:   model.CheckIn{Location: model.Location{Latitude: 1, Longitude: 1}, NextLocation: "Obra", ActualKM: 100},
+		StartedAt: startedAt,
+	}
+}
+
+func openEntry(t *testing.T, store *Store, carID, userID primitive.ObjectID, startedAt time.Time) *model.CarEntry {
+	t.Helper()
+	entry := newEntry(carID, userID, startedAt)
+	if err := store.CarEntries.Create(context.Background(), entry, true); err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+func closeEntry(t *testing.T, store *Store, entry *model.CarEntry, kmDriven float64, endedAt time.Time) {
+	t.Helper()
+	checkOut := model.CheckOut{Location: model.Location{Latitude: 1, Longitude: 1}, ActualKM: entry.CheckIn.ActualKM + kmDriven}
+	if _, err := store.CarEntries.Close(context.Background(), entry.ID, checkOut, kmDriven, endedAt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func entryIDs(entries []model.CarEntry) []primitive.ObjectID {
+	ids := []primitive.ObjectID{}
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	return ids
+}
+
+func assertIDs(t *testing.T, what string, got []primitive.ObjectID, want ...primitive.ObjectID) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: %d registros, esperado %d", what, len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s: registro %d = %s, esperado %s", what, i, got[i].Hex(), want[i].Hex())
+		}
+	}
+}
+
+func TestCarEntryOpenLocks(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store *Store) {
+		ctx := context.Background()
+		car1, car2 := primitive.NewObjectID(), primitive.NewObjectID()
+		driver1, driver2 := primitive.NewObjectID(), primitive.NewObjectID()
+
+		first := openEntry(t, store, car1, driver1, at(0))
+
+		var conflict *OpenEntryConflictError
+		err := store.CarEntries.Create(ctx, newEntry(car1, driver2, at(1)), true)
+		if !errors.As(err, &conflict) || !errors.Is(err, ErrCarHasOpenEntry) || conflict.EntryID != first.ID {
+			t.Fatalf("carro em uso: err = %v", err)
+		}
+		err = store.CarEntries.Create(ctx, newEntry(car2, driver1, at(1)), true)
+		if !errors.As(err, &conflict) || !errors.Is(err, ErrUserHasOpenEntry) || conflict.EntryID != first.ID {
+			t.Fatalf("motorista em uso: err = %v", err)
+		}
+		if err := store.CarEntries.Create(ctx, newEntry(car2, driver1, at(1)), false); err != nil {
+			t.Fatalf("sem travar o motorista, o segundo carro deveria ser liberado: %v", err)
+		}
+
+		open, err := store.CarEntries.FindOpenByCarAndUser(ctx, car1, driver1)
+		if err != nil || open.ID != first.ID {
+			t.Fatalf("entrada aberta = %v, err = %v", open, err)
+		}
+		if _, err := store.CarEntries.FindOpenByCarAndUser(ctx, car1, driver2); err != ErrNotFound {
+			t.Fatalf("outro motorista não tem entrada aberta, err = %v", err)
+		}
+
+		closeEntry(t, store, first, 50, at(2))
+		if _, err := store.CarEntries.FindOpenByCar(ctx, car1); err != ErrNotFound {
+			t.Fatalf("o carro deveria estar livre após o check-out, err = %v", err)
+		}
+		checkOut := model.CheckOut{ActualKM: 200}
+		if _, err := store.CarEntries.Close(ctx, first.ID, checkOut, 100, at(3)); err != ErrNotFound {
+			t.Fatalf("check-out repetido deveria retornar ErrNotFound, err = %v", err)
+		}
+		if err := store.CarEntries.Create(ctx, newEntry(car1, driver2, at(3)), true); err != nil {
+			t.Fatalf("o carro liberado deveria aceitar nova entrada: %v", err)
+		}
+
+		closed, err := store.CarEntries.FindByID(ctx, first.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if closed.CheckOut == nil || closed.KMDriven == nil || *closed.KMDriven != 50 || closed.EndedAt == nil || !closed.EndedAt.Equal(at(2)) {
+			t.Fatalf("entrada encerrada = %+v", closed)
+		}
+		if closed.ActiveCarID != nil || closed.ActiveUserID != nil {
+			t.Fatalf("as travas deveriam ser removidas no check-out: %+v", closed)
+		}
+	})
+}
+
+func TestCarEntryQueries(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store *Store) {
+		ctx := context.Background()
+		car1, car2 := primitive.NewObjectID(), primitive.NewObjectID()
+		driver1, driver2 := primitive.NewObjectID(), primitive.NewObjectID()
+
+		a := openEntry(t, store, car1, driver1, at(0))
+		closeEntry(t, store, a, 10, at(1))
+		b := openEntry(t, store, car2, driver2, at(2))
+		closeEntry(t, store, b, 20, at(5))
+		c := openEntry(t, store, car1, driver2, at(3))
+		closeEntry(t, store, c, 30, at(4))
+		d := openEntry(t, store, car1, driver1, at(6))
+
+		all, err := store.CarEntries.List(ctx, CarEntryFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, "List", entryIDs(all), d.ID, c.ID, b.ID, a.ID)
+
+		from, to := at(2), at(6)
+		filtered, err := store.CarEntries.List(ctx, CarEntryFilter{CarID: &car1, From: &from, To: &to})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, "List por carro e período", entryIDs(filtered), c.ID)
+
+		byUser, err := store.CarEntries.List(ctx, CarEntryFilter{UserID: &driver1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, "List por motorista", entryIDs(byUser), d.ID, a.ID)
+
+		byCar, err := store.CarEntries.ListByCar(ctx, car1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, "ListByCar", entryIDs(byCar), a.ID, c.ID, d.ID)
+
+		started, err := store.CarEntries.ListStartedBetween(ctx, at(0), at(3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, "ListStartedBetween", entryIDs(started), a.ID, b.ID)
+
+		closed, err := store.CarEntries.ListClosedBetween(ctx, at(1), at(5))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, "ListClosedBetween", entryIDs(closed), a.ID, c.ID)
+
+		count, err := store.CarEntries.Count(ctx)
+		if err != nil || count != 4 {
+			t.Fatalf("Count = %d, err = %v", count, err)
+		}
+
+		if err := store.CarEntries.Delete(ctx, a.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.CarEntries.Delete(ctx, a.ID); err != ErrNotFound {
+			t.Fatalf("remoção repetida deveria retornar ErrNotFound, err = %v", err)
+		}
+		if _, err := store.CarEntries.FindByID(ctx, a.ID); err != ErrNotFound {
+			t.Fatalf("entrada removida ainda encontrada, err = %v", err)
+		}
+	})
+}
+
+func TestCarEntryFindByIDWithUser(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store *Store) {
+		ctx := context.Background()
+		driver := &model.User{ID: primitive.NewObjectID(), Name: "Motorista", Email: "m@frota.com", Password: "hash", UserType: "USER", CNH: "12345678900", IsActive: true}
+		if err := store.Users.Create(ctx, driver); err != nil {
+			t.Fatal(err)
+		}
+
+		entry := openEntry(t, store, primitive.NewObjectID(), driver.ID, at(0))
+		found, err := store.CarEntries.FindByIDWithUser(ctx, entry.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.User == nil || found.User.ID != driver.ID || found.User.Email != driver.Email {
+			t.Fatalf("motorista da entrada = %+v", found.User)
+		}
+		if found.User.Password != "" {
+			t.Fatal("o hash da senha não deve acompanhar a entrada")
+		}
+
+		orphan := openEntry(t, store, primitive.NewObjectID(), primitive.NewObjectID(), at(1))
+		found, err = store.CarEntries.FindByIDWithUser(ctx, orphan.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.User != nil {
+			t.Fatalf("entrada de motorista removido não deveria ter usuário: %+v", found.User)
+		}
+
+		if _, err := store.CarEntries.FindByIDWithUser(ctx, primitive.NewObjectID()); err != ErrNotFound {
+			t.Fatalf("entrada inexistente, err = %v", err)
+		}
+	})
+}
+
+func TestCarStatistics(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store *Store) {
+		ctx := context.Background()
+		idle := newCar(t, store, "AAA1111", true)
+		busy := newCar(t, store, "BBB2222", true)
+		newCar(t, store, "CCC3333", false)
+		driver := primitive.NewObjectID()
+
+		fuels := []model.Fuel{
+			{ID: primitive.NewObjectID(), CarID: idle.ID, Type: model.FuelTypeRefuel, PreviousFuel: 0, NewFuel: 40, Liters: 40, CreatedAt: at(0)},
+			{ID: primitive.NewObjectID(), CarID: idle.ID, Type: model.FuelTypeCheckOut, PreviousFuel: 40, NewFuel: 35, KMDriven: 50, CreatedAt: at(2)},
+		}
+		for i := range fuels {
+			if err := store.Fuels.Create(ctx, &fuels[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		old := openEntry(t, store, idle.ID, driver, at(1))
+		closeEntry(t, store, old, 50, at(2))
+		openEntry(t, store, busy.ID, driver, at(3))
+
+		statistics, err := store.Cars.Statistics(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Slice(statistics, func(i, j int) bool { return statistics[i].Plate < statistics[j].Plate })
+		if len(statistics) != 2 {
+			t.Fatalf("apenas carros ativos deveriam aparecer: %+v", statistics)
+		}
+
+		first := statistics[0]
+		if first.ID != idle.ID || first.InUse {
+			t.Fatalf("carro parado = %+v", first)
+		}
+		if first.LastRefuelCreatedAt == nil || !first.LastRefuelCreatedAt.Equal(at(0)) {
+			t.Fatalf("último abastecimento = %v, esperado %s", first.LastRefuelCreatedAt, at(0))
+		}
+		if first.CurrentFuel == nil || *first.CurrentFuel != 35 {
+			t.Fatalf("combustível atual = %v, esperado 35", first.CurrentFuel)
+		}
+		if first.Checkout == nil || first.Checkout.ActualKM != 150 {
+			t.Fatalf("último check-out = %+v", first.Checkout)
+		}
+
+		second := statistics[1]
+		if second.ID != busy.ID || !second.InUse || second.CurrentFuel != nil || second.LastRefuelCreatedAt != nil || second.Checkout != nil {
+			t.Fatalf("carro em uso = %+v", second)
+		}
+	})
+}
+
+func TestFuelQueries(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store *Store) {
+		ctx := context.Background()
+		car, other := primitive.NewObjectID(), primitive.NewObjectID()
+
+		if _, err := store.Fuels.FindLatestByCar(ctx, car); err != ErrNotFound {
+			t.Fatalf("carro sem histórico, err = %v", err)
+		}
+
+		fuels := []model.Fuel{
+			{ID: primitive.NewObjectID(), CarID: car, Type: model.FuelTypeRefuel, NewFuel: 40, Liters: 40, CreatedAt: at(2)},
+			{ID: primitive.NewObjectID(), CarID: car, Type: model.FuelTypeCheckOut, PreviousFuel: 40, NewFuel: 30, KMDriven: 100, CreatedAt: at(3)},
+			{ID: primitive.NewObjectID(), CarID: car, Type: model.FuelTypeRefuel, NewFuel: 10, Liters: 10, CreatedAt: at(0)},
+			{ID: primitive.NewObjectID(), CarID: other, Type: model.FuelTypeRefuel, NewFuel: 20, Liters: 20, CreatedAt: at(1)},
+		}
+		for i := range fuels {
+			if err := store.Fuels.Create(ctx, &fuels[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		latest, err := store.Fuels.FindLatestByCar(ctx, car)
+		if err != nil || latest.ID != fuels[1].ID {
+			t.Fatalf("último registro = %+v, err = %v", latest, err)
+		}
+
+		history, err := store.Fuels.ListByCar(ctx, car)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := []primitive.ObjectID{}
+		for _, fuel := range history {
+			ids = append(ids, fuel.ID)
+		}
+		assertIDs(t, "ListByCar", ids, fuels[2].ID, fuels[0].ID, fuels[1].ID)
+
+		refuels, err := store.Fuels.ListRefuelsBetween(ctx, at(1), at(3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = ids[:0]
+		for _, fuel := range refuels {
+			ids = append(ids, fuel.ID)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i].Hex() < ids[j].Hex() })
+		want := []primitive.ObjectID{fuels[0].ID, fuels[3].ID}
+		sort.Slice(want, func(i, j int) bool { return want[i].Hex() < want[j].Hex() })
+		assertIDs(t, "ListRefuelsBetween", ids, want...)
+
+		if err := store.Fuels.ReplaceByCar(ctx, car, []model.Fuel{}); err != nil {
+			t.Fatal(err)
+		}
+		if history, err := store.Fuels.ListByCar(ctx, car); err != nil || len(history) != 0 {
+			t.Fatalf("histórico após substituição vazia = %+v, err = %v", history, err)
+		}
+		if history, err := store.Fuels.ListByCar(ctx, other); err != nil || len(history) != 1 {
+			t.Fatalf("histórico de outro carro = %+v, err = %v", history, err)
+		}
+	})
+}
+
+func TestRegisterFailure(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store *Store) {
+		ctx := context.Background()
+		window := 15 * time.Minute
+		register := func(now time.Time) *model.LoginThrottle {
+			t.Helper()
+			throttle, err := store.LoginAttempts.RegisterFailure(ctx, "email:a@frota.com", now, window, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return throttle
+		}
+
+		for i := 1; i <= 2; i++ {
+			if throttle := register(base); throttle.Failures != i || throttle.LockedUntil != nil {
+				t.Fatalf("falha %d: %+v", i, throttle)
+			}
+		}
+
+		locked := register(base.Add(time.Minute))
+		lockedUntil := base.Add(time.Minute + window)
+		if locked.Failures != 3 || locked.LockedUntil == nil || !locked.LockedUntil.Equal(lockedUntil) || !locked.ExpiresAt.Equal(lockedUntil) {
+			t.Fatalf("terceira falha deveria bloquear: %+v", locked)
+		}
+
+		during := register(base.Add(2 * time.Minute))
+		if during.Failures != 4 || !during.LockedUntil.Equal(lockedUntil) || !during.ExpiresAt.Equal(base.Add(2*time.Minute+window)) {
+			t.Fatalf("falha durante o bloqueio: %+v", during)
+		}
+
+		stored, err := store.LoginAttempts.FindThrottle(ctx, "email:a@frota.com")
+		if err != nil || stored.Failures != 4 || !stored.LastFailureAt.Equal(base.Add(2*time.Minute)) {
+			t.Fatalf("registro gravado = %+v, err = %v", stored, err)
+		}
+
+		reset := register(during.ExpiresAt)
+		if reset.Failures != 1 || reset.LockedUntil != nil || !reset.ExpiresAt.Equal(during.ExpiresAt.Add(window)) {
+			t.Fatalf("registro expirado deveria recomeçar: %+v", reset)
+		}
+
+		if err := store.LoginAttempts.DeleteThrottle(ctx, "email:a@frota.com"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.LoginAttempts.FindThrottle(ctx, "email:a@frota.com"); err != ErrNotFound {
+			t.Fatalf("registro removido, err = %v", err)
+		}
+	})
+}
+
+func TestTransactorRollsBack(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store *Store) {
+		ctx := context.Background()
+		car := &model.Car{ID: primitive.NewObjectID(), Number: "1", Plate: "ABC1234", Model: "Gol", Brand: "VW", Year: 2020, IsActive: true, Capacity: 50, Consumption: 10}
+		failure := errors.New("falha")
+
+		err := store.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := store.Cars.Create(ctx, car); err != nil {
+				return err
+			}
+			return failure
+		})
+		if err != failure {
+			t.Fatalf("err = %v, esperado %v", err, failure)
+		}
+		if _, err := store.Cars.FindByID(ctx, car.ID); err != ErrNotFound {
+			t.Fatalf("o carro criado na transação deveria ter sido descartado, err = %v", err)
+		}
+
+		err = store.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+			return store.Cars.Create(ctx, car)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Cars.FindByID(ctx, car.ID); err != nil {
+			t.Fatalf("o carro da transação confirmada deveria existir: %v", err)
+		}
+	})
+}
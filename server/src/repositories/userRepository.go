@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+
+	model "server/src/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type UserFilter struct {
+	Search   string
+	IsActive bool
+}
+
+type UserRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*model.User, error)
+	FindActiveByEmail(ctx context.Context, email string) (*model.User, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	List(ctx context.Context, filter UserFilter) ([]model.User, error)
+	Create(ctx context.Context, user *model.User) error
+	Update(ctx context.Context, id primitive.ObjectID, updates map[string]interface{}) (*model.User, error)
+	SetActive(ctx context.Context, id primitive.ObjectID, active bool) error
+	IncrementTokenVersion(ctx context.Context, id primitive.ObjectID) error
+	// SetPassword troca o hash da senha e define se o usuário precisa trocá-la
+	// no próximo acesso.
+	SetPassword(ctx context.Context, id primitive.ObjectID, hash string, mustChange bool) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	CountActive(ctx context.Context) (int64, error)
+}
+
+type mongoUserRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoUserRepository(db *mongo.Database) UserRepository {
+	return &mongoUserRepository{collection: db.Collection("users")}
+}
+
+func (r *mongoUserRepository) findOne(ctx context.Context, filter bson.M) (*model.User, error) {
+	var user model.User
+	err := r.collection.FindOne(ctx, filter).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *mongoUserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.User, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *mongoUserRepository) FindActiveByEmail(ctx context.Context, email string) (*model.User, error) {
+	return r.findOne(ctx, bson.M{"email": email, "isActive": true})
+}
+
+func (r *mongoUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"email": email})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *mongoUserRepository) List(ctx context.Context, filter UserFilter) ([]model.User, error) {
+	searchFilter := bson.M{}
+	if filter.Search != "" {
+		searchFilter["$or"] = []bson.M{
+			{"name": bson.M{"$regex": filter.Search, "$options": "i"}},
+			{"cnh": bson.M{"$regex": filter.Search, "$options": "i"}},
+			{"email": bson.M{"$regex": filter.Search, "$options": "i"}},
+		}
+	}
+	searchFilter["isActive"] = filter.IsActive
+
+	cursor, err := r.collection.Find(ctx, searchFilter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []model.User
+	for cursor.Next(ctx) {
+		var user model.User
+		cursor.Decode(&user)
+		users = append(users, user)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *mongoUserRepository) Create(ctx context.Context, user *model.User) error {
+	_, err := r.collection.InsertOne(ctx, user)
+	return err
+}
+
+func (r *mongoUserRepository) Update(ctx context.Context, id primitive.ObjectID, updates map[string]interface{}) (*model.User, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated model.User
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": updates}, opts).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *mongoUserRepository) SetActive(ctx context.Context, id primitive.ObjectID, active bool) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"isActive": active}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoUserRepository) IncrementTokenVersion(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"tokenVersion": 1}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoUserRepository) SetPassword(ctx context.Context, id primitive.ObjectID, hash string, mustChange bool) error {
+	update := bson.M{"$set": bson.M{"password": hash, "mustChangePassword": mustChange}}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoUserRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *mongoUserRepository) CountActive(ctx context.Context) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"isActive": true})
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	controller "server/src/controllers"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AlertRoutes(router *gin.RouterGroup, store *repository.Store) {
+	alerts := service.NewAlertService(store)
+
+	alert := router.Group("/alert")
+	{
+		alert.GET("/", controller.GetAlerts(alerts))
+		alert.PUT("/:alertId/acknowledge", controller.AcknowledgeAlert(alerts))
+		alert.PUT("/:alertId/resolve", controller.ResolveAlert(alerts))
+	}
+}
@@ -2,15 +2,23 @@ package routes
 
 import (
 	controller "server/src/controllers"
+	repository "server/src/repositories"
+	service "server/src/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthRoutes(router *gin.Engine) {
+func AuthRoutes(router *gin.Engine, store *repository.Store, notifier service.Notifier) {
+	tokens := service.NewTokenService(store)
+	guard := service.NewLoginGuardService(store)
+	passwords := service.NewPasswordService(store, notifier)
+
 	auth := router.Group("/auth")
 	{
-		auth.POST("/login", controller.LoginUser())
-		auth.POST("/logout", controller.LogoutUser())
-		auth.GET("/refresh-token", controller.RefreshToken())
+		auth.POST("/login", controller.LoginUser(store.Users, tokens, guard))
+		auth.POST("/logout", controller.LogoutUser(tokens))
+		auth.GET("/refresh-token", controller.RefreshToken(tokens))
+		auth.POST("/forgot-password", controller.ForgotPassword(passwords))
+		auth.POST("/reset-password", controller.ResetPassword(passwords))
 	}
 }
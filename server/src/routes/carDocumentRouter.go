@@ -0,0 +1,24 @@
+package routes
+
+import (
+	controller "server/src/controllers"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CarDocumentRoutes(router *gin.RouterGroup, store *repository.Store) {
+	documents := service.NewCarDocumentService(store)
+
+	document := router.Group("/document")
+	{
+		document.GET("/expiring", controller.GetExpiringCarDocuments(documents))
+		document.GET("/:documentId", controller.GetCarDocument(store.CarDocuments))
+		document.GET("/", controller.GetCarDocuments(store.CarDocuments))
+		document.POST("/create", controller.CreateCarDocument(documents))
+		document.POST("/:documentId/upload", controller.UploadCarDocumentFiles(store.CarDocuments))
+		document.PUT("/update/:documentId", controller.UpdateCarDocument(documents))
+		document.DELETE("/delete/:documentId", controller.DeleteCarDocument(store.CarDocuments))
+	}
+}
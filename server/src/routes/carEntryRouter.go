@@ -2,21 +2,36 @@ package routes
 
 import (
 	controller "server/src/controllers"
+	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CarEntryRoutes(router *gin.RouterGroup) {
+func CarEntryRoutes(router *gin.RouterGroup, store *repository.Store) {
+	alerts := service.NewAlertService(store)
+	checklists := service.NewChecklistService(store)
+	damages := service.NewDamageService(store)
+	availability := service.NewAvailabilityService(store)
+	odometer := service.NewOdometerService(store)
+	licenses := service.NewDriverLicenseService(store)
+	reservations := service.NewReservationService(store)
+	costCenters := service.NewCostCenterService(store)
+
 	car := router.Group("/car-entry")
 	{
-		car.POST("/start", controller.StartCarEntry())
-		car.PUT("/end", controller.EndCarEntry())
-		car.POST("/fuel", controller.FuelEntry())
-		car.GET("/:entryId", controller.GetCarEntry())
-		car.GET("/", controller.GetCarEntrys())
-		car.DELETE("/delete/:entryId", controller.DeleteCarEntry())
+		car.POST("/start", controller.StartCarEntry(store.CarEntries, availability, licenses, reservations, odometer, checklists, damages, costCenters))
+		car.PUT("/end", controller.EndCarEntry(store.CarEntries, store.Cars, store.Fuels, store.Transactor, alerts, checklists, damages, reservations))
+		car.POST("/fuel", controller.FuelEntry(store.Fuels, store.Cars, store.Transactor, alerts))
+		car.POST("/fuel/:fuelId/receipt", controller.UploadFuelReceipt(store.Fuels))
+		car.GET("/:entryId", controller.GetCarEntry(store.CarEntries))
+		car.GET("/", controller.GetCarEntrys(store.CarEntries))
+		car.DELETE("/delete/:entryId", controller.DeleteCarEntry(store.CarEntries, store.Cars, store.Fuels, store.Transactor, alerts))
 
-		car.POST("/:entryId/checkin/upload", controller.UploadCheckInImages())
-		car.POST("/:entryId/checkout/upload", controller.UploadCheckOutImages())
+		car.POST("/:entryId/checkin/upload", controller.UploadCheckInImages(store.CarEntries))
+		car.POST("/:entryId/checkout/upload", controller.UploadCheckOutImages(store.CarEntries))
+		car.POST("/:entryId/checkin/checklist/:itemKey/upload", controller.UploadChecklistPhoto(store.CarEntries, damages, model.ChecklistStageCheckIn))
+		car.POST("/:entryId/checkout/checklist/:itemKey/upload", controller.UploadChecklistPhoto(store.CarEntries, damages, model.ChecklistStageCheckOut))
 	}
 }
@@ -2,19 +2,27 @@ package routes
 
 import (
 	controller "server/src/controllers"
+	repository "server/src/repositories"
+	service "server/src/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CarRoutes(router *gin.RouterGroup) {
+func CarRoutes(router *gin.RouterGroup, store *repository.Store) {
+	availability := service.NewAvailabilityService(store)
+	odometer := service.NewOdometerService(store)
+
 	car := router.Group("/car")
 	{
-		car.GET("/:carId", controller.GetCar())
-		car.GET("/", controller.GetCars())
-		car.POST("/create", controller.CreateCar())
-		car.DELETE("/delete/:carId", controller.DeleteCar())
-		car.PUT("/update/:carId", controller.UpdateCar())
-		car.PUT("/disable/:carId", controller.DisableCar())
-		car.PUT("/enable/:carId", controller.EnableCar())
+		car.GET("/availability", controller.GetFleetAvailability(availability))
+		car.GET("/:carId/availability", controller.GetCarAvailability(availability))
+		car.GET("/:carId/odometer", controller.GetOdometerTimeline(odometer))
+		car.GET("/:carId", controller.GetCar(store.Cars))
+		car.GET("/", controller.GetCars(store.Cars))
+		car.POST("/create", controller.CreateCar(store.Cars))
+		car.DELETE("/delete/:carId", controller.DeleteCar(store.Cars))
+		car.PUT("/update/:carId", controller.UpdateCar(store.Cars))
+		car.PUT("/disable/:carId", controller.DisableCar(store.Cars))
+		car.PUT("/enable/:carId", controller.EnableCar(store.Cars))
 	}
 }
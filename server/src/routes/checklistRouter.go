@@ -0,0 +1,25 @@
+package routes
+
+import (
+	controller "server/src/controllers"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ChecklistRoutes(router *gin.RouterGroup, store *repository.Store) {
+	checklists := service.NewChecklistService(store)
+
+	checklist := router.Group("/checklist")
+	{
+		checklist.GET("/:templateId", controller.GetChecklistTemplate(store.ChecklistTemplates))
+		checklist.GET("/:templateId/versions", controller.GetChecklistTemplateVersions(store.ChecklistTemplates))
+		checklist.GET("/:templateId/versions/:version", controller.GetChecklistTemplateVersion(store.ChecklistTemplates))
+		checklist.GET("/", controller.GetChecklistTemplates(store.ChecklistTemplates, store.Cars, checklists))
+		checklist.POST("/create", controller.CreateChecklistTemplate(checklists))
+		checklist.PUT("/update/:templateId", controller.UpdateChecklistTemplate(checklists))
+		checklist.PUT("/disable/:templateId", controller.DisableChecklistTemplate(store.ChecklistTemplates))
+		checklist.PUT("/enable/:templateId", controller.EnableChecklistTemplate(store.ChecklistTemplates))
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	controller "server/src/controllers"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CostCenterRoutes(router *gin.RouterGroup, store *repository.Store) {
+	costCenters := service.NewCostCenterService(store)
+
+	group := router.Group("/cost-center")
+	{
+		group.GET("/:costCenterId", controller.GetCostCenter(store.CostCenters))
+		group.GET("/", controller.GetCostCenters(store.CostCenters))
+		group.POST("/create", controller.CreateCostCenter(costCenters))
+		group.PUT("/update/:costCenterId", controller.UpdateCostCenter(costCenters))
+		group.PUT("/disable/:costCenterId", controller.DisableCostCenter(store.CostCenters))
+		group.PUT("/enable/:costCenterId", controller.EnableCostCenter(store.CostCenters))
+	}
+}
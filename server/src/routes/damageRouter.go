@@ -0,0 +1,24 @@
+package routes
+
+import (
+	controller "server/src/controllers"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func DamageRoutes(router *gin.RouterGroup, store *repository.Store) {
+	damages := service.NewDamageService(store)
+
+	damage := router.Group("/damage")
+	{
+		damage.GET("/:damageId", controller.GetDamage(store.Damages))
+		damage.GET("/", controller.GetDamages(store.Damages))
+		damage.GET("/compare/:entryId", controller.CompareEntryDamages(damages))
+		damage.POST("/create", controller.CreateDamage(damages, store.CarEntries, store.Cars))
+		damage.POST("/:damageId/upload", controller.UploadDamagePhotos(store.Damages))
+		damage.PUT("/:damageId/status", controller.UpdateDamageStatus(damages))
+		damage.PUT("/:damageId/assign", controller.AssignDamage(damages))
+	}
+}
@@ -2,13 +2,29 @@ package routes
 
 import (
 	controller "server/src/controllers"
+	repository "server/src/repositories"
+	service "server/src/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-func FormsRoutes(router *gin.RouterGroup) {
+func FormsRoutes(router *gin.RouterGroup, store *repository.Store) {
+	ledger := service.NewFuelLedgerService(store)
+	consumption := service.NewConsumptionService(store)
+	costs := service.NewCostReportService(store)
+	alerts := service.NewAlertService(store)
+	checklists := service.NewChecklistService(store)
+	costCenters := service.NewCostCenterService(store)
+
 	car := router.Group("/forms")
 	{
-		car.GET("/statistics", controller.GetStatistics())
+		car.GET("/statistics", controller.GetStatistics(store.Users, store.Cars, store.CarEntries))
+		car.GET("/fuel-ledger/:carId", controller.CheckFuelLedger(ledger))
+		car.POST("/fuel-ledger/:carId/rebuild", controller.RebuildFuelLedger(ledger, alerts))
+		car.GET("/consumption", controller.GetConsumptionReports(consumption))
+		car.GET("/consumption/:carId", controller.GetConsumptionReport(consumption))
+		car.GET("/costs", controller.GetCostReport(costs))
+		car.GET("/checklist-failures", controller.GetChecklistFailures(checklists))
+		car.GET("/cost-centers", controller.GetCostCenterUsage(costCenters))
 	}
 }
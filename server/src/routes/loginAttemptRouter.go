@@ -0,0 +1,19 @@
+package routes
+
+import (
+	controller "server/src/controllers"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func LoginAttemptRoutes(router *gin.RouterGroup, store *repository.Store) {
+	guard := service.NewLoginGuardService(store)
+
+	group := router.Group("/login-attempt")
+	{
+		group.GET("/", controller.GetLoginAttempts(store.LoginAttempts))
+		group.PUT("/unlock-ip/:ip", controller.UnlockLoginIP(guard))
+	}
+}
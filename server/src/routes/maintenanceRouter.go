@@ -0,0 +1,31 @@
+package routes
+
+import (
+	controller "server/src/controllers"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func MaintenanceRoutes(router *gin.RouterGroup, store *repository.Store) {
+	maintenance := service.NewMaintenanceService(store)
+
+	group := router.Group("/maintenance")
+	{
+		group.GET("/schedule", controller.GetMaintenanceSchedule(maintenance))
+
+		group.GET("/plan/:planId", controller.GetMaintenancePlan(store.Maintenance))
+		group.GET("/plan/", controller.GetMaintenancePlans(store.Maintenance))
+		group.POST("/plan/create", controller.CreateMaintenancePlan(maintenance))
+		group.PUT("/plan/update/:planId", controller.UpdateMaintenancePlan(maintenance))
+		group.PUT("/plan/disable/:planId", controller.DisableMaintenancePlan(store.Maintenance))
+		group.PUT("/plan/enable/:planId", controller.EnableMaintenancePlan(store.Maintenance))
+
+		group.GET("/record/:recordId", controller.GetMaintenanceRecord(store.Maintenance))
+		group.GET("/record/", controller.GetMaintenanceRecords(store.Maintenance))
+		group.POST("/record/create", controller.CreateMaintenanceRecord(maintenance))
+		group.PUT("/record/:recordId/complete", controller.CompleteMaintenanceRecord(maintenance))
+		group.POST("/record/:recordId/upload", controller.UploadMaintenanceInvoices(store.Maintenance))
+	}
+}
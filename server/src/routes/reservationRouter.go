@@ -0,0 +1,24 @@
+package routes
+
+import (
+	controller "server/src/controllers"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ReservationRoutes(router *gin.RouterGroup, store *repository.Store) {
+	reservations := service.NewReservationService(store)
+
+	group := router.Group("/reservation")
+	{
+		group.GET("/availability", controller.GetCarSlots(reservations))
+		group.GET("/:reservationId", controller.GetReservation(store.Reservations))
+		group.GET("/", controller.GetReservations(reservations))
+		group.POST("/create", controller.CreateReservation(reservations))
+		group.PUT("/:reservationId/approve", controller.ApproveReservation(reservations))
+		group.PUT("/:reservationId/reject", controller.RejectReservation(reservations))
+		group.PUT("/:reservationId/cancel", controller.CancelReservation(reservations, store.Reservations))
+	}
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	middleware "server/src/middlewares"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes monta as rotas públicas de autenticação e, atrás do
+// middleware de autenticação, as demais rotas da API.
+func RegisterRoutes(router *gin.Engine, store *repository.Store, notifier service.Notifier) {
+	AuthRoutes(router, store, notifier)
+
+	authProtected := router.Group("/")
+	authProtected.Use(middleware.Authenticate(service.NewAccessService(store)))
+
+	authProtected.Static("/uploads", "./uploads")
+
+	UserRoutes(authProtected, store, notifier)
+	CarRoutes(authProtected, store)
+	CarEntryRoutes(authProtected, store)
+	FormsRoutes(authProtected, store)
+	AlertRoutes(authProtected, store)
+	ChecklistRoutes(authProtected, store)
+	DamageRoutes(authProtected, store)
+	MaintenanceRoutes(authProtected, store)
+	CarDocumentRoutes(authProtected, store)
+	ReservationRoutes(authProtected, store)
+	CostCenterRoutes(authProtected, store)
+	SessionRoutes(authProtected, store)
+	LoginAttemptRoutes(authProtected, store)
+}
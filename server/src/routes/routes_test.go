@@ -0,0 +1,260 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// api executa requisições contra as rotas da aplicação montadas sobre o
+// armazenamento em memória, sem abrir um servidor.
+type api struct {
+	t      *testing.T
+	router *gin.Engine
+	store  *repository.Store
+}
+
+func newAPI(t *testing.T) *api {
+	t.Helper()
+	t.Setenv("SECRET_KEY", "segredo-de-teste")
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	store := repository.NewMemoryStore()
+	RegisterRoutes(router, store, &service.StubNotifier{})
+	return &api{t: t, router: router, store: store}
+}
+
+func (a *api) do(method, path, token string, body interface{}, out interface{}) int {
+	a.t.Helper()
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			a.t.Fatal(err)
+		}
+	}
+	request := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	request.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		request.Header.Set("Authorization", token)
+	}
+
+	recorder := httptest.NewRecorder()
+	a.router.ServeHTTP(recorder, request)
+	if out != nil {
+		if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+			a.t.Fatalf("%s %s: resposta inválida %q: %v", method, path, recorder.Body, err)
+		}
+	}
+	return recorder.Code
+}
+
+func (a *api) expect(status int, method, path, token string, body interface{}, out interface{}) {
+	a.t.Helper()
+	var raw json.RawMessage
+	if code := a.do(method, path, token, body, &raw); code != status {
+		a.t.Fatalf("%s %s = %d, esperado %d: %s", method, path, code, status, raw)
+	}
+	if out != nil {
+		if err := json.Unmarshal(raw, out); err != nil {
+			a.t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+}
+
+func (a *api) seedAdmin(email, password string) *model.User {
+	a.t.Helper()
+	hash, err := helper.HashPassword(password)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	admin := &model.User{
+		ID:       primitive.NewObjectID(),
+		Name:     "Administrador",
+		Email:    email,
+		Password: hash,
+		UserType: "ADMIN",
+		CNH:      "12345678900",
+		IsActive: true,
+	}
+	if err := a.store.Users.Create(context.Background(), admin); err != nil {
+		a.t.Fatal(err)
+	}
+	return admin
+}
+
+func (a *api) login(email, password string) string {
+	a.t.Helper()
+	var response struct {
+		AccessToken string `json:"accessToken"`
+	}
+	a.expect(http.StatusOK, http.MethodPost, "/auth/login", "", gin.H{"email": email, "password": password}, &response)
+	return response.AccessToken
+}
+
+func (a *api) createCar(token, number, plate string) string {
+	a.t.Helper()
+	var car struct {
+		ID string `json:"id"`
+	}
+	a.expect(http.StatusCreated, http.MethodPost, "/car/create", token, gin.H{
+		"number": number, "plate": plate, "model": "Gol", "brand": "VW", "year": 2020,
+		"isActive": true, "capacity": 50, "consumption": 10,
+	}, &car)
+	return car.ID
+}
+
+func checkInBody(carID, userID string, km float64) gin.H {
+	return gin.H{"carID": carID, "userID": userID, "checkIn": gin.H{
+		"location": gin.H{"latitude": 1, "longitude": 1}, "nextLocation": "Obra", "carState": "ok", "actualKM": km,
+	}}
+}
+
+func checkOutBody(carID, userID string, km float64) gin.H {
+	return gin.H{"carID": carID, "userID": userID, "checkOut": gin.H{
+		"location": gin.H{"latitude": 1, "longitude": 1}, "carState": "ok", "actualKM": km,
+	}}
+}
+
+func TestCarEntryLifecycle(t *testing.T) {
+	a := newAPI(t)
+	admin := a.seedAdmin("admin@frota.com", "senha-admin")
+	token := a.login("admin@frota.com", "senha-admin")
+	carID := a.createCar(token, "1", "ABC1234")
+	userID := admin.ID.Hex()
+
+	var entry struct {
+		ID string `json:"id"`
+	}
+	a.expect(http.StatusCreated, http.MethodPost, "/car-entry/start", token, checkInBody(carID, userID, 1000), &entry)
+	if entry.ID == "" {
+		t.Fatal("check-in sem ID da entrada")
+	}
+	a.expect(http.StatusConflict, http.MethodPost, "/car-entry/start", token, checkInBody(carID, userID, 1000), nil)
+
+	refuel := gin.H{"carId": carID, "liters": 30, "pricePerLiter": 5, "fuelKind": "gasolina", "station": "Posto", "odometer": 1000, "fullTank": true}
+	a.expect(http.StatusCreated, http.MethodPost, "/car-entry/fuel", token, refuel, nil)
+	refuel["liters"] = 25
+	a.expect(http.StatusBadRequest, http.MethodPost, "/car-entry/fuel", token, refuel, nil)
+
+	a.expect(http.StatusOK, http.MethodPut, "/car-entry/end", token, checkOutBody(carID, userID, 1100), nil)
+	a.expect(http.StatusNotFound, http.MethodPut, "/car-entry/end", token, checkOutBody(carID, userID, 1200), nil)
+
+	var detail struct {
+		KMDriven *float64 `json:"kmDriven"`
+		User     *struct {
+			Email    string `json:"email"`
+			Password string `json:"password"`
+		} `json:"user"`
+	}
+	a.expect(http.StatusOK, http.MethodGet, "/car-entry/"+entry.ID, token, nil, &detail)
+	if detail.KMDriven == nil || *detail.KMDriven != 100 {
+		t.Fatalf("kmDriven = %v, esperado 100", detail.KMDriven)
+	}
+	if detail.User == nil || detail.User.Email != "admin@frota.com" || detail.User.Password != "" {
+		t.Fatalf("motorista da entrada = %+v", detail.User)
+	}
+
+	var statistics struct {
+		CarCount      int64 `json:"carCount"`
+		CarEntryCount int64 `json:"carEntryCount"`
+		Cars          []struct {
+			ID          string   `json:"id"`
+			CurrentFuel *float64 `json:"currentFuel"`
+			InUse       bool     `json:"inUse"`
+			Checkout    *struct {
+				ActualKM float64 `json:"actualKM"`
+			} `json:"checkout"`
+		} `json:"cars"`
+	}
+	a.expect(http.StatusOK, http.MethodGet, "/forms/statistics", token, nil, &statistics)
+	if statistics.CarCount != 1 || statistics.CarEntryCount != 1 || len(statistics.Cars) != 1 {
+		t.Fatalf("estatísticas = %+v", statistics)
+	}
+	car := statistics.Cars[0]
+	if car.ID != carID || car.InUse || car.CurrentFuel == nil || *car.CurrentFuel != 20 || car.Checkout == nil || car.Checkout.ActualKM != 1100 {
+		t.Fatalf("estatísticas do carro = %+v", car)
+	}
+
+	var ledger struct {
+		Consistent          bool    `json:"consistent"`
+		ExpectedCurrentFuel float64 `json:"expectedCurrentFuel"`
+	}
+	a.expect(http.StatusOK, http.MethodGet, "/forms/fuel-ledger/"+carID, token, nil, &ledger)
+	if !ledger.Consistent || ledger.ExpectedCurrentFuel != 20 {
+		t.Fatalf("histórico de combustível = %+v", ledger)
+	}
+
+	a.expect(http.StatusOK, http.MethodDelete, "/car-entry/delete/"+entry.ID, token, nil, nil)
+	a.expect(http.StatusNotFound, http.MethodGet, "/car-entry/"+entry.ID, token, nil, nil)
+	a.expect(http.StatusOK, http.MethodGet, "/forms/fuel-ledger/"+carID, token, nil, &ledger)
+	if !ledger.Consistent || ledger.ExpectedCurrentFuel != 30 {
+		t.Fatalf("histórico após remover a entrada = %+v", ledger)
+	}
+}
+
+func TestNewDriverMustChangePassword(t *testing.T) {
+	a := newAPI(t)
+	a.seedAdmin("admin@frota.com", "senha-admin")
+	token := a.login("admin@frota.com", "senha-admin")
+
+	a.expect(http.StatusCreated, http.MethodPost, "/user/create", token, gin.H{
+		"name": "Motorista", "email": "motorista@frota.com", "password": "provisoria",
+		"userType": "USER", "cnh": "29141777683",
+	}, nil)
+
+	driver := a.login("motorista@frota.com", "provisoria")
+	a.expect(http.StatusForbidden, http.MethodGet, "/car/", driver, nil, nil)
+
+	var current map[string]interface{}
+	a.expect(http.StatusOK, http.MethodGet, "/user/current", driver, nil, &current)
+	if current["mustChangePassword"] != true {
+		t.Fatalf("usuário atual = %v", current)
+	}
+	if password, _ := current["password"].(string); password != "" {
+		t.Fatal("a rota do usuário atual não deve expor o hash da senha")
+	}
+
+	a.expect(http.StatusBadRequest, http.MethodPost, "/user/change-password", driver,
+		gin.H{"currentPassword": "errada", "newPassword": "definitiva"}, nil)
+	a.expect(http.StatusOK, http.MethodPost, "/user/change-password", driver,
+		gin.H{"currentPassword": "provisoria", "newPassword": "definitiva"}, nil)
+	a.expect(http.StatusOK, http.MethodGet, "/car/", driver, nil, nil)
+}
+
+func TestRoutesRequireAuthentication(t *testing.T) {
+	a := newAPI(t)
+	for _, path := range []string{"/car/", "/car-entry/", "/user/current", "/forms/statistics"} {
+		if code := a.do(http.MethodGet, path, "", nil, nil); code != http.StatusUnauthorized {
+			t.Errorf("GET %s sem token = %d, esperado %d", path, code, http.StatusUnauthorized)
+		}
+	}
+
+	a.seedAdmin("admin@frota.com", "senha-admin")
+	if code := a.do(http.MethodPost, "/auth/login", "", gin.H{"email": "admin@frota.com", "password": "errada"}, nil); code != http.StatusUnauthorized {
+		t.Fatalf("login com senha errada = %d, esperado %d", code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	controller "server/src/controllers"
+	repository "server/src/repositories"
+	service "server/src/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SessionRoutes(router *gin.RouterGroup, store *repository.Store) {
+	sessions := service.NewSessionService(store)
+
+	group := router.Group("/session")
+	{
+		group.GET("/", controller.GetSessions(sessions))
+		group.GET("/user/:userId", controller.GetSessions(sessions))
+		group.PUT("/revoke/:sessionId", controller.RevokeSession(sessions, store.Sessions))
+	}
+}
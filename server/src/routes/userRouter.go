@@ -4,18 +4,28 @@ import (
 	"github.com/gin-gonic/gin"
 
 	controller "server/src/controllers"
+	repository "server/src/repositories"
+	service "server/src/services"
 )
 
-func UserRoutes(router *gin.RouterGroup) {
+func UserRoutes(router *gin.RouterGroup, store *repository.Store, notifier service.Notifier) {
+	licenses := service.NewDriverLicenseService(store)
+	tokens := service.NewTokenService(store)
+	guard := service.NewLoginGuardService(store)
+	passwords := service.NewPasswordService(store, notifier)
+
 	user := router.Group("/user")
 	{
-		user.GET("/:userId", controller.GetUser())
-		user.GET("/", controller.GetUsers())
-		user.GET("/current", controller.GetCurrentUser())
-		user.POST("/create", controller.CreateUser())
-		user.DELETE("/delete/:userId", controller.DeleteUser())
-		user.PUT("/update/:userId", controller.UpdateUser())
-		user.PUT("/disable/:userId", controller.DisableUser())
-		user.PUT("/enable/:userId", controller.EnableUser())
+		user.GET("/:userId", controller.GetUser(store.Users))
+		user.GET("/", controller.GetUsers(store.Users))
+		user.GET("/current", controller.GetCurrentUser(store.Users))
+		user.GET("/cnh/expiring", controller.GetExpiringCNHs(licenses))
+		user.POST("/create", controller.CreateUser(store.Users))
+		user.DELETE("/delete/:userId", controller.DeleteUser(store.Users, tokens))
+		user.PUT("/update/:userId", controller.UpdateUser(store.Users, tokens))
+		user.PUT("/disable/:userId", controller.DisableUser(store.Users, tokens))
+		user.PUT("/enable/:userId", controller.EnableUser(store.Users))
+		user.PUT("/unlock/:userId", controller.UnlockUser(store.Users, guard))
+		user.POST("/change-password", controller.ChangePassword(passwords))
 	}
 }
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultAccessCacheSeconds é por quanto tempo o estado do usuário e da
+// sessão fica em cache entre requisições autenticadas.
+const defaultAccessCacheSeconds = 30
+
+var (
+	ErrUserDisabled  = errors.New("usuário desativado")
+	ErrTokenOutdated = errors.New("token desatualizado")
+
+	// ErrPasswordChangeRequired indica que o usuário precisa trocar a senha
+	// provisória antes de usar o sistema.
+	ErrPasswordChangeRequired = errors.New("troca de senha obrigatória")
+)
+
+type cachedUser struct {
+	isActive           bool
+	mustChangePassword bool
+	tokenVersion       int
+	expiresAt          time.Time
+}
+
+type cachedSession struct {
+	userID    primitive.ObjectID
+	expiresAt time.Time
+}
+
+// accessCache é compartilhado por todas as instâncias de AccessService para
+// que as alterações feitas neste processo o invalidem na hora; outras
+// instâncias do servidor enxergam a mudança ao fim do TTL.
+var accessCache = struct {
+	sync.Mutex
+	users    map[primitive.ObjectID]cachedUser
+	sessions map[primitive.ObjectID]cachedSession
+}{
+	users:    make(map[primitive.ObjectID]cachedUser),
+	sessions: make(map[primitive.ObjectID]cachedSession),
+}
+
+// AccessCacheTTL lê ACCESS_CACHE_SECONDS, usando o padrão quando a variável
+// está ausente ou inválida. Zero desativa o cache.
+func AccessCacheTTL() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv("ACCESS_CACHE_SECONDS"))
+	if err != nil || seconds < 0 {
+		seconds = defaultAccessCacheSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// InvalidateUserAccess descarta o estado em cache do usuário e das suas
+// sessões. Deve ser chamado sempre que o status, o perfil ou as sessões do
+// usuário mudarem.
+func InvalidateUserAccess(userID primitive.ObjectID) {
+	accessCache.Lock()
+	defer accessCache.Unlock()
+
+	delete(accessCache.users, userID)
+	for id, session := range accessCache.sessions {
+		if session.userID == userID {
+			delete(accessCache.sessions, id)
+		}
+	}
+}
+
+func invalidateSessionAccess(sessionID primitive.ObjectID) {
+	accessCache.Lock()
+	defer accessCache.Unlock()
+
+	delete(accessCache.sessions, sessionID)
+}
+
+// AccessService confere, a cada requisição autenticada, se o usuário do
+// token continua ativo, se o token não foi invalidado por mudança de perfil
+// e se a sessão não foi encerrada.
+type AccessService struct {
+	users    repository.UserRepository
+	sessions *SessionService
+}
+
+func NewAccessService(store *repository.Store) *AccessService {
+	return &AccessService{users: store.Users, sessions: NewSessionService(store)}
+}
+
+func (s *AccessService) user(ctx context.Context, userID primitive.ObjectID, now time.Time) (cachedUser, error) {
+	accessCache.Lock()
+	cached, ok := accessCache.users[userID]
+	accessCache.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached, nil
+	}
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err == repository.ErrNotFound {
+		return cachedUser{}, ErrUserDisabled
+	}
+	if err != nil {
+		return cachedUser{}, err
+	}
+
+	cached = cachedUser{
+		isActive:           user.IsActive,
+		mustChangePassword: user.MustChangePassword,
+		tokenVersion:       user.TokenVersion,
+		expiresAt:          now.Add(AccessCacheTTL()),
+	}
+	accessCache.Lock()
+	accessCache.users[userID] = cached
+	accessCache.Unlock()
+	return cached, nil
+}
+
+func (s *AccessService) session(ctx context.Context, userID, sessionID primitive.ObjectID, ip string, now time.Time) error {
+	accessCache.Lock()
+	cached, ok := accessCache.sessions[sessionID]
+	accessCache.Unlock()
+	if ok && cached.userID == userID && now.Before(cached.expiresAt) {
+		return nil
+	}
+
+	if err := s.sessions.Validate(ctx, sessionID, ip); err != nil {
+		return err
+	}
+
+	accessCache.Lock()
+	accessCache.sessions[sessionID] = cachedSession{userID: userID, expiresAt: now.Add(AccessCacheTTL())}
+	accessCache.Unlock()
+	return nil
+}
+
+// Authorize retorna ErrUserDisabled, ErrTokenOutdated ou ErrSessionRevoked
+// quando o token não deve mais ser aceito, e ErrPasswordChangeRequired
+// quando o token é válido mas o usuário ainda precisa trocar a senha.
+// sessionID é nulo para tokens emitidos antes das sessões.
+func (s *AccessService) Authorize(ctx context.Context, userID primitive.ObjectID, tokenVersion int, sessionID *primitive.ObjectID, ip string) error {
+	now := time.Now()
+	user, err := s.user(ctx, userID, now)
+	if err != nil {
+		return err
+	}
+	if !user.isActive {
+		return ErrUserDisabled
+	}
+	if tokenVersion != user.tokenVersion {
+		return ErrTokenOutdated
+	}
+
+	if sessionID != nil {
+		if err := s.session(ctx, userID, *sessionID, ip, now); err != nil {
+			return err
+		}
+	}
+	if user.mustChangePassword {
+		return ErrPasswordChangeRequired
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultLowFuelThreshold é o percentual da capacidade usado quando o carro
+// não define Car.LowFuelThreshold nem LOW_FUEL_THRESHOLD_PERCENT está configurada.
+const defaultLowFuelThreshold = 15.0
+
+var ErrInvalidAlertTransition = errors.New("o alerta não pode passar para este status")
+
+type AlertService struct {
+	cars   repository.CarRepository
+	fuels  repository.FuelRepository
+	alerts repository.AlertRepository
+}
+
+func NewAlertService(store *repository.Store) *AlertService {
+	return &AlertService{cars: store.Cars, fuels: store.Fuels, alerts: store.Alerts}
+}
+
+func lowFuelThreshold(car *model.Car) float64 {
+	if car.LowFuelThreshold > 0 {
+		return car.LowFuelThreshold
+	}
+	threshold, err := strconv.ParseFloat(os.Getenv("LOW_FUEL_THRESHOLD_PERCENT"), 64)
+	if err != nil || threshold <= 0 {
+		return defaultLowFuelThreshold
+	}
+	return threshold
+}
+
+// Evaluate aplica as regras de nível de combustível ao saldo atual do carro:
+// abre alertas de nível negativo ou abaixo do limite e resolve
+// automaticamente os que deixaram de valer.
+func (s *AlertService) Evaluate(ctx context.Context, carID primitive.ObjectID) error {
+	car, err := s.cars.FindByID(ctx, carID)
+	if err != nil {
+		return err
+	}
+
+	level := 0.0
+	var fuelID *primitive.ObjectID
+	if lastFuel, err := s.fuels.FindLatestByCar(ctx, carID); err == nil {
+		level = lastFuel.NewFuel
+		fuelID = &lastFuel.ID
+	} else if err != repository.ErrNotFound {
+		return err
+	}
+
+	threshold := lowFuelThreshold(car)
+	limit := float64(car.Capacity) * threshold / 100
+
+	negative := level < 0
+	if err := s.apply(ctx, negative, model.Alert{
+		CarID:     car.ID,
+		Type:      model.AlertTypeNegativeFuel,
+		Message:   fmt.Sprintf("Nível de combustível calculado negativo (%.1f L) para o carro %s", level, car.Plate),
+		FuelLevel: level,
+		Capacity:  car.Capacity,
+		FuelID:    fuelID,
+	}); err != nil {
+		return err
+	}
+
+	return s.apply(ctx, !negative && level < limit, model.Alert{
+		CarID:     car.ID,
+		Type:      model.AlertTypeLowFuel,
+		Message:   fmt.Sprintf("Combustível baixo no carro %s: %.1f L de %d L (limite de %.0f%%)", car.Plate, level, car.Capacity, threshold),
+		FuelLevel: level,
+		Capacity:  car.Capacity,
+		Threshold: threshold,
+		FuelID:    fuelID,
+	})
+}
+
+// apply abre o alerta quando a condição vale e não há outro ativo do mesmo
+// tipo, ou resolve o ativo quando a condição deixou de valer.
+func (s *AlertService) apply(ctx context.Context, raised bool, alert model.Alert) error {
+	active, err := s.alerts.FindActive(ctx, alert.CarID, alert.Type)
+	if err != nil && err != repository.ErrNotFound {
+		return err
+	}
+
+	if !raised {
+		if active == nil {
+			return nil
+		}
+		_, err := s.alerts.UpdateStatus(ctx, active.ID,
+			[]string{model.AlertStatusOpen, model.AlertStatusAcknowledged},
+			model.AlertStatusResolved, nil, time.Now())
+		if err == repository.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if active != nil {
+		return nil
+	}
+	return s.create(ctx, &alert)
+}
+
+func (s *AlertService) create(ctx context.Context, alert *model.Alert) error {
+	alert.ID = primitive.NewObjectID()
+	alert.Status = model.AlertStatusOpen
+	alert.CreatedAt = time.Now()
+
+	err := s.alerts.Create(ctx, alert)
+	if err == repository.ErrAlertAlreadyActive {
+		return nil
+	}
+	return err
+}
+
+// RaiseOverCapacity registra a tentativa de abastecer além da capacidade do
+// tanque. Tentativas repetidas enquanto o alerta estiver ativo não geram novos.
+func (s *AlertService) RaiseOverCapacity(ctx context.Context, car *model.Car, level, liters float64) error {
+	return s.create(ctx, &model.Alert{
+		CarID:     car.ID,
+		Type:      model.AlertTypeOverCapacity,
+		Message:   fmt.Sprintf("Tentativa de abastecer %.1f L no carro %s com %.1f L de %d L", liters, car.Plate, level, car.Capacity),
+		FuelLevel: level,
+		Capacity:  car.Capacity,
+		Liters:    liters,
+	})
+}
+
+func (s *AlertService) List(ctx context.Context, filter repository.AlertFilter) ([]model.Alert, error) {
+	return s.alerts.List(ctx, filter)
+}
+
+func (s *AlertService) Acknowledge(ctx context.Context, id, userID primitive.ObjectID) (*model.Alert, error) {
+	return s.transition(ctx, id, []string{model.AlertStatusOpen}, model.AlertStatusAcknowledged, userID)
+}
+
+func (s *AlertService) Resolve(ctx context.Context, id, userID primitive.ObjectID) (*model.Alert, error) {
+	return s.transition(ctx, id, []string{model.AlertStatusOpen, model.AlertStatusAcknowledged}, model.AlertStatusResolved, userID)
+}
+
+func (s *AlertService) transition(ctx context.Context, id primitive.ObjectID, from []string, status string, userID primitive.ObjectID) (*model.Alert, error) {
+	if _, err := s.alerts.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	alert, err := s.alerts.UpdateStatus(ctx, id, from, status, &userID, time.Now())
+	if err == repository.ErrNotFound {
+		return nil, ErrInvalidAlertTransition
+	}
+	return alert, err
+}
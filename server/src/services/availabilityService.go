@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AvailabilityService struct {
+	cars        repository.CarRepository
+	carEntries  repository.CarEntryRepository
+	damages     repository.DamageRepository
+	maintenance *MaintenanceService
+	documents   *CarDocumentService
+}
+
+func NewAvailabilityService(store *repository.Store) *AvailabilityService {
+	return &AvailabilityService{
+		cars:        store.Cars,
+		carEntries:  store.CarEntries,
+		damages:     store.Damages,
+		maintenance: NewMaintenanceService(store),
+		documents:   NewCarDocumentService(store),
+	}
+}
+
+// availabilityInputs reúne as pendências de todos os carros consultados, para
+// que a listagem da frota não faça uma consulta por carro.
+type availabilityInputs struct {
+	damages     map[primitive.ObjectID][]model.Damage
+	overdue     map[primitive.ObjectID][]model.MaintenanceDue
+	maintenance map[primitive.ObjectID][]model.MaintenanceRecord
+	licensing   map[primitive.ObjectID]model.CarDocument
+}
+
+func (s *AvailabilityService) load(ctx context.Context, carID *primitive.ObjectID) (*availabilityInputs, error) {
+	damages, err := s.damages.List(ctx, repository.DamageFilter{CarID: carID, Status: activeDamageStatuses})
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.maintenance.Schedule(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	inProgress, err := s.maintenance.InProgress(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	licensing, err := s.documents.ExpiredLicensing(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := &availabilityInputs{
+		damages:     make(map[primitive.ObjectID][]model.Damage),
+		overdue:     make(map[primitive.ObjectID][]model.MaintenanceDue),
+		maintenance: make(map[primitive.ObjectID][]model.MaintenanceRecord),
+		licensing:   licensing,
+	}
+	for _, damage := range damages {
+		inputs.damages[damage.CarID] = append(inputs.damages[damage.CarID], damage)
+	}
+	for _, due := range schedule {
+		if due.Status == model.MaintenanceDueOverdue {
+			inputs.overdue[due.CarID] = append(inputs.overdue[due.CarID], due)
+		}
+	}
+	for _, record := range inProgress {
+		inputs.maintenance[record.CarID] = append(inputs.maintenance[record.CarID], record)
+	}
+	return inputs, nil
+}
+
+// evaluateAvailability aplica as regras ao carro. Avarias críticas
+// (severidade alta) abertas, manutenções preventivas vencidas e licenciamento
+// vencido bloqueiam o carro; avarias críticas em reparo e manutenções em
+// andamento colocam-no em manutenção. Um carro com entrada aberta aparece como em uso, mas os motivos
+// continuam listados.
+func evaluateAvailability(car model.Car, inUse bool, inputs *availabilityInputs) *model.CarAvailability {
+	availability := &model.CarAvailability{
+		CarID:   car.ID,
+		Plate:   car.Plate,
+		Status:  model.CarAvailable,
+		Reasons: []model.BlockReason{},
+	}
+
+	inMaintenance := false
+	for _, damage := range inputs.damages[car.ID] {
+		if damage.Severity != model.DamageSeverityHigh {
+			continue
+		}
+		damageID := damage.ID
+		if damage.Status == model.DamageStatusInRepair {
+			inMaintenance = true
+			availability.Reasons = append(availability.Reasons, model.BlockReason{
+				Kind:    model.BlockReasonDamage,
+				Message: fmt.Sprintf("avaria crítica em reparo: %s", damage.Location),
+				RefID:   &damageID,
+			})
+			continue
+		}
+		availability.Reasons = append(availability.Reasons, model.BlockReason{
+			Kind:    model.BlockReasonDamage,
+			Message: fmt.Sprintf("avaria crítica aberta: %s", damage.Location),
+			RefID:   &damageID,
+		})
+	}
+
+	for _, record := range inputs.maintenance[car.ID] {
+		inMaintenance = true
+		recordID := record.ID
+		availability.Reasons = append(availability.Reasons, model.BlockReason{
+			Kind:    model.BlockReasonMaintenance,
+			Message: fmt.Sprintf("em manutenção: %s", record.Description),
+			RefID:   &recordID,
+		})
+	}
+	for _, due := range inputs.overdue[car.ID] {
+		planID := due.PlanID
+		availability.Reasons = append(availability.Reasons, model.BlockReason{
+			Kind:    model.BlockReasonMaintenance,
+			Message: fmt.Sprintf("manutenção preventiva vencida: %s", due.PlanName),
+			RefID:   &planID,
+		})
+	}
+	if document, ok := inputs.licensing[car.ID]; ok {
+		documentID := document.ID
+		availability.Reasons = append(availability.Reasons, model.BlockReason{
+			Kind:    model.BlockReasonDocument,
+			Message: fmt.Sprintf("licenciamento vencido em %s", document.ExpiresAt.Format("02/01/2006")),
+			RefID:   &documentID,
+		})
+	}
+
+	switch {
+	case inUse:
+		availability.Status = model.CarInUse
+	case inMaintenance:
+		availability.Status = model.CarInMaintenance
+	case len(availability.Reasons) > 0:
+		availability.Status = model.CarBlocked
+	}
+	return availability
+}
+
+// Blocked indica se o carro não pode receber check-in, independentemente de
+// estar em uso.
+func Blocked(availability *model.CarAvailability) bool {
+	return len(availability.Reasons) > 0
+}
+
+func (s *AvailabilityService) Check(ctx context.Context, carID primitive.ObjectID) (*model.CarAvailability, error) {
+	car, err := s.cars.FindByID(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+
+	inUse := false
+	if _, err := s.carEntries.FindOpenByCar(ctx, carID); err == nil {
+		inUse = true
+	} else if err != repository.ErrNotFound {
+		return nil, err
+	}
+
+	inputs, err := s.load(ctx, &carID)
+	if err != nil {
+		return nil, err
+	}
+	return evaluateAvailability(*car, inUse, inputs), nil
+}
+
+// CheckFleet calcula a disponibilidade de todos os carros ativos.
+func (s *AvailabilityService) CheckFleet(ctx context.Context) ([]model.CarAvailability, error) {
+	statistics, err := s.cars.Statistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := s.load(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []model.CarAvailability{}
+	for _, car := range statistics {
+		result = append(result, *evaluateAvailability(car.Car, car.InUse, inputs))
+	}
+	return result, nil
+}
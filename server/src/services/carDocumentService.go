@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultDocumentExpiryDays é o prazo padrão, em dias, da listagem de
+// documentos a vencer.
+const defaultDocumentExpiryDays = 30
+
+var ErrDocumentCarNotFound = errors.New("carro não encontrado")
+
+// DocumentExpiryDays lê DOCUMENT_EXPIRY_WARNING_DAYS, usando o padrão quando a
+// variável está ausente ou inválida.
+func DocumentExpiryDays() int {
+	days, err := strconv.Atoi(os.Getenv("DOCUMENT_EXPIRY_WARNING_DAYS"))
+	if err != nil || days <= 0 {
+		return defaultDocumentExpiryDays
+	}
+	return days
+}
+
+type CarDocumentService struct {
+	cars      repository.CarRepository
+	documents repository.CarDocumentRepository
+}
+
+func NewCarDocumentService(store *repository.Store) *CarDocumentService {
+	return &CarDocumentService{cars: store.Cars, documents: store.CarDocuments}
+}
+
+func (s *CarDocumentService) Create(ctx context.Context, document *model.CarDocument) error {
+	if _, err := s.cars.FindByID(ctx, document.CarID); err == repository.ErrNotFound {
+		return ErrDocumentCarNotFound
+	} else if err != nil {
+		return err
+	}
+
+	document.ID = primitive.NewObjectID()
+	document.Files = nil
+	document.CreatedAt = time.Now()
+	document.UpdatedAt = document.CreatedAt
+	return s.documents.Create(ctx, document)
+}
+
+// Update troca tipo, número, datas e observações; o carro, os arquivos e a
+// autoria do documento são mantidos.
+func (s *CarDocumentService) Update(ctx context.Context, id primitive.ObjectID, document *model.CarDocument) error {
+	current, err := s.documents.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	document.ID = current.ID
+	document.CarID = current.CarID
+	document.Files = current.Files
+	document.CreatedBy = current.CreatedBy
+	document.CreatedAt = current.CreatedAt
+	document.UpdatedAt = time.Now()
+	return s.documents.Replace(ctx, document)
+}
+
+type carDocumentKey struct {
+	carID   primitive.ObjectID
+	docType string
+}
+
+// currentDocuments mantém, para cada carro e tipo, apenas o documento de
+// vencimento mais distante. documents deve vir ordenado como em List.
+func currentDocuments(documents []model.CarDocument) []model.CarDocument {
+	seen := make(map[carDocumentKey]bool)
+	var current []model.CarDocument
+	for _, document := range documents {
+		key := carDocumentKey{carID: document.CarID, docType: document.Type}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		current = append(current, document)
+	}
+	return current
+}
+
+func daysUntil(t, now time.Time) int {
+	return int(math.Floor(t.Sub(now).Hours() / 24))
+}
+
+// Expiring lista os documentos vigentes dos carros ativos que vencem nos
+// próximos days dias, incluindo os já vencidos, do mais urgente ao menos.
+func (s *CarDocumentService) Expiring(ctx context.Context, days int, carID *primitive.ObjectID) ([]model.CarDocumentExpiry, error) {
+	cars, err := s.cars.List(ctx, repository.CarFilter{IsActive: true})
+	if err != nil {
+		return nil, err
+	}
+	plates := make(map[primitive.ObjectID]string)
+	for _, car := range cars {
+		plates[car.ID] = car.Plate
+	}
+
+	documents, err := s.documents.List(ctx, repository.CarDocumentFilter{CarID: carID})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	limit := now.AddDate(0, 0, days)
+	result := []model.CarDocumentExpiry{}
+	for _, document := range currentDocuments(documents) {
+		plate, active := plates[document.CarID]
+		if !active || document.ExpiresAt.After(limit) {
+			continue
+		}
+		result = append(result, model.CarDocumentExpiry{
+			Document: document,
+			Plate:    plate,
+			DaysLeft: daysUntil(document.ExpiresAt, now),
+			Expired:  !now.Before(document.ExpiresAt),
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Document.ExpiresAt.Before(result[j].Document.ExpiresAt)
+	})
+	return result, nil
+}
+
+// ExpiredLicensing devolve, por carro, o licenciamento vigente já vencido.
+// Carros sem licenciamento cadastrado não aparecem.
+func (s *CarDocumentService) ExpiredLicensing(ctx context.Context, carID *primitive.ObjectID) (map[primitive.ObjectID]model.CarDocument, error) {
+	documents, err := s.documents.List(ctx, repository.CarDocumentFilter{CarID: carID, Type: model.CarDocumentLicensing})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expired := make(map[primitive.ObjectID]model.CarDocument)
+	for _, document := range currentDocuments(documents) {
+		if !now.Before(document.ExpiresAt) {
+			expired[document.CarID] = document
+		}
+	}
+	return expired, nil
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ChecklistError indica um checklist ou modelo de checklist inválido; a
+// mensagem pode ser devolvida diretamente ao cliente.
+type ChecklistError struct {
+	Message string
+}
+
+func (e *ChecklistError) Error() string {
+	return e.Message
+}
+
+func checklistErrorf(format string, args ...interface{}) error {
+	return &ChecklistError{Message: fmt.Sprintf(format, args...)}
+}
+
+// checklistKeyPattern restringe as chaves dos itens, que também são usadas
+// como nome de pasta das fotos.
+var checklistKeyPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+type ChecklistService struct {
+	templates  repository.ChecklistTemplateRepository
+	cars       repository.CarRepository
+	carEntries repository.CarEntryRepository
+	transactor repository.Transactor
+}
+
+func NewChecklistService(store *repository.Store) *ChecklistService {
+	return &ChecklistService{
+		templates:  store.ChecklistTemplates,
+		cars:       store.Cars,
+		carEntries: store.CarEntries,
+		transactor: store.Transactor,
+	}
+}
+
+// validateTemplate confere regras que as tags de validação não cobrem:
+// formato e unicidade das chaves, etapas sem repetição e carros existentes.
+// As categorias são normalizadas para minúsculas.
+func (s *ChecklistService) validateTemplate(ctx context.Context, template *model.ChecklistTemplate) error {
+	stages := make(map[string]bool)
+	for _, stage := range template.Stages {
+		if stages[stage] {
+			return checklistErrorf("etapa %q repetida", stage)
+		}
+		stages[stage] = true
+	}
+
+	categories := make([]string, 0, len(template.Categories))
+	for _, category := range template.Categories {
+		category = normalizeCategory(category)
+		if containsString(categories, category) {
+			return checklistErrorf("categoria %q repetida", category)
+		}
+		categories = append(categories, category)
+	}
+	template.Categories = categories
+
+	cars := make(map[primitive.ObjectID]bool)
+	for _, carID := range template.CarIDs {
+		if cars[carID] {
+			return checklistErrorf("carro %s repetido", carID.Hex())
+		}
+		cars[carID] = true
+		if _, err := s.cars.FindByID(ctx, carID); err == repository.ErrNotFound {
+			return checklistErrorf("carro %s não encontrado", carID.Hex())
+		} else if err != nil {
+			return err
+		}
+	}
+
+	keys := make(map[string]bool)
+	for _, item := range template.Items {
+		if !checklistKeyPattern.MatchString(item.Key) {
+			return checklistErrorf("chave de item %q inválida; use letras minúsculas, números, _ ou -", item.Key)
+		}
+		if keys[item.Key] {
+			return checklistErrorf("chave de item %q repetida", item.Key)
+		}
+		keys[item.Key] = true
+	}
+	return nil
+}
+
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+func templateVersion(template *model.ChecklistTemplate) *model.ChecklistTemplateVersion {
+	return &model.ChecklistTemplateVersion{
+		ID:         primitive.NewObjectID(),
+		TemplateID: template.ID,
+		Version:    template.Version,
+		Name:       template.Name,
+		Stages:     template.Stages,
+		Categories: template.Categories,
+		CarIDs:     template.CarIDs,
+		Items:      template.Items,
+		CreatedAt:  template.UpdatedAt,
+	}
+}
+
+// CreateTemplate grava o modelo como versão 1.
+func (s *ChecklistService) CreateTemplate(ctx context.Context, template *model.ChecklistTemplate) error {
+	if err := s.validateTemplate(ctx, template); err != nil {
+		return err
+	}
+
+	template.ID = primitive.NewObjectID()
+	template.Version = 1
+	template.IsActive = true
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = template.CreatedAt
+
+	return s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.templates.Create(ctx, template); err != nil {
+			return err
+		}
+		return s.templates.CreateVersion(ctx, templateVersion(template))
+	})
+}
+
+// UpdateTemplate publica uma nova versão do modelo. As versões anteriores
+// continuam disponíveis para as entradas que as usaram.
+func (s *ChecklistService) UpdateTemplate(ctx context.Context, id primitive.ObjectID, template *model.ChecklistTemplate) error {
+	if err := s.validateTemplate(ctx, template); err != nil {
+		return err
+	}
+
+	return s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.templates.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		template.ID = current.ID
+		template.Version = current.Version + 1
+		template.IsActive = current.IsActive
+		template.CreatedAt = current.CreatedAt
+		template.UpdatedAt = time.Now()
+
+		if err := s.templates.CreateVersion(ctx, templateVersion(template)); err != nil {
+			return err
+		}
+		return s.templates.Replace(ctx, template)
+	})
+}
+
+// templateScope indica quão específica é a atribuição do modelo ao carro:
+// 2 para o próprio carro, 1 para a categoria, 0 para toda a frota e -1 se o
+// modelo não se aplica.
+func templateScope(template model.ChecklistTemplate, car *model.Car) int {
+	for _, carID := range template.CarIDs {
+		if carID == car.ID {
+			return 2
+		}
+	}
+	category := normalizeCategory(car.Category)
+	if category != "" && containsString(template.Categories, category) {
+		return 1
+	}
+	if len(template.CarIDs) == 0 && len(template.Categories) == 0 {
+		return 0
+	}
+	return -1
+}
+
+// Applicable lista os modelos ativos da etapa que valem para o carro. Só a
+// atribuição mais específica conta: modelos do próprio carro substituem os
+// da categoria, que substituem os gerais.
+func (s *ChecklistService) Applicable(ctx context.Context, stage string, car *model.Car) ([]model.ChecklistTemplate, error) {
+	active, err := s.templates.List(ctx, repository.ChecklistTemplateFilter{IsActive: true, Stage: stage})
+	if err != nil {
+		return nil, err
+	}
+
+	best := -1
+	result := []model.ChecklistTemplate{}
+	for _, template := range active {
+		scope := templateScope(template, car)
+		if scope < best {
+			continue
+		}
+		if scope > best {
+			best = scope
+			result = result[:0]
+		}
+		result = append(result, template)
+	}
+	if best < 0 {
+		return []model.ChecklistTemplate{}, nil
+	}
+	return result, nil
+}
+
+// Prepare valida o checklist enviado para a etapa contra o modelo indicado e
+// o normaliza na ordem do modelo, copiando nome, versão e rótulos. Quando há
+// modelo ativo para a etapa e o carro, o checklist é obrigatório.
+func (s *ChecklistService) Prepare(ctx context.Context, stage string, carID primitive.ObjectID, checklist *model.Checklist) error {
+	car, err := s.cars.FindByID(ctx, carID)
+	if err == repository.ErrNotFound {
+		return checklistErrorf("carro não encontrado")
+	}
+	if err != nil {
+		return err
+	}
+	applicable, err := s.Applicable(ctx, stage, car)
+	if err != nil {
+		return err
+	}
+
+	if checklist == nil {
+		if len(applicable) > 0 {
+			return checklistErrorf("checklist obrigatório para esta etapa")
+		}
+		return nil
+	}
+
+	var template *model.ChecklistTemplate
+	for i := range applicable {
+		if applicable[i].ID == checklist.TemplateID {
+			template = &applicable[i]
+		}
+	}
+	if template == nil {
+		return checklistErrorf("modelo de checklist não se aplica a este carro nesta etapa")
+	}
+	if checklist.TemplateVersion != 0 && checklist.TemplateVersion != template.Version {
+		return checklistErrorf("modelo de checklist %q foi atualizado; recarregue o formulário", template.Name)
+	}
+
+	answers := make(map[string]model.ChecklistItem, len(checklist.Items))
+	for _, item := range checklist.Items {
+		if _, ok := answers[item.Key]; ok {
+			return checklistErrorf("item %q respondido mais de uma vez", item.Key)
+		}
+		answers[item.Key] = item
+	}
+
+	items := make([]model.ChecklistItem, 0, len(template.Items))
+	for _, templateItem := range template.Items {
+		answer, ok := answers[templateItem.Key]
+		if !ok {
+			return checklistErrorf("item %q não preenchido", templateItem.Label)
+		}
+		delete(answers, templateItem.Key)
+
+		switch answer.Result {
+		case model.ChecklistResultPass, model.ChecklistResultFail, model.ChecklistResultNA:
+		default:
+			return checklistErrorf("resultado inválido para o item %q", templateItem.Label)
+		}
+		if answer.Result == model.ChecklistResultNA && templateItem.Required {
+			return checklistErrorf("item %q é obrigatório e não aceita NA", templateItem.Label)
+		}
+		if answer.Result == model.ChecklistResultFail && answer.Notes == "" {
+			return checklistErrorf("descreva o problema do item %q", templateItem.Label)
+		}
+
+		items = append(items, model.ChecklistItem{
+			Key:      templateItem.Key,
+			Label:    templateItem.Label,
+			Category: templateItem.Category,
+			Result:   answer.Result,
+			Notes:    answer.Notes,
+		})
+	}
+	for key := range answers {
+		return checklistErrorf("item %q não existe no modelo %q", key, template.Name)
+	}
+
+	checklist.TemplateName = template.Name
+	checklist.TemplateVersion = template.Version
+	checklist.Items = items
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// FailureSummary conta, por item, as reprovações nos check-ins e check-outs
+// feitos em [from, to) e em quantos carros distintos elas ocorreram.
+func (s *ChecklistService) FailureSummary(ctx context.Context, from, to time.Time) ([]model.ChecklistFailureSummary, error) {
+	started, err := s.carEntries.ListStartedBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	closed, err := s.carEntries.ListClosedBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[string]*model.ChecklistFailureSummary)
+	cars := make(map[string]map[string]bool)
+	count := func(entry model.CarEntry, checklist *model.Checklist) {
+		if checklist == nil {
+			return
+		}
+		for _, item := range checklist.Items {
+			if item.Result != model.ChecklistResultFail {
+				continue
+			}
+			if summaries[item.Key] == nil {
+				summaries[item.Key] = &model.ChecklistFailureSummary{Key: item.Key}
+				cars[item.Key] = make(map[string]bool)
+			}
+			summaries[item.Key].Label = item.Label
+			summaries[item.Key].Failures++
+			cars[item.Key][entry.CarID.Hex()] = true
+		}
+	}
+
+	for _, entry := range started {
+		count(entry, entry.CheckIn.Checklist)
+	}
+	for _, entry := range closed {
+		count(entry, entry.CheckOut.Checklist)
+	}
+
+	result := []model.ChecklistFailureSummary{}
+	for key, summary := range summaries {
+		summary.Cars = len(cars[key])
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Failures != result[j].Failures {
+			return result[i].Failures > result[j].Failures
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result, nil
+}
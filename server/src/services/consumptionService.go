@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"math"
+	"os"
+	"strconv"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultConsumptionDeviation é o desvio percentual padrão entre o consumo
+// medido e Car.Consumption a partir do qual o carro é sinalizado.
+const defaultConsumptionDeviation = 15.0
+
+type ConsumptionService struct {
+	cars  repository.CarRepository
+	fuels repository.FuelRepository
+}
+
+func NewConsumptionService(store *repository.Store) *ConsumptionService {
+	return &ConsumptionService{cars: store.Cars, fuels: store.Fuels}
+}
+
+// ConsumptionDeviationThreshold lê CONSUMPTION_DEVIATION_PERCENT, usando o
+// padrão quando a variável está ausente ou inválida.
+func ConsumptionDeviationThreshold() float64 {
+	threshold, err := strconv.ParseFloat(os.Getenv("CONSUMPTION_DEVIATION_PERCENT"), 64)
+	if err != nil || threshold <= 0 {
+		return defaultConsumptionDeviation
+	}
+	return threshold
+}
+
+func (s *ConsumptionService) Report(ctx context.Context, carID primitive.ObjectID, threshold float64) (*model.ConsumptionReport, error) {
+	car, err := s.cars.FindByID(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	return s.report(ctx, car, threshold)
+}
+
+// ReportActive retorna o relatório de todos os carros ativos.
+func (s *ConsumptionService) ReportActive(ctx context.Context, threshold float64) ([]model.ConsumptionReport, error) {
+	cars, err := s.cars.List(ctx, repository.CarFilter{IsActive: true})
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []model.ConsumptionReport{}
+	for i := range cars {
+		report, err := s.report(ctx, &cars[i], threshold)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, *report)
+	}
+	return reports, nil
+}
+
+func (s *ConsumptionService) report(ctx context.Context, car *model.Car, threshold float64) (*model.ConsumptionReport, error) {
+	fuels, err := s.fuels.ListByCar(ctx, car.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.ConsumptionReport{
+		CarID:            car.ID,
+		Plate:            car.Plate,
+		RatedConsumption: car.Consumption,
+		ThresholdPercent: threshold,
+		Samples:          measureConsumption(fuels, car.Consumption, threshold),
+	}
+
+	var km, liters float64
+	for _, sample := range report.Samples {
+		km += sample.KM
+		liters += sample.Liters
+	}
+	if liters > 0 {
+		measured := km / liters
+		deviation := consumptionDeviation(measured, car.Consumption)
+		report.MeasuredKMPerLiter = &measured
+		report.Deviation = &deviation
+		report.Flagged = math.Abs(deviation) > threshold
+	}
+	return report, nil
+}
+
+// measureConsumption calcula o km/l entre abastecimentos de tanque cheio
+// consecutivos: a distância é a diferença de hodômetro e o volume é a soma
+// dos litros abastecidos depois do primeiro até o segundo, inclusive.
+// Abastecimentos sem hodômetro interrompem a medição até o próximo tanque cheio.
+func measureConsumption(fuels []model.Fuel, rated, threshold float64) []model.ConsumptionSample {
+	samples := []model.ConsumptionSample{}
+
+	var start *model.Fuel
+	liters := 0.0
+	for i := range fuels {
+		fuel := &fuels[i]
+		if fuel.Type != model.FuelTypeRefuel {
+			continue
+		}
+		if fuel.Odometer <= 0 || fuel.Liters <= 0 {
+			start = nil
+			liters = 0
+			continue
+		}
+
+		liters += fuel.Liters
+		if !fuel.FullTank {
+			continue
+		}
+
+		if start != nil && fuel.Odometer > start.Odometer {
+			km := fuel.Odometer - start.Odometer
+			measured := km / liters
+			deviation := consumptionDeviation(measured, rated)
+			samples = append(samples, model.ConsumptionSample{
+				StartFuelID:   start.ID,
+				EndFuelID:     fuel.ID,
+				StartedAt:     start.CreatedAt,
+				EndedAt:       fuel.CreatedAt,
+				StartOdometer: start.Odometer,
+				EndOdometer:   fuel.Odometer,
+				KM:            km,
+				Liters:        liters,
+				KMPerLiter:    measured,
+				Deviation:     deviation,
+				Flagged:       math.Abs(deviation) > threshold,
+			})
+		}
+
+		start = fuel
+		liters = 0
+	}
+	return samples
+}
+
+// consumptionDeviation retorna o desvio percentual do consumo medido em
+// relação ao informado no cadastro do carro.
+func consumptionDeviation(measured, rated float64) float64 {
+	if rated <= 0 {
+		return 0
+	}
+	return (measured - rated) / rated * 100
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func consumptionRefuel(carID primitive.ObjectID, odometer, liters float64, fullTank bool, hours int) model.Fuel {
+	return model.Fuel{
+		ID:        primitive.NewObjectID(),
+		CarID:     carID,
+		Type:      model.FuelTypeRefuel,
+		Liters:    liters,
+		Odometer:  odometer,
+		FullTank:  fullTank,
+		CreatedAt: ledgerStart.Add(time.Duration(hours) * time.Hour),
+	}
+}
+
+func TestMeasureConsumption(t *testing.T) {
+	carID := primitive.NewObjectID()
+	full := func(odometer, liters float64, hours int) model.Fuel {
+		return consumptionRefuel(carID, odometer, liters, true, hours)
+	}
+	partial := func(odometer, liters float64, hours int) model.Fuel {
+		return consumptionRefuel(carID, odometer, liters, false, hours)
+	}
+	checkOut := model.Fuel{ID: primitive.NewObjectID(), CarID: carID, Type: model.FuelTypeCheckOut, KMDriven: 80}
+
+	tests := []struct {
+		name    string
+		fuels   []model.Fuel
+		want    []float64
+		flagged []bool
+	}{
+		{
+			name:  "sem tanque cheio anterior",
+			fuels: []model.Fuel{partial(1000, 20, 0), full(1200, 20, 1)},
+			want:  []float64{},
+		},
+		{
+			name:    "entre dois tanques cheios",
+			fuels:   []model.Fuel{full(1000, 40, 0), full(1400, 40, 1)},
+			want:    []float64{10},
+			flagged: []bool{false},
+		},
+		{
+			name:    "soma os parciais até o próximo tanque cheio",
+			fuels:   []model.Fuel{full(1000, 40, 0), partial(1200, 10, 1), full(1400, 30, 2)},
+			want:    []float64{10},
+			flagged: []bool{false},
+		},
+		{
+			name:    "ignora check-outs",
+			fuels:   []model.Fuel{full(1000, 40, 0), checkOut, full(1400, 40, 1)},
+			want:    []float64{10},
+			flagged: []bool{false},
+		},
+		{
+			name:    "várias amostras",
+			fuels:   []model.Fuel{full(1000, 40, 0), full(1400, 40, 1), full(1600, 40, 2)},
+			want:    []float64{10, 5},
+			flagged: []bool{false, true},
+		},
+		{
+			name:    "abastecimento sem hodômetro interrompe a medição",
+			fuels:   []model.Fuel{full(1000, 40, 0), partial(0, 10, 1), full(1400, 30, 2), full(1800, 40, 3)},
+			want:    []float64{10},
+			flagged: []bool{false},
+		},
+		{
+			name:  "hodômetro que não avança",
+			fuels: []model.Fuel{full(1000, 40, 0), full(1000, 10, 1)},
+			want:  []float64{},
+		},
+		{
+			name:    "consumo acima do cadastrado",
+			fuels:   []model.Fuel{full(1000, 40, 0), full(1500, 40, 1)},
+			want:    []float64{12.5},
+			flagged: []bool{true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			samples := measureConsumption(tt.fuels, 10, 15)
+			if len(samples) != len(tt.want) {
+				t.Fatalf("%d amostras, esperado %d: %+v", len(samples), len(tt.want), samples)
+			}
+			for i, sample := range samples {
+				if math.Abs(sample.KMPerLiter-tt.want[i]) > 1e-9 {
+					t.Errorf("amostra %d: %.2f km/l, esperado %.2f", i, sample.KMPerLiter, tt.want[i])
+				}
+				if math.Abs(sample.KM/sample.Liters-sample.KMPerLiter) > 1e-9 {
+					t.Errorf("amostra %d: km/l não confere com km e litros: %+v", i, sample)
+				}
+				if sample.Flagged != tt.flagged[i] {
+					t.Errorf("amostra %d: sinalizada = %v, esperado %v", i, sample.Flagged, tt.flagged[i])
+				}
+			}
+		})
+	}
+}
+
+func TestConsumptionDeviation(t *testing.T) {
+	tests := []struct {
+		measured, rated, want float64
+	}{
+		{10, 10, 0},
+		{8.5, 10, -15},
+		{12, 10, 20},
+		{10, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := consumptionDeviation(tt.measured, tt.rated); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("consumptionDeviation(%.1f, %.1f) = %.2f, esperado %.2f", tt.measured, tt.rated, got, tt.want)
+		}
+	}
+}
+
+func TestConsumptionReportAveragesSamples(t *testing.T) {
+	store := repository.NewMemoryStore()
+	ctx := context.Background()
+	car := ledgerCar()
+	if err := store.Cars.Create(ctx, car); err != nil {
+		t.Fatal(err)
+	}
+	for _, fuel := range []model.Fuel{
+		consumptionRefuel(car.ID, 1000, 40, true, 0),
+		consumptionRefuel(car.ID, 1300, 30, true, 1),
+		consumptionRefuel(car.ID, 1500, 30, true, 2),
+	} {
+		if err := store.Fuels.Create(ctx, &fuel); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := NewConsumptionService(store).Report(ctx, car.ID, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Samples) != 2 || report.MeasuredKMPerLiter == nil {
+		t.Fatalf("relatório = %+v", report)
+	}
+	if math.Abs(*report.MeasuredKMPerLiter-500.0/60) > 1e-9 {
+		t.Fatalf("consumo medido = %.4f, esperado %.4f", *report.MeasuredKMPerLiter, 500.0/60)
+	}
+	if !report.Flagged {
+		t.Fatalf("desvio de %.1f%% deveria sinalizar o carro", *report.Deviation)
+	}
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CostCenterError indica um centro de custo ou dados de viagem inválidos; a
+// mensagem pode ser devolvida diretamente ao cliente.
+type CostCenterError struct {
+	Message string
+}
+
+func (e *CostCenterError) Error() string {
+	return e.Message
+}
+
+func costCenterErrorf(format string, args ...interface{}) error {
+	return &CostCenterError{Message: fmt.Sprintf(format, args...)}
+}
+
+// NormalizeCostCenterCode padroniza o código como é gravado no catálogo e
+// nas entradas.
+func NormalizeCostCenterCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+type CostCenterService struct {
+	costCenters repository.CostCenterRepository
+	carEntries  repository.CarEntryRepository
+}
+
+func NewCostCenterService(store *repository.Store) *CostCenterService {
+	return &CostCenterService{costCenters: store.CostCenters, carEntries: store.CarEntries}
+}
+
+func (s *CostCenterService) Create(ctx context.Context, costCenter *model.CostCenter) error {
+	costCenter.Code = NormalizeCostCenterCode(costCenter.Code)
+	costCenter.Name = strings.TrimSpace(costCenter.Name)
+	if costCenter.Code == "" || costCenter.Name == "" {
+		return costCenterErrorf("informe o código e o nome do centro de custo")
+	}
+
+	costCenter.ID = primitive.NewObjectID()
+	costCenter.IsActive = true
+	costCenter.CreatedAt = time.Now()
+	costCenter.UpdatedAt = costCenter.CreatedAt
+	return s.costCenters.Create(ctx, costCenter)
+}
+
+// Update altera nome e descrição; o código é mantido para não desvincular as
+// entradas já registradas.
+func (s *CostCenterService) Update(ctx context.Context, id primitive.ObjectID, costCenter *model.CostCenter) error {
+	current, err := s.costCenters.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if code := NormalizeCostCenterCode(costCenter.Code); code != "" && code != current.Code {
+		return costCenterErrorf("o código do centro de custo não pode ser alterado")
+	}
+	costCenter.Name = strings.TrimSpace(costCenter.Name)
+	if costCenter.Name == "" {
+		return costCenterErrorf("informe o nome do centro de custo")
+	}
+
+	costCenter.ID = current.ID
+	costCenter.Code = current.Code
+	costCenter.IsActive = current.IsActive
+	costCenter.CreatedAt = current.CreatedAt
+	costCenter.UpdatedAt = time.Now()
+	return s.costCenters.Replace(ctx, costCenter)
+}
+
+// PrepareTrip normaliza os dados da viagem informados no check-in e confere o
+// centro de custo no catálogo. Com REQUIRE_COST_CENTER=true o centro de custo
+// passa a ser obrigatório. Clientes antigos continuam lendo NextLocation, que
+// é preenchido a partir do destino quando vier vazio.
+func (s *CostCenterService) PrepareTrip(ctx context.Context, checkIn *model.CheckIn) error {
+	checkIn.Purpose = strings.TrimSpace(checkIn.Purpose)
+	checkIn.NextLocation = strings.TrimSpace(checkIn.NextLocation)
+
+	passengers := checkIn.Passengers[:0]
+	for _, passenger := range checkIn.Passengers {
+		if passenger = strings.TrimSpace(passenger); passenger != "" {
+			passengers = append(passengers, passenger)
+		}
+	}
+	checkIn.Passengers = passengers
+
+	if destination := checkIn.Destination; destination != nil {
+		destination.Name = strings.TrimSpace(destination.Name)
+		destination.City = strings.TrimSpace(destination.City)
+		if checkIn.NextLocation == "" {
+			checkIn.NextLocation = destination.Name
+			if destination.City != "" {
+				checkIn.NextLocation += " - " + destination.City
+			}
+		}
+	}
+
+	checkIn.CostCenter = NormalizeCostCenterCode(checkIn.CostCenter)
+	if checkIn.CostCenter == "" {
+		if os.Getenv("REQUIRE_COST_CENTER") == "true" {
+			return costCenterErrorf("informe o centro de custo da viagem")
+		}
+		return nil
+	}
+
+	costCenter, err := s.costCenters.FindByCode(ctx, checkIn.CostCenter)
+	if err == repository.ErrNotFound {
+		return costCenterErrorf("centro de custo %q não cadastrado", checkIn.CostCenter)
+	}
+	if err != nil {
+		return err
+	}
+	if !costCenter.IsActive {
+		return costCenterErrorf("centro de custo %q desativado", checkIn.CostCenter)
+	}
+	return nil
+}
+
+// Usage soma a quilometragem das viagens concluídas em [from, to) por centro
+// de custo, da maior para a menor.
+func (s *CostCenterService) Usage(ctx context.Context, from, to time.Time) ([]model.CostCenterUsage, error) {
+	entries, err := s.carEntries.ListClosedBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]*model.CostCenterUsage)
+	for _, entry := range entries {
+		code := entry.CheckIn.CostCenter
+		item, ok := usage[code]
+		if !ok {
+			item = &model.CostCenterUsage{Code: code, Name: "Sem centro de custo"}
+			if code != "" {
+				item.Name = code
+				if costCenter, err := s.costCenters.FindByCode(ctx, code); err == nil {
+					item.Name = costCenter.Name
+				} else if err != repository.ErrNotFound {
+					return nil, err
+				}
+			}
+			usage[code] = item
+		}
+
+		item.Trips++
+		item.Passengers += len(entry.CheckIn.Passengers)
+		if entry.KMDriven != nil {
+			item.KMDriven += *entry.KMDriven
+		}
+	}
+
+	result := []model.CostCenterUsage{}
+	for _, item := range usage {
+		result = append(result, *item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].KMDriven != result[j].KMDriven {
+			return result[i].KMDriven > result[j].KMDriven
+		}
+		return result[i].Code < result[j].Code
+	})
+	return result, nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CostReportService struct {
+	cars       repository.CarRepository
+	users      repository.UserRepository
+	carEntries repository.CarEntryRepository
+	fuels      repository.FuelRepository
+}
+
+func NewCostReportService(store *repository.Store) *CostReportService {
+	return &CostReportService{
+		cars:       store.Cars,
+		users:      store.Users,
+		carEntries: store.CarEntries,
+		fuels:      store.Fuels,
+	}
+}
+
+// Report soma o custo dos abastecimentos registrados em [from, to) e os
+// quilômetros das entradas encerradas no mesmo intervalo, agrupados por
+// carro, motorista ou período.
+func (s *CostReportService) Report(ctx context.Context, from, to time.Time, groupBy, interval string) (*model.CostReport, error) {
+	fuels, err := s.fuels.ListRefuelsBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.carEntries.ListClosedBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.CostReport{
+		From:    from,
+		To:      to,
+		GroupBy: groupBy,
+		Rows:    []model.CostReportRow{},
+		Total:   model.CostReportRow{Key: "total", Label: "Total"},
+	}
+	if groupBy == model.CostGroupByPeriod {
+		report.Interval = interval
+	}
+
+	rows := make(map[string]*model.CostReportRow)
+	row := func(key string) *model.CostReportRow {
+		if rows[key] == nil {
+			rows[key] = &model.CostReportRow{Key: key}
+		}
+		return rows[key]
+	}
+
+	for _, fuel := range fuels {
+		var key string
+		switch groupBy {
+		case model.CostGroupByCar:
+			key = fuel.CarID.Hex()
+		case model.CostGroupByDriver:
+			if fuel.DriverID != nil {
+				key = fuel.DriverID.Hex()
+			}
+		default:
+			key = periodKey(fuel.CreatedAt, interval)
+		}
+
+		r := row(key)
+		r.Refuels++
+		r.Liters += fuel.Liters
+		r.FuelCost += fuel.TotalCost
+	}
+
+	for _, entry := range entries {
+		var key string
+		switch groupBy {
+		case model.CostGroupByCar:
+			key = entry.CarID.Hex()
+		case model.CostGroupByDriver:
+			key = entry.UserID.Hex()
+		default:
+			key = periodKey(*entry.EndedAt, interval)
+		}
+
+		r := row(key)
+		r.Trips++
+		if entry.KMDriven != nil {
+			r.KMDriven += *entry.KMDriven
+		}
+	}
+
+	for key, r := range rows {
+		r.Label = s.label(ctx, groupBy, key)
+		setCostPerKM(r)
+		report.Rows = append(report.Rows, *r)
+
+		report.Total.Refuels += r.Refuels
+		report.Total.Liters += r.Liters
+		report.Total.FuelCost += r.FuelCost
+		report.Total.Trips += r.Trips
+		report.Total.KMDriven += r.KMDriven
+	}
+	setCostPerKM(&report.Total)
+
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if groupBy == model.CostGroupByPeriod {
+			return report.Rows[i].Key < report.Rows[j].Key
+		}
+		return report.Rows[i].FuelCost > report.Rows[j].FuelCost
+	})
+	return report, nil
+}
+
+func setCostPerKM(r *model.CostReportRow) {
+	if r.KMDriven > 0 {
+		costPerKM := r.FuelCost / r.KMDriven
+		r.CostPerKM = &costPerKM
+	}
+}
+
+func periodKey(t time.Time, interval string) string {
+	if interval == model.CostIntervalDay {
+		return t.Local().Format("2006-01-02")
+	}
+	return t.Local().Format("2006-01")
+}
+
+// label retorna a placa do carro ou o nome do motorista; para períodos, a
+// própria chave.
+func (s *CostReportService) label(ctx context.Context, groupBy, key string) string {
+	if groupBy == model.CostGroupByPeriod {
+		return key
+	}
+
+	id, err := primitive.ObjectIDFromHex(key)
+	if err != nil {
+		return "Não informado"
+	}
+
+	if groupBy == model.CostGroupByCar {
+		if car, err := s.cars.FindByID(ctx, id); err == nil {
+			return car.Plate
+		}
+	} else if user, err := s.users.FindByID(ctx, id); err == nil {
+		return user.Name
+	}
+	return "Removido"
+}
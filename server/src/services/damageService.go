@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidDamageTransition = errors.New("a avaria não pode passar para este status")
+	ErrDamageClosed            = errors.New("a avaria já foi encerrada")
+	ErrAssigneeNotFound        = errors.New("responsável não encontrado")
+)
+
+// damageTransitions lista, para cada status, de quais status a avaria pode
+// chegar a ele.
+var damageTransitions = map[string][]string{
+	model.DamageStatusOpen:     {model.DamageStatusInRepair, model.DamageStatusResolved, model.DamageStatusRejected},
+	model.DamageStatusInRepair: {model.DamageStatusOpen},
+	model.DamageStatusResolved: {model.DamageStatusOpen, model.DamageStatusInRepair},
+	model.DamageStatusRejected: {model.DamageStatusOpen},
+}
+
+var activeDamageStatuses = []string{model.DamageStatusOpen, model.DamageStatusInRepair}
+
+type DamageService struct {
+	damages    repository.DamageRepository
+	carEntries repository.CarEntryRepository
+	users      repository.UserRepository
+}
+
+func NewDamageService(store *repository.Store) *DamageService {
+	return &DamageService{damages: store.Damages, carEntries: store.CarEntries, users: store.Users}
+}
+
+// Report abre a avaria com status open e o primeiro evento do histórico.
+func (s *DamageService) Report(ctx context.Context, damage *model.Damage) error {
+	now := time.Now()
+	damage.ID = primitive.NewObjectID()
+	damage.Status = model.DamageStatusOpen
+	damage.AssigneeID = nil
+	damage.Photos = nil
+	damage.CreatedAt = now
+	damage.UpdatedAt = now
+	damage.History = []model.DamageEvent{{
+		Status:     model.DamageStatusOpen,
+		CarEntryID: damage.CarEntryID,
+		Stage:      damage.Stage,
+		By:         damage.ReportedBy,
+		At:         now,
+	}}
+	return s.damages.Create(ctx, damage)
+}
+
+// ReportChecklistFailures abre uma avaria para cada item reprovado do
+// checklist. Se o carro já tiver uma avaria ativa para o mesmo item, o novo
+// relato é registrado no histórico dela em vez de duplicá-la.
+func (s *DamageService) ReportChecklistFailures(ctx context.Context, entry *model.CarEntry, stage string, checklist *model.Checklist) error {
+	if checklist == nil {
+		return nil
+	}
+
+	for _, item := range checklist.Items {
+		if item.Result != model.ChecklistResultFail {
+			continue
+		}
+
+		active, err := s.damages.List(ctx, repository.DamageFilter{
+			CarID:            &entry.CarID,
+			ChecklistItemKey: item.Key,
+			Status:           activeDamageStatuses,
+		})
+		if err != nil {
+			return err
+		}
+
+		entryID := entry.ID
+		if len(active) > 0 {
+			_, err = s.damages.AddEvent(ctx, active[0].ID, activeDamageStatuses, model.DamageEvent{
+				CarEntryID: &entryID,
+				Stage:      stage,
+				Note:       "Relatado novamente: " + item.Notes,
+				By:         entry.UserID,
+				At:         time.Now(),
+			})
+			if err != nil && err != repository.ErrNotFound {
+				return err
+			}
+			continue
+		}
+
+		err = s.Report(ctx, &model.Damage{
+			CarID:            entry.CarID,
+			CarEntryID:       &entryID,
+			Stage:            stage,
+			ChecklistItemKey: item.Key,
+			Location:         item.Label,
+			Description:      item.Notes,
+			Severity:         model.DamageSeverityMedium,
+			ReportedBy:       entry.UserID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AttachChecklistPhoto copia a foto do item do checklist para a avaria aberta
+// por ele na mesma entrada, se houver.
+func (s *DamageService) AttachChecklistPhoto(ctx context.Context, entryID primitive.ObjectID, stage, key, path string) error {
+	damages, err := s.damages.List(ctx, repository.DamageFilter{CarEntryID: &entryID, Stage: stage, ChecklistItemKey: key})
+	if err != nil {
+		return err
+	}
+	for _, damage := range damages {
+		if err := s.damages.AddPhotos(ctx, damage.ID, []string{path}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *DamageService) Transition(ctx context.Context, id primitive.ObjectID, status, note string, by primitive.ObjectID) (*model.Damage, error) {
+	from, ok := damageTransitions[status]
+	if !ok {
+		return nil, ErrInvalidDamageTransition
+	}
+	if _, err := s.damages.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	damage, err := s.damages.AddEvent(ctx, id, from, model.DamageEvent{Status: status, Note: note, By: by, At: time.Now()})
+	if err == repository.ErrNotFound {
+		return nil, ErrInvalidDamageTransition
+	}
+	return damage, err
+}
+
+// Assign define o responsável pelo reparo de uma avaria ainda ativa.
+func (s *DamageService) Assign(ctx context.Context, id, assigneeID primitive.ObjectID, note string, by primitive.ObjectID) (*model.Damage, error) {
+	if _, err := s.damages.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if _, err := s.users.FindByID(ctx, assigneeID); err == repository.ErrNotFound {
+		return nil, ErrAssigneeNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	damage, err := s.damages.AddEvent(ctx, id, activeDamageStatuses, model.DamageEvent{AssigneeID: &assigneeID, Note: note, By: by, At: time.Now()})
+	if err == repository.ErrNotFound {
+		return nil, ErrDamageClosed
+	}
+	return damage, err
+}
+
+// damageStatusAt reconstrói pelo histórico o status da avaria no instante t.
+// Retorna "" se a avaria ainda não existia.
+func damageStatusAt(damage model.Damage, t time.Time) string {
+	status := ""
+	for _, event := range damage.History {
+		if event.At.After(t) {
+			break
+		}
+		if event.Status != "" {
+			status = event.Status
+		}
+	}
+	return status
+}
+
+// Compare separa as avarias do carro em: ativas no check-out anterior, novas
+// (relatadas no check-in da entrada ou entre o check-out anterior e esse
+// check-in) e já conhecidas que foram relatadas novamente nesse check-in.
+func (s *DamageService) Compare(ctx context.Context, entryID primitive.ObjectID) (*model.DamageComparison, error) {
+	entry, err := s.carEntries.FindByID(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.carEntries.ListByCar(ctx, entry.CarID)
+	if err != nil {
+		return nil, err
+	}
+	damages, err := s.damages.List(ctx, repository.DamageFilter{CarID: &entry.CarID})
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &model.DamageComparison{
+		CarID:           entry.CarID,
+		CarEntryID:      entry.ID,
+		CheckInAt:       entry.StartedAt,
+		KnownAtCheckOut: []model.Damage{},
+		NewDamages:      []model.Damage{},
+		ReportedAgain:   []model.Damage{},
+	}
+	for _, previous := range entries {
+		if previous.ID == entry.ID || previous.EndedAt == nil || previous.EndedAt.After(entry.StartedAt) {
+			continue
+		}
+		if comparison.PreviousCheckOutAt == nil || previous.EndedAt.After(*comparison.PreviousCheckOutAt) {
+			previousID, endedAt := previous.ID, *previous.EndedAt
+			comparison.PreviousEntryID = &previousID
+			comparison.PreviousCheckOutAt = &endedAt
+		}
+	}
+
+	for _, damage := range damages {
+		known := false
+		if comparison.PreviousCheckOutAt != nil {
+			status := damageStatusAt(damage, *comparison.PreviousCheckOutAt)
+			known = containsString(activeDamageStatuses, status)
+		}
+
+		if known {
+			comparison.KnownAtCheckOut = append(comparison.KnownAtCheckOut, damage)
+			for _, event := range damage.History {
+				if event.CarEntryID != nil && *event.CarEntryID == entry.ID && event.Stage == model.ChecklistStageCheckIn {
+					comparison.ReportedAgain = append(comparison.ReportedAgain, damage)
+					break
+				}
+			}
+			continue
+		}
+
+		atCheckIn := damage.CarEntryID != nil && *damage.CarEntryID == entry.ID && damage.Stage == model.ChecklistStageCheckIn
+		betweenEntries := comparison.PreviousCheckOutAt != nil &&
+			damage.CreatedAt.After(*comparison.PreviousCheckOutAt) && damage.CreatedAt.Before(entry.StartedAt)
+		if atCheckIn || betweenEntries {
+			comparison.NewDamages = append(comparison.NewDamages, damage)
+		}
+	}
+	return comparison, nil
+}
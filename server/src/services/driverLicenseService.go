@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultCNHExpiryDays é o prazo padrão, em dias, do relatório de CNHs a
+// vencer.
+const defaultCNHExpiryDays = 30
+
+// LicenseError indica que o motorista não pode conduzir o carro; a mensagem
+// pode ser devolvida diretamente ao cliente.
+type LicenseError struct {
+	Message string
+}
+
+func (e *LicenseError) Error() string {
+	return e.Message
+}
+
+// CNHExpiryDays lê CNH_EXPIRY_WARNING_DAYS, usando o padrão quando a variável
+// está ausente ou inválida.
+func CNHExpiryDays() int {
+	days, err := strconv.Atoi(os.Getenv("CNH_EXPIRY_WARNING_DAYS"))
+	if err != nil || days <= 0 {
+		return defaultCNHExpiryDays
+	}
+	return days
+}
+
+type DriverLicenseService struct {
+	users repository.UserRepository
+	cars  repository.CarRepository
+}
+
+func NewDriverLicenseService(store *repository.Store) *DriverLicenseService {
+	return &DriverLicenseService{users: store.Users, cars: store.Cars}
+}
+
+// CheckDriver recusa o motorista com CNH vencida ou sem categoria que cubra a
+// exigida pelo carro. Motoristas sem validade cadastrada não são barrados
+// pelo vencimento.
+func (s *DriverLicenseService) CheckDriver(ctx context.Context, userID, carID primitive.ObjectID) error {
+	user, err := s.users.FindByID(ctx, userID)
+	if err == repository.ErrNotFound {
+		return &LicenseError{Message: "Motorista não encontrado"}
+	}
+	if err != nil {
+		return err
+	}
+	car, err := s.cars.FindByID(ctx, carID)
+	if err != nil {
+		return err
+	}
+
+	if user.CNHExpiresAt != nil && !time.Now().Before(*user.CNHExpiresAt) {
+		return &LicenseError{Message: fmt.Sprintf("CNH do motorista vencida em %s", user.CNHExpiresAt.Format("02/01/2006"))}
+	}
+	if car.RequiredLicense != "" && !helper.CNHCovers(user.CNHCategories, car.RequiredLicense) {
+		held := "nenhuma"
+		if len(user.CNHCategories) > 0 {
+			held = strings.Join(user.CNHCategories, ", ")
+		}
+		return &LicenseError{Message: fmt.Sprintf("O carro exige CNH categoria %s; categorias do motorista: %s", car.RequiredLicense, held)}
+	}
+	return nil
+}
+
+// Expiring lista os motoristas ativos cuja CNH vence nos próximos days dias,
+// incluindo as já vencidas, da mais urgente à menos.
+func (s *DriverLicenseService) Expiring(ctx context.Context, days int) ([]model.CNHExpiry, error) {
+	users, err := s.users.List(ctx, repository.UserFilter{IsActive: true})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	limit := now.AddDate(0, 0, days)
+	result := []model.CNHExpiry{}
+	for _, user := range users {
+		if user.CNHExpiresAt == nil || user.CNHExpiresAt.After(limit) {
+			continue
+		}
+		result = append(result, model.CNHExpiry{
+			UserID:     user.ID,
+			Name:       user.Name,
+			Email:      user.Email,
+			CNH:        user.CNH,
+			Categories: user.CNHCategories,
+			ExpiresAt:  *user.CNHExpiresAt,
+			DaysLeft:   daysUntil(*user.CNHExpiresAt, now),
+			Expired:    !now.Before(*user.CNHExpiresAt),
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ExpiresAt.Before(result[j].ExpiresAt) })
+	return result, nil
+}
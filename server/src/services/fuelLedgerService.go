@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fuelLedgerTolerance é a diferença máxima, em litros, aceita entre o saldo
+// gravado e o recalculado antes de considerar o registro divergente.
+const fuelLedgerTolerance = 0.01
+
+// legacyCheckOutWindow é a distância máxima entre endedAt da entrada e
+// createdAt do registro de combustível para associar check-outs antigos,
+// gravados antes do campo carEntryID.
+const legacyCheckOutWindow = time.Minute
+
+type FuelLedgerService struct {
+	cars       repository.CarRepository
+	carEntries repository.CarEntryRepository
+	fuels      repository.FuelRepository
+	transactor repository.Transactor
+}
+
+func NewFuelLedgerService(store *repository.Store) *FuelLedgerService {
+	return &FuelLedgerService{
+		cars:       store.Cars,
+		carEntries: store.CarEntries,
+		fuels:      store.Fuels,
+		transactor: store.Transactor,
+	}
+}
+
+type fuelLedgerEvent struct {
+	at     time.Time
+	fuel   model.Fuel
+	amount float64
+	stored *model.Fuel
+}
+
+// Check refaz, em ordem cronológica, os abastecimentos e check-outs do carro
+// e compara o resultado com o histórico de combustível gravado.
+func (s *FuelLedgerService) Check(ctx context.Context, carID primitive.ObjectID) (*model.FuelLedgerReport, error) {
+	car, err := s.cars.FindByID(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.carEntries.ListByCar(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.fuels.ListByCar(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+
+	return replayFuelLedger(car, entries, stored), nil
+}
+
+// Rebuild executa Check e substitui o histórico gravado pelo recalculado,
+// na mesma transação. O relatório retornado descreve o estado anterior.
+func (s *FuelLedgerService) Rebuild(ctx context.Context, carID primitive.ObjectID) (*model.FuelLedgerReport, error) {
+	var report *model.FuelLedgerReport
+	err := s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.fuels.LockCar(ctx, carID); err != nil {
+			return err
+		}
+		var err error
+		report, err = s.Check(ctx, carID)
+		if err != nil {
+			return err
+		}
+		return s.fuels.ReplaceByCar(ctx, carID, report.Ledger)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Rewritten = true
+	return report, nil
+}
+
+// refuelDetails copia os dados do abastecimento para o histórico recalculado,
+// descartando apenas os saldos, que serão refeitos.
+func refuelDetails(fuel model.Fuel) model.Fuel {
+	fuel.Type = model.FuelTypeRefuel
+	fuel.PreviousFuel = 0
+	fuel.NewFuel = 0
+	return fuel
+}
+
+func isRefuel(fuel model.Fuel) bool {
+	return fuel.Type == model.FuelTypeRefuel || (fuel.Type == "" && fuel.KMDriven == 0)
+}
+
+func isCheckOut(fuel model.Fuel) bool {
+	return fuel.Type == model.FuelTypeCheckOut || (fuel.Type == "" && fuel.KMDriven > 0)
+}
+
+func replayFuelLedger(car *model.Car, entries []model.CarEntry, stored []model.Fuel) *model.FuelLedgerReport {
+	report := &model.FuelLedgerReport{
+		CarID:       car.ID,
+		Plate:       car.Plate,
+		Divergences: []model.FuelLedgerDivergence{},
+		Unmatched:   []model.Fuel{},
+		Ledger:      []model.Fuel{},
+	}
+	if len(stored) > 0 {
+		report.StoredCurrentFuel = stored[len(stored)-1].NewFuel
+	}
+
+	used := make(map[primitive.ObjectID]bool)
+	checkOutsByEntry := make(map[primitive.ObjectID]*model.Fuel)
+	var events []fuelLedgerEvent
+
+	for i := range stored {
+		fuel := &stored[i]
+		if isRefuel(*fuel) {
+			used[fuel.ID] = true
+			events = append(events, fuelLedgerEvent{
+				at:     fuel.CreatedAt,
+				fuel:   refuelDetails(*fuel),
+				amount: fuel.NewFuel - fuel.PreviousFuel,
+				stored: fuel,
+			})
+		} else if isCheckOut(*fuel) && fuel.CarEntryID != nil {
+			checkOutsByEntry[*fuel.CarEntryID] = fuel
+		}
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		if entry.CheckOut == nil || entry.KMDriven == nil {
+			continue
+		}
+
+		endedAt := entry.StartedAt
+		if entry.EndedAt != nil {
+			endedAt = *entry.EndedAt
+		}
+
+		match := checkOutsByEntry[entry.ID]
+		if match == nil {
+			match = findLegacyCheckOut(stored, used, *entry.KMDriven, endedAt)
+		}
+		if match != nil {
+			used[match.ID] = true
+		}
+
+		entryID := entry.ID
+		events = append(events, fuelLedgerEvent{
+			at: endedAt,
+			fuel: model.Fuel{
+				Type:       model.FuelTypeCheckOut,
+				CarEntryID: &entryID,
+				KMDriven:   *entry.KMDriven,
+			},
+			amount: -*entry.KMDriven / car.Consumption,
+			stored: match,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].at.Before(events[j].at)
+	})
+
+	level := 0.0
+	for _, event := range events {
+		fuel := event.fuel
+		fuel.CarID = car.ID
+		fuel.PreviousFuel = level
+		fuel.NewFuel = level + event.amount
+		fuel.ID = primitive.NewObjectID()
+		fuel.CreatedAt = event.at
+		level = fuel.NewFuel
+
+		if event.stored != nil {
+			fuel.ID = event.stored.ID
+			fuel.CreatedAt = event.stored.CreatedAt
+
+			if math.Abs(event.stored.NewFuel-fuel.NewFuel) > fuelLedgerTolerance ||
+				math.Abs(event.stored.PreviousFuel-fuel.PreviousFuel) > fuelLedgerTolerance {
+				storedFuel, expectedFuel := event.stored.NewFuel, fuel.NewFuel
+				report.Divergences = append(report.Divergences, model.FuelLedgerDivergence{
+					FuelID:       &fuel.ID,
+					CarEntryID:   fuel.CarEntryID,
+					Type:         fuel.Type,
+					Date:         event.at,
+					Issue:        "saldo gravado difere do recalculado",
+					StoredFuel:   &storedFuel,
+					ExpectedFuel: &expectedFuel,
+				})
+			}
+		} else {
+			expectedFuel := fuel.NewFuel
+			report.Divergences = append(report.Divergences, model.FuelLedgerDivergence{
+				CarEntryID:   fuel.CarEntryID,
+				Type:         fuel.Type,
+				Date:         event.at,
+				Issue:        "check-out sem registro de combustível",
+				ExpectedFuel: &expectedFuel,
+			})
+		}
+
+		report.Ledger = append(report.Ledger, fuel)
+	}
+
+	for _, fuel := range stored {
+		if !used[fuel.ID] {
+			report.Unmatched = append(report.Unmatched, fuel)
+		}
+	}
+
+	report.ExpectedCurrentFuel = level
+	report.Consistent = len(report.Divergences) == 0 &&
+		math.Abs(report.StoredCurrentFuel-report.ExpectedCurrentFuel) <= fuelLedgerTolerance
+	return report
+}
+
+func findLegacyCheckOut(stored []model.Fuel, used map[primitive.ObjectID]bool, kmDriven float64, endedAt time.Time) *model.Fuel {
+	for i := range stored {
+		fuel := &stored[i]
+		if used[fuel.ID] || !isCheckOut(*fuel) || fuel.CarEntryID != nil {
+			continue
+		}
+		if math.Abs(fuel.KMDriven-kmDriven) > 1e-9 {
+			continue
+		}
+		diff := fuel.CreatedAt.Sub(endedAt)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff <= legacyCheckOutWindow {
+			return fuel
+		}
+	}
+	return nil
+}
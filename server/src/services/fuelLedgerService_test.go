@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ledgerStart = time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+func ledgerCar() *model.Car {
+	return &model.Car{ID: primitive.NewObjectID(), Plate: "ABC1234", Capacity: 50, Consumption: 10, IsActive: true}
+}
+
+func closedEntry(carID primitive.ObjectID, kmDriven float64, endedAt time.Time) model.CarEntry {
+	return model.CarEntry{
+		ID:        primitive.NewObjectID(),
+		CarID:     carID,
+		UserID:    primitive.NewObjectID(),
+		StartedAt: endedAt.Add(-time.Hour),
+		CheckOut:  &model.CheckOut{ActualKM: kmDriven},
+		KMDriven:  &kmDriven,
+		EndedAt:   &endedAt,
+	}
+}
+
+func refuel(carID primitive.ObjectID, previous, liters float64, at time.Time) model.Fuel {
+	return model.Fuel{
+		ID:           primitive.NewObjectID(),
+		CarID:        carID,
+		Type:         model.FuelTypeRefuel,
+		PreviousFuel: previous,
+		NewFuel:      previous + liters,
+		Liters:       liters,
+		CreatedAt:    at,
+	}
+}
+
+func checkOutFuel(carID primitive.ObjectID, entryID *primitive.ObjectID, previous, kmDriven float64, at time.Time) model.Fuel {
+	return model.Fuel{
+		ID:           primitive.NewObjectID(),
+		CarID:        carID,
+		Type:         model.FuelTypeCheckOut,
+		CarEntryID:   entryID,
+		PreviousFuel: previous,
+		NewFuel:      previous - kmDriven/10,
+		KMDriven:     kmDriven,
+		CreatedAt:    at,
+	}
+}
+
+func assertLevels(t *testing.T, ledger []model.Fuel, levels ...float64) {
+	t.Helper()
+	if len(ledger) != len(levels) {
+		t.Fatalf("histórico com %d registros, esperado %d: %+v", len(ledger), len(levels), ledger)
+	}
+	previous := 0.0
+	for i, fuel := range ledger {
+		if math.Abs(fuel.PreviousFuel-previous) > 1e-9 || math.Abs(fuel.NewFuel-levels[i]) > 1e-9 {
+			t.Fatalf("registro %d: %.2f -> %.2f, esperado %.2f -> %.2f", i, fuel.PreviousFuel, fuel.NewFuel, previous, levels[i])
+		}
+		previous = levels[i]
+	}
+}
+
+func TestReplayFuelLedgerConsistent(t *testing.T) {
+	car := ledgerCar()
+	entry := closedEntry(car.ID, 100, ledgerStart.Add(2*time.Hour))
+	stored := []model.Fuel{
+		refuel(car.ID, 0, 40, ledgerStart),
+		checkOutFuel(car.ID, &entry.ID, 40, 100, ledgerStart.Add(2*time.Hour)),
+		refuel(car.ID, 30, 5, ledgerStart.Add(3*time.Hour)),
+	}
+
+	report := replayFuelLedger(car, []model.CarEntry{entry}, stored)
+	if !report.Consistent || len(report.Divergences) != 0 || len(report.Unmatched) != 0 {
+		t.Fatalf("relatório deveria ser consistente: %+v", report)
+	}
+	assertLevels(t, report.Ledger, 40, 30, 35)
+	for i := range stored {
+		if report.Ledger[i].ID != stored[i].ID {
+			t.Fatalf("registro %d deveria manter o ID gravado", i)
+		}
+	}
+	if report.ExpectedCurrentFuel != 35 || report.StoredCurrentFuel != 35 {
+		t.Fatalf("saldo = %.2f/%.2f, esperado 35", report.StoredCurrentFuel, report.ExpectedCurrentFuel)
+	}
+}
+
+func TestReplayFuelLedgerOrdersOutOfSequenceRecords(t *testing.T) {
+	car := ledgerCar()
+	entry := closedEntry(car.ID, 50, ledgerStart.Add(time.Hour))
+	stored := []model.Fuel{
+		refuel(car.ID, 0, 20, ledgerStart),
+		refuel(car.ID, 20, 10, ledgerStart.Add(2*time.Hour)),
+		checkOutFuel(car.ID, &entry.ID, 30, 50, ledgerStart.Add(3*time.Hour)),
+	}
+
+	report := replayFuelLedger(car, []model.CarEntry{entry}, stored)
+	assertLevels(t, report.Ledger, 20, 15, 25)
+	if report.Consistent || len(report.Divergences) != 2 {
+		t.Fatalf("esperadas 2 divergências: %+v", report.Divergences)
+	}
+	if report.StoredCurrentFuel != 25 || report.ExpectedCurrentFuel != 25 {
+		t.Fatalf("saldo = %.2f/%.2f, esperado 25", report.StoredCurrentFuel, report.ExpectedCurrentFuel)
+	}
+}
+
+func TestReplayFuelLedgerLegacyCheckOutWindow(t *testing.T) {
+	car := ledgerCar()
+	matched := closedEntry(car.ID, 100, ledgerStart.Add(time.Hour))
+	missing := closedEntry(car.ID, 50, ledgerStart.Add(2*time.Hour))
+	legacy := checkOutFuel(car.ID, nil, 40, 100, ledgerStart.Add(time.Hour+legacyCheckOutWindow))
+	legacy.Type = ""
+	stale := checkOutFuel(car.ID, nil, 30, 50, ledgerStart.Add(2*time.Hour+legacyCheckOutWindow+time.Second))
+	stored := []model.Fuel{refuel(car.ID, 0, 40, ledgerStart), legacy, stale}
+
+	report := replayFuelLedger(car, []model.CarEntry{matched, missing}, stored)
+	assertLevels(t, report.Ledger, 40, 30, 25)
+
+	if report.Ledger[1].ID != legacy.ID || report.Ledger[1].CarEntryID == nil || *report.Ledger[1].CarEntryID != matched.ID {
+		t.Fatalf("check-out antigo dentro da janela deveria ser associado à entrada: %+v", report.Ledger[1])
+	}
+	if report.Ledger[1].Type != model.FuelTypeCheckOut {
+		t.Fatalf("tipo = %q, esperado %q", report.Ledger[1].Type, model.FuelTypeCheckOut)
+	}
+	if len(report.Unmatched) != 1 || report.Unmatched[0].ID != stale.ID {
+		t.Fatalf("check-out fora da janela deveria ficar sem associação: %+v", report.Unmatched)
+	}
+	if len(report.Divergences) != 1 || report.Divergences[0].Issue != "check-out sem registro de combustível" ||
+		*report.Divergences[0].CarEntryID != missing.ID {
+		t.Fatalf("divergências = %+v", report.Divergences)
+	}
+}
+
+func TestReplayFuelLedgerLegacyCheckOutNeedsSameKM(t *testing.T) {
+	car := ledgerCar()
+	entry := closedEntry(car.ID, 100, ledgerStart.Add(time.Hour))
+	other := checkOutFuel(car.ID, nil, 0, 90, ledgerStart.Add(time.Hour))
+
+	report := replayFuelLedger(car, []model.CarEntry{entry}, []model.Fuel{other})
+	if len(report.Unmatched) != 1 || len(report.Divergences) != 1 {
+		t.Fatalf("check-out com km diferente não deveria ser associado: %+v", report)
+	}
+}
+
+func TestReplayFuelLedgerSkipsOpenEntries(t *testing.T) {
+	car := ledgerCar()
+	open := model.CarEntry{ID: primitive.NewObjectID(), CarID: car.ID, StartedAt: ledgerStart}
+
+	report := replayFuelLedger(car, []model.CarEntry{open}, nil)
+	if !report.Consistent || len(report.Ledger) != 0 || report.ExpectedCurrentFuel != 0 {
+		t.Fatalf("entrada aberta não deveria gerar lançamentos: %+v", report)
+	}
+}
+
+func TestRebuildFuelLedgerWithEmptyLedger(t *testing.T) {
+	store := repository.NewMemoryStore()
+	ctx := context.Background()
+	car, other := ledgerCar(), ledgerCar()
+	other.Plate = "XYZ9876"
+	for _, c := range []*model.Car{car, other} {
+		if err := store.Cars.Create(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	orphan := checkOutFuel(car.ID, nil, 10, 50, ledgerStart)
+	kept := refuel(other.ID, 0, 20, ledgerStart)
+	for _, fuel := range []model.Fuel{orphan, kept} {
+		if err := store.Fuels.Create(ctx, &fuel); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := NewFuelLedgerService(store).Rebuild(ctx, car.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Rewritten || len(report.Ledger) != 0 || len(report.Unmatched) != 1 {
+		t.Fatalf("relatório = %+v", report)
+	}
+
+	fuels, err := store.Fuels.ListByCar(ctx, car.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fuels) != 0 {
+		t.Fatalf("o histórico do carro deveria ter sido esvaziado: %+v", fuels)
+	}
+	fuels, err = store.Fuels.ListByCar(ctx, other.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fuels) != 1 || fuels[0].ID != kept.ID {
+		t.Fatalf("o histórico de outros carros não deveria mudar: %+v", fuels)
+	}
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Padrões da proteção contra força bruta no login. As falhas de uma conta ou
+// de um IP são esquecidas após o período de bloqueio sem novas falhas.
+const (
+	defaultLoginMaxFailures    = 5
+	defaultLoginIPMaxFailures  = 50
+	defaultLoginLockoutMinutes = 15
+
+	// Falhas toleradas antes de a espera entre tentativas começar a dobrar.
+	loginAccountFreeFailures = 1
+	loginIPFreeFailures      = 10
+
+	maxLoginBackoff = 5 * time.Minute
+)
+
+// LoginThrottledError indica que a tentativa foi recusada sem conferir a
+// senha, porque a conta ou o IP precisam aguardar RetryAfter.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("muitas tentativas de login; tente novamente em %s", e.RetryAfter.Round(time.Second))
+}
+
+func countFromEnv(name string, fallback int) int {
+	count, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || count <= 0 {
+		count = fallback
+	}
+	return count
+}
+
+// loginPolicy define os limites aplicados às chaves de um tipo.
+type loginPolicy struct {
+	prefix       string
+	freeFailures int
+	maxFailures  int
+}
+
+func loginPolicies() []loginPolicy {
+	return []loginPolicy{
+		{prefix: "email:", freeFailures: loginAccountFreeFailures, maxFailures: countFromEnv("LOGIN_MAX_FAILURES", defaultLoginMaxFailures)},
+		{prefix: "ip:", freeFailures: loginIPFreeFailures, maxFailures: countFromEnv("LOGIN_IP_MAX_FAILURES", defaultLoginIPMaxFailures)},
+	}
+}
+
+// LoginLockoutDuration lê LOGIN_LOCKOUT_MINUTES, usando o padrão quando a
+// variável está ausente ou inválida.
+func LoginLockoutDuration() time.Duration {
+	return minutesFromEnv("LOGIN_LOCKOUT_MINUTES", defaultLoginLockoutMinutes)
+}
+
+// loginBackoff é a espera exigida após failures falhas consecutivas: zero
+// até o limite tolerado e, a partir dele, 1s, 2s, 4s... até maxLoginBackoff.
+func loginBackoff(failures, free int) time.Duration {
+	exceeded := failures - free
+	if exceeded <= 0 {
+		return 0
+	}
+	if exceeded > 16 {
+		return maxLoginBackoff
+	}
+	backoff := time.Duration(1<<(exceeded-1)) * time.Second
+	if backoff > maxLoginBackoff {
+		return maxLoginBackoff
+	}
+	return backoff
+}
+
+// NormalizeLoginEmail é a forma do email usada nas chaves de bloqueio e no
+// registro das tentativas, para que variações de caixa contem juntas.
+func NormalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func loginKey(policy loginPolicy, attempt *model.LoginAttempt) string {
+	if policy.prefix == "ip:" {
+		return policy.prefix + attempt.IP
+	}
+	return policy.prefix + NormalizeLoginEmail(attempt.Email)
+}
+
+// LoginGuardService limita as tentativas de login por conta e por IP e
+// registra cada tentativa. Os limites valem também para emails que não
+// pertencem a nenhum usuário, para não revelar quais contas existem.
+type LoginGuardService struct {
+	attempts repository.LoginAttemptRepository
+}
+
+func NewLoginGuardService(store *repository.Store) *LoginGuardService {
+	return &LoginGuardService{attempts: store.LoginAttempts}
+}
+
+// throttle devolve o estado vigente da chave, ou nil se não houver falhas
+// recentes.
+func (s *LoginGuardService) throttle(ctx context.Context, key string, now time.Time) (*model.LoginThrottle, error) {
+	throttle, err := s.attempts.FindThrottle(ctx, key)
+	if err == repository.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !now.Before(throttle.ExpiresAt) {
+		return nil, nil
+	}
+	return throttle, nil
+}
+
+func (s *LoginGuardService) record(ctx context.Context, attempt *model.LoginAttempt, result string, now time.Time) error {
+	attempt.ID = primitive.NewObjectID()
+	attempt.Email = NormalizeLoginEmail(attempt.Email)
+	attempt.Result = result
+	attempt.CreatedAt = now
+	return s.attempts.CreateAttempt(ctx, attempt)
+}
+
+// Check recusa a tentativa com *LoginThrottledError enquanto a conta ou o IP
+// estiverem bloqueados ou aguardando o intervalo entre tentativas. A
+// tentativa recusada também é registrada.
+func (s *LoginGuardService) Check(ctx context.Context, attempt *model.LoginAttempt) error {
+	now := time.Now()
+	var blocked *LoginThrottledError
+	for _, policy := range loginPolicies() {
+		throttle, err := s.throttle(ctx, loginKey(policy, attempt), now)
+		if err != nil {
+			return err
+		}
+		if throttle == nil {
+			continue
+		}
+
+		var wait time.Duration
+		locked := throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil)
+		if locked {
+			wait = throttle.LockedUntil.Sub(now)
+		} else {
+			wait = throttle.LastFailureAt.Add(loginBackoff(throttle.Failures, policy.freeFailures)).Sub(now)
+		}
+		if wait <= 0 {
+			continue
+		}
+		if blocked == nil {
+			blocked = &LoginThrottledError{}
+		}
+		if wait > blocked.RetryAfter {
+			blocked.RetryAfter = wait
+		}
+		blocked.Locked = blocked.Locked || locked
+	}
+	if blocked == nil {
+		return nil
+	}
+
+	result := model.LoginAttemptThrottled
+	if blocked.Locked {
+		result = model.LoginAttemptLocked
+	}
+	if err := s.record(ctx, attempt, result, now); err != nil {
+		return err
+	}
+	return blocked
+}
+
+// RecordFailure registra uma senha ou email incorretos e bloqueia a conta ou
+// o IP que atingirem o limite de falhas.
+func (s *LoginGuardService) RecordFailure(ctx context.Context, attempt *model.LoginAttempt) error {
+	now := time.Now()
+	if err := s.record(ctx, attempt, model.LoginAttemptInvalidCredentials, now); err != nil {
+		return err
+	}
+
+	lockout := LoginLockoutDuration()
+	for _, policy := range loginPolicies() {
+		key := loginKey(policy, attempt)
+		throttle, err := s.attempts.RegisterFailure(ctx, key, now, lockout, policy.maxFailures)
+		if err != nil {
+			return err
+		}
+		if throttle.LockedUntil != nil && throttle.Failures == policy.maxFailures {
+			log.Printf("Login bloqueado para %s até %s após %d falhas", key, throttle.LockedUntil.Format(time.RFC3339), throttle.Failures)
+		}
+	}
+	return nil
+}
+
+// RecordSuccess registra o login e zera as falhas da conta. As falhas do IP
+// continuam valendo, para que um login válido não libere tentativas contra
+// outras contas.
+func (s *LoginGuardService) RecordSuccess(ctx context.Context, attempt *model.LoginAttempt) error {
+	if err := s.record(ctx, attempt, model.LoginAttemptSuccess, time.Now()); err != nil {
+		return err
+	}
+	return s.Unlock(ctx, attempt.Email)
+}
+
+// Unlock libera a conta do email informado e zera as suas falhas.
+func (s *LoginGuardService) Unlock(ctx context.Context, email string) error {
+	return s.attempts.DeleteThrottle(ctx, "email:"+NormalizeLoginEmail(email))
+}
+
+// UnlockIP libera o IP informado e zera as suas falhas.
+func (s *LoginGuardService) UnlockIP(ctx context.Context, ip string) error {
+	return s.attempts.DeleteThrottle(ctx, "ip:"+ip)
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+)
+
+func TestLoginBackoff(t *testing.T) {
+	tests := []struct {
+		failures, free int
+		want           time.Duration
+	}{
+		{0, 1, 0},
+		{1, 1, 0},
+		{2, 1, time.Second},
+		{3, 1, 2 * time.Second},
+		{4, 1, 4 * time.Second},
+		{9, 1, 128 * time.Second},
+		{10, 1, 256 * time.Second},
+		{11, 1, maxLoginBackoff},
+		{17, 1, maxLoginBackoff},
+		{100, 1, maxLoginBackoff},
+		{10, 10, 0},
+		{11, 10, time.Second},
+		{13, 10, 4 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := loginBackoff(tt.failures, tt.free); got != tt.want {
+			t.Errorf("loginBackoff(%d, %d) = %s, esperado %s", tt.failures, tt.free, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterFailureLocksAtMaxFailures(t *testing.T) {
+	attempts := repository.NewMemoryStore().LoginAttempts
+	ctx := context.Background()
+	now := time.Now()
+	window := 15 * time.Minute
+
+	for i := 1; i <= 3; i++ {
+		throttle, err := attempts.RegisterFailure(ctx, "email:a@a.com", now, window, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if throttle.Failures != i {
+			t.Fatalf("falhas = %d, esperado %d", throttle.Failures, i)
+		}
+		if i < 3 && throttle.LockedUntil != nil {
+			t.Fatalf("bloqueado após %d falhas", i)
+		}
+		if i == 3 && (throttle.LockedUntil == nil || !throttle.LockedUntil.Equal(now.Add(window))) {
+			t.Fatalf("bloqueio = %v, esperado %s", throttle.LockedUntil, now.Add(window))
+		}
+	}
+
+	later := now.Add(time.Minute)
+	throttle, err := attempts.RegisterFailure(ctx, "email:a@a.com", later, window, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !throttle.LockedUntil.Equal(now.Add(window)) {
+		t.Fatalf("falhas durante o bloqueio não devem prorrogá-lo: %s", throttle.LockedUntil)
+	}
+	if !throttle.ExpiresAt.Equal(later.Add(window)) {
+		t.Fatalf("expiração = %s, esperado %s", throttle.ExpiresAt, later.Add(window))
+	}
+}
+
+func TestRegisterFailureResetsAfterExpiry(t *testing.T) {
+	attempts := repository.NewMemoryStore().LoginAttempts
+	ctx := context.Background()
+	now := time.Now()
+	window := 15 * time.Minute
+
+	for i := 0; i < 3; i++ {
+		if _, err := attempts.RegisterFailure(ctx, "ip:10.0.0.1", now, window, 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expired := now.Add(window)
+	throttle, err := attempts.RegisterFailure(ctx, "ip:10.0.0.1", expired, window, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if throttle.Failures != 1 || throttle.LockedUntil != nil {
+		t.Fatalf("registro expirado deveria recomeçar: %+v", throttle)
+	}
+	if !throttle.ExpiresAt.Equal(expired.Add(window)) {
+		t.Fatalf("expiração = %s, esperado %s", throttle.ExpiresAt, expired.Add(window))
+	}
+}
+
+func TestLoginGuardLocksAccount(t *testing.T) {
+	t.Setenv("LOGIN_MAX_FAILURES", "3")
+	guard := NewLoginGuardService(repository.NewMemoryStore())
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := guard.RecordFailure(ctx, &model.LoginAttempt{Email: "A@a.com", IP: "10.0.0.1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := guard.Check(ctx, &model.LoginAttempt{Email: "a@A.com ", IP: "10.0.0.2"})
+	var throttled *LoginThrottledError
+	if !errors.As(err, &throttled) || !throttled.Locked {
+		t.Fatalf("a conta deveria estar bloqueada, err = %v", err)
+	}
+	if throttled.RetryAfter <= LoginLockoutDuration()-time.Minute {
+		t.Fatalf("espera = %s, esperado perto de %s", throttled.RetryAfter, LoginLockoutDuration())
+	}
+
+	if err := guard.Unlock(ctx, "a@a.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := guard.Check(ctx, &model.LoginAttempt{Email: "a@a.com", IP: "10.0.0.2"}); err != nil {
+		t.Fatalf("a conta deveria estar liberada, err = %v", err)
+	}
+}
+
+func TestLoginGuardSuccessKeepsIPFailures(t *testing.T) {
+	store := repository.NewMemoryStore()
+	guard := NewLoginGuardService(store)
+	ctx := context.Background()
+
+	if err := guard.RecordFailure(ctx, &model.LoginAttempt{Email: "a@a.com", IP: "10.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := guard.RecordSuccess(ctx, &model.LoginAttempt{Email: "a@a.com", IP: "10.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.LoginAttempts.FindThrottle(ctx, "email:a@a.com"); err != repository.ErrNotFound {
+		t.Fatalf("as falhas da conta deveriam ter sido zeradas, err = %v", err)
+	}
+	throttle, err := store.LoginAttempts.FindThrottle(ctx, "ip:10.0.0.1")
+	if err != nil {
+		t.Fatalf("as falhas do IP deveriam continuar valendo: %v", err)
+	}
+	if throttle.Failures != 1 {
+		t.Fatalf("falhas do IP = %d, esperado 1", throttle.Failures)
+	}
+
+	attempts, err := store.LoginAttempts.ListAttempts(ctx, repository.LoginAttemptFilter{Email: "a@a.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 2 || attempts[0].Result != model.LoginAttemptSuccess || attempts[1].Result != model.LoginAttemptInvalidCredentials {
+		t.Fatalf("tentativas registradas = %+v", attempts)
+	}
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Antecedência usada para marcar um plano como due_soon quando ele não
+// define DueSoonKM ou DueSoonDays.
+const (
+	defaultDueSoonKM   = 1000.0
+	defaultDueSoonDays = 15
+)
+
+// MaintenanceError indica um plano ou registro de manutenção inválido; a
+// mensagem pode ser devolvida diretamente ao cliente.
+type MaintenanceError struct {
+	Message string
+}
+
+func (e *MaintenanceError) Error() string {
+	return e.Message
+}
+
+func maintenanceErrorf(format string, args ...interface{}) error {
+	return &MaintenanceError{Message: fmt.Sprintf(format, args...)}
+}
+
+type MaintenanceService struct {
+	cars        repository.CarRepository
+	carEntries  repository.CarEntryRepository
+	maintenance repository.MaintenanceRepository
+}
+
+func NewMaintenanceService(store *repository.Store) *MaintenanceService {
+	return &MaintenanceService{cars: store.Cars, carEntries: store.CarEntries, maintenance: store.Maintenance}
+}
+
+func (s *MaintenanceService) validatePlan(ctx context.Context, plan *model.MaintenancePlan) error {
+	plan.Model = strings.TrimSpace(plan.Model)
+	if (plan.CarID == nil) == (plan.Model == "") {
+		return maintenanceErrorf("informe o carro ou o modelo do plano, não ambos")
+	}
+	if plan.IntervalKM == 0 && plan.IntervalMonths == 0 {
+		return maintenanceErrorf("informe o intervalo em km ou em meses")
+	}
+	if plan.CarID != nil {
+		if _, err := s.cars.FindByID(ctx, *plan.CarID); err == repository.ErrNotFound {
+			return maintenanceErrorf("carro não encontrado")
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MaintenanceService) CreatePlan(ctx context.Context, plan *model.MaintenancePlan) error {
+	if err := s.validatePlan(ctx, plan); err != nil {
+		return err
+	}
+
+	plan.ID = primitive.NewObjectID()
+	plan.IsActive = true
+	plan.CreatedAt = time.Now()
+	plan.UpdatedAt = plan.CreatedAt
+	return s.maintenance.CreatePlan(ctx, plan)
+}
+
+func (s *MaintenanceService) UpdatePlan(ctx context.Context, id primitive.ObjectID, plan *model.MaintenancePlan) error {
+	if err := s.validatePlan(ctx, plan); err != nil {
+		return err
+	}
+
+	current, err := s.maintenance.FindPlanByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	plan.ID = current.ID
+	plan.IsActive = current.IsActive
+	plan.CreatedAt = current.CreatedAt
+	plan.UpdatedAt = time.Now()
+	return s.maintenance.ReplacePlan(ctx, plan)
+}
+
+func planApplies(plan model.MaintenancePlan, car model.Car) bool {
+	if plan.CarID != nil {
+		return *plan.CarID == car.ID
+	}
+	return strings.EqualFold(plan.Model, strings.TrimSpace(car.Model))
+}
+
+// CreateRecord registra uma manutenção. Sem CompletedAt ela fica em
+// andamento até ser concluída com CompleteRecord.
+func (s *MaintenanceService) CreateRecord(ctx context.Context, record *model.MaintenanceRecord) error {
+	car, err := s.cars.FindByID(ctx, record.CarID)
+	if err == repository.ErrNotFound {
+		return maintenanceErrorf("carro não encontrado")
+	}
+	if err != nil {
+		return err
+	}
+
+	if record.PlanID != nil {
+		plan, err := s.maintenance.FindPlanByID(ctx, *record.PlanID)
+		if err == repository.ErrNotFound {
+			return maintenanceErrorf("plano de manutenção não encontrado")
+		}
+		if err != nil {
+			return err
+		}
+		if !planApplies(*plan, *car) {
+			return maintenanceErrorf("o plano %q não se aplica a este carro", plan.Name)
+		}
+	}
+
+	now := time.Now()
+	if record.StartedAt.IsZero() {
+		record.StartedAt = now
+		if record.CompletedAt != nil {
+			record.StartedAt = *record.CompletedAt
+		}
+	}
+	if record.StartedAt.After(now) || (record.CompletedAt != nil && record.CompletedAt.After(now)) {
+		return maintenanceErrorf("datas da manutenção não podem estar no futuro")
+	}
+	if record.CompletedAt != nil && record.CompletedAt.Before(record.StartedAt) {
+		return maintenanceErrorf("conclusão anterior ao início da manutenção")
+	}
+
+	record.ID = primitive.NewObjectID()
+	record.Invoices = nil
+	record.CreatedAt = now
+	return s.maintenance.CreateRecord(ctx, record)
+}
+
+// CompleteRecord conclui a manutenção agora. Odômetro e custo iguais a zero
+// mantêm os valores informados na abertura.
+func (s *MaintenanceService) CompleteRecord(ctx context.Context, id primitive.ObjectID, odometer, cost float64) (*model.MaintenanceRecord, error) {
+	record, err := s.maintenance.FindRecordByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if record.CompletedAt != nil {
+		return nil, maintenanceErrorf("manutenção já concluída")
+	}
+	if odometer == 0 {
+		odometer = record.Odometer
+	}
+	if cost == 0 {
+		cost = record.Cost
+	}
+
+	record, err = s.maintenance.CompleteRecord(ctx, id, time.Now(), odometer, cost)
+	if err == repository.ErrNotFound {
+		return nil, maintenanceErrorf("manutenção já concluída")
+	}
+	return record, err
+}
+
+// odometerAt é a maior leitura de odômetro conhecida até t, considerando os
+// check-outs e as manutenções concluídas.
+func odometerAt(entries []model.CarEntry, records []model.MaintenanceRecord, t time.Time) float64 {
+	odometer := 0.0
+	for _, entry := range entries {
+		if entry.CheckOut != nil && entry.EndedAt != nil && !entry.EndedAt.After(t) {
+			odometer = math.Max(odometer, entry.CheckOut.ActualKM)
+		}
+	}
+	for _, record := range records {
+		if record.CompletedAt != nil && !record.CompletedAt.After(t) {
+			odometer = math.Max(odometer, record.Odometer)
+		}
+	}
+	return odometer
+}
+
+// computeDue calcula o próximo vencimento do plano a partir da última
+// manutenção concluída. Sem manutenção registrada, a contagem começa na
+// criação do plano, com a quilometragem que o carro tinha naquela data.
+func computeDue(plan model.MaintenancePlan, car model.Car, entries []model.CarEntry, records []model.MaintenanceRecord, current float64, now time.Time) model.MaintenanceDue {
+	due := model.MaintenanceDue{
+		CarID:           car.ID,
+		Plate:           car.Plate,
+		PlanID:          plan.ID,
+		PlanName:        plan.Name,
+		CurrentOdometer: current,
+		Status:          model.MaintenanceDueOK,
+	}
+
+	baseDate := plan.CreatedAt
+	baseKM := odometerAt(entries, records, plan.CreatedAt)
+	for _, record := range records {
+		if record.PlanID != nil && *record.PlanID == plan.ID && record.CompletedAt != nil {
+			recordID, completedAt := record.ID, *record.CompletedAt
+			due.LastRecordID = &recordID
+			due.LastCompletedAt = &completedAt
+			baseDate, baseKM = completedAt, record.Odometer
+			break
+		}
+	}
+	due.LastOdometer = baseKM
+
+	dueSoonKM := plan.DueSoonKM
+	if dueSoonKM == 0 {
+		dueSoonKM = defaultDueSoonKM
+	}
+	dueSoonDays := plan.DueSoonDays
+	if dueSoonDays == 0 {
+		dueSoonDays = defaultDueSoonDays
+	}
+
+	overdue, dueSoon := false, false
+	if plan.IntervalKM > 0 {
+		nextKM := baseKM + plan.IntervalKM
+		remaining := nextKM - current
+		due.NextDueKM, due.RemainingKM = &nextKM, &remaining
+		overdue = overdue || remaining <= 0
+		dueSoon = dueSoon || remaining <= dueSoonKM
+	}
+	if plan.IntervalMonths > 0 {
+		nextDate := baseDate.AddDate(0, plan.IntervalMonths, 0)
+		remaining := int(math.Floor(nextDate.Sub(now).Hours() / 24))
+		due.NextDueDate, due.RemainingDays = &nextDate, &remaining
+		overdue = overdue || !now.Before(nextDate)
+		dueSoon = dueSoon || remaining <= dueSoonDays
+	}
+
+	switch {
+	case overdue:
+		due.Status = model.MaintenanceDueOverdue
+	case dueSoon:
+		due.Status = model.MaintenanceDueSoon
+	}
+	return due
+}
+
+var maintenanceDueRank = map[string]int{
+	model.MaintenanceDueOverdue: 0,
+	model.MaintenanceDueSoon:    1,
+	model.MaintenanceDueOK:      2,
+}
+
+// Schedule calcula o vencimento de cada plano ativo para o carro informado ou,
+// com carID nulo, para todos os carros ativos. Os vencidos vêm primeiro.
+func (s *MaintenanceService) Schedule(ctx context.Context, carID *primitive.ObjectID) ([]model.MaintenanceDue, error) {
+	plans, err := s.maintenance.ListPlans(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var cars []model.Car
+	if carID != nil {
+		car, err := s.cars.FindByID(ctx, *carID)
+		if err != nil {
+			return nil, err
+		}
+		cars = []model.Car{*car}
+	} else {
+		cars, err = s.cars.List(ctx, repository.CarFilter{IsActive: true})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	completed := false
+	records, err := s.maintenance.ListRecords(ctx, repository.MaintenanceRecordFilter{CarID: carID, InProgress: &completed})
+	if err != nil {
+		return nil, err
+	}
+	recordsByCar := make(map[primitive.ObjectID][]model.MaintenanceRecord)
+	for _, record := range records {
+		recordsByCar[record.CarID] = append(recordsByCar[record.CarID], record)
+	}
+
+	now := time.Now()
+	result := []model.MaintenanceDue{}
+	for _, car := range cars {
+		var applicable []model.MaintenancePlan
+		for _, plan := range plans {
+			if planApplies(plan, car) {
+				applicable = append(applicable, plan)
+			}
+		}
+		if len(applicable) == 0 {
+			continue
+		}
+
+		entries, err := s.carEntries.ListByCar(ctx, car.ID)
+		if err != nil {
+			return nil, err
+		}
+		current := odometerAt(entries, recordsByCar[car.ID], now)
+		for _, plan := range applicable {
+			result = append(result, computeDue(plan, car, entries, recordsByCar[car.ID], current, now))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Status != result[j].Status {
+			return maintenanceDueRank[result[i].Status] < maintenanceDueRank[result[j].Status]
+		}
+		return result[i].Plate < result[j].Plate
+	})
+	return result, nil
+}
+
+// InProgress lista as manutenções ainda não concluídas do carro informado
+// ou, com carID nulo, de toda a frota.
+func (s *MaintenanceService) InProgress(ctx context.Context, carID *primitive.ObjectID) ([]model.MaintenanceRecord, error) {
+	inProgress := true
+	return s.maintenance.ListRecords(ctx, repository.MaintenanceRecordFilter{CarID: carID, InProgress: &inProgress})
+}
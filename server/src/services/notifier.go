@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Notifier entrega mensagens aos usuários fora do sistema, como o link de
+// redefinição de senha.
+type Notifier interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// NewNotifier usa SMTP quando SMTP_HOST está definido e, caso contrário, o
+// StubNotifier, que apenas registra as mensagens no log.
+func NewNotifier() Notifier {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		log.Println("Warning: SMTP_HOST não definido; mensagens aos usuários serão apenas registradas no log")
+		return &StubNotifier{}
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = os.Getenv("SMTP_USERNAME")
+	}
+	return &SMTPNotifier{
+		Addr:     net.JoinHostPort(host, port),
+		Host:     host,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	}
+}
+
+// SMTPNotifier envia as mensagens por email, em texto simples. A conexão usa
+// STARTTLS quando o servidor oferece.
+type SMTPNotifier struct {
+	Addr     string
+	Host     string
+	Username string
+	Password string
+	From     string
+}
+
+// parseMailAddress valida um endereço usado nos cabeçalhos da mensagem,
+// recusando quebras de linha que permitiriam injetar outros cabeçalhos.
+func parseMailAddress(address string) (*mail.Address, error) {
+	if strings.ContainsAny(address, "\r\n") {
+		return nil, fmt.Errorf("endereço de email inválido: %q", address)
+	}
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("endereço de email inválido %q: %w", address, err)
+	}
+	return parsed, nil
+}
+
+func (n *SMTPNotifier) Send(ctx context.Context, to, subject, body string) error {
+	from, err := parseMailAddress(n.From)
+	if err != nil {
+		return err
+	}
+	recipient, err := parseMailAddress(to)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if n.Username != "" {
+		auth = smtp.PlainAuth("", n.Username, n.Password, n.Host)
+	}
+
+	headers := []string{
+		"From: " + from.String(),
+		"To: " + recipient.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding: 8bit",
+	}
+	message := strings.Join(headers, "\r\n") + "\r\n\r\n" + strings.ReplaceAll(body, "\n", "\r\n")
+
+	if err := smtp.SendMail(n.Addr, auth, from.Address, []string{recipient.Address}, []byte(message)); err != nil {
+		return fmt.Errorf("erro ao enviar email para %s: %w", to, err)
+	}
+	return nil
+}
+
+// NotifierMessage é uma mensagem guardada pelo StubNotifier.
+type NotifierMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// StubNotifier registra as mensagens no log e as guarda em memória, para
+// desenvolvimento local e testes.
+type StubNotifier struct {
+	mu       sync.Mutex
+	messages []NotifierMessage
+}
+
+func (n *StubNotifier) Send(ctx context.Context, to, subject, body string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.messages = append(n.messages, NotifierMessage{To: to, Subject: subject, Body: body})
+	log.Printf("Mensagem para %s: %s\n%s", to, subject, body)
+	return nil
+}
+
+// Messages devolve as mensagens enviadas até agora, da mais antiga para a
+// mais recente.
+func (n *StubNotifier) Messages() []NotifierMessage {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]NotifierMessage(nil), n.messages...)
+}
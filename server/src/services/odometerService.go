@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// defaultOdometerTolerance é a diferença aceita entre leituras, para
+	// acomodar arredondamentos e o deslocamento até a vaga.
+	defaultOdometerTolerance = 5.0
+	// defaultOdometerMaxGap é o maior avanço aceito no check-in em relação à
+	// última leitura; acima disso o valor é tratado como erro de digitação.
+	defaultOdometerMaxGap = 500.0
+)
+
+// OdometerError indica uma leitura de odômetro incompatível com o histórico
+// do carro; a mensagem pode ser devolvida diretamente ao cliente.
+type OdometerError struct {
+	Message string
+}
+
+func (e *OdometerError) Error() string {
+	return e.Message
+}
+
+// OdometerTolerance lê ODOMETER_TOLERANCE_KM, usando o padrão quando a
+// variável está ausente ou inválida.
+func OdometerTolerance() float64 {
+	tolerance, err := strconv.ParseFloat(os.Getenv("ODOMETER_TOLERANCE_KM"), 64)
+	if err != nil || tolerance < 0 {
+		return defaultOdometerTolerance
+	}
+	return tolerance
+}
+
+// OdometerMaxGap lê ODOMETER_MAX_GAP_KM, usando o padrão quando a variável
+// está ausente ou inválida.
+func OdometerMaxGap() float64 {
+	maxGap, err := strconv.ParseFloat(os.Getenv("ODOMETER_MAX_GAP_KM"), 64)
+	if err != nil || maxGap <= 0 {
+		return defaultOdometerMaxGap
+	}
+	return maxGap
+}
+
+type OdometerService struct {
+	cars        repository.CarRepository
+	carEntries  repository.CarEntryRepository
+	fuels       repository.FuelRepository
+	maintenance repository.MaintenanceRepository
+}
+
+func NewOdometerService(store *repository.Store) *OdometerService {
+	return &OdometerService{
+		cars:        store.Cars,
+		carEntries:  store.CarEntries,
+		fuels:       store.Fuels,
+		maintenance: store.Maintenance,
+	}
+}
+
+// Timeline monta as leituras do carro em ordem cronológica. As leituras de
+// entradas e manutenções são a referência: cada uma é comparada com a última
+// referência consistente, e os abastecimentos, digitados durante a viagem,
+// apenas são conferidos contra ela.
+func (s *OdometerService) Timeline(ctx context.Context, carID primitive.ObjectID, tolerance float64) (*model.OdometerTimeline, error) {
+	car, err := s.cars.FindByID(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.carEntries.ListByCar(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	fuels, err := s.fuels.ListByCar(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+	completed := false
+	records, err := s.maintenance.ListRecords(ctx, repository.MaintenanceRecordFilter{CarID: &carID, InProgress: &completed})
+	if err != nil {
+		return nil, err
+	}
+
+	readings := []model.OdometerReading{}
+	for _, entry := range entries {
+		userID := entry.UserID
+		readings = append(readings, model.OdometerReading{
+			At:     entry.StartedAt,
+			KM:     entry.CheckIn.ActualKM,
+			Source: model.OdometerSourceCheckIn,
+			RefID:  entry.ID,
+			UserID: &userID,
+		})
+		if entry.CheckOut != nil && entry.EndedAt != nil {
+			readings = append(readings, model.OdometerReading{
+				At:     *entry.EndedAt,
+				KM:     entry.CheckOut.ActualKM,
+				Source: model.OdometerSourceCheckOut,
+				RefID:  entry.ID,
+				UserID: &userID,
+			})
+		}
+	}
+	for _, fuel := range fuels {
+		if fuel.Type == model.FuelTypeRefuel && fuel.Odometer > 0 {
+			readings = append(readings, model.OdometerReading{
+				At:     fuel.CreatedAt,
+				KM:     fuel.Odometer,
+				Source: model.OdometerSourceRefuel,
+				RefID:  fuel.ID,
+				UserID: fuel.DriverID,
+			})
+		}
+	}
+	for _, record := range records {
+		createdBy := record.CreatedBy
+		readings = append(readings, model.OdometerReading{
+			At:     *record.CompletedAt,
+			KM:     record.Odometer,
+			Source: model.OdometerSourceMaintenance,
+			RefID:  record.ID,
+			UserID: &createdBy,
+		})
+	}
+	sort.SliceStable(readings, func(i, j int) bool { return readings[i].At.Before(readings[j].At) })
+
+	timeline := &model.OdometerTimeline{
+		CarID:       car.ID,
+		Plate:       car.Plate,
+		ToleranceKM: tolerance,
+		Readings:    readings,
+		Gaps:        []model.OdometerGap{},
+	}
+
+	var last *model.OdometerReading
+	for i := range readings {
+		reading := &readings[i]
+		if last != nil && reading.KM < last.KM-tolerance {
+			reading.Inconsistent = true
+			continue
+		}
+		if reading.Source == model.OdometerSourceRefuel {
+			continue
+		}
+
+		// Depois de um check-in o carro está em uso; fora disso, qualquer
+		// avanço até a próxima leitura de referência não pertence a uma entrada.
+		if last != nil && last.Source != model.OdometerSourceCheckIn && reading.KM-last.KM > tolerance {
+			timeline.Gaps = append(timeline.Gaps, model.OdometerGap{From: *last, To: *reading, KM: reading.KM - last.KM})
+		}
+		last = reading
+	}
+	if last != nil {
+		timeline.CurrentKM = last.KM
+	}
+	return timeline, nil
+}
+
+// ValidateCheckIn confere a leitura do check-in contra a última leitura de
+// referência do carro. Retorna a quilometragem rodada fora de entradas
+// quando ela excede a tolerância.
+func (s *OdometerService) ValidateCheckIn(ctx context.Context, carID primitive.ObjectID, km float64) (*float64, error) {
+	tolerance := OdometerTolerance()
+	timeline, err := s.Timeline(ctx, carID, tolerance)
+	if err != nil {
+		return nil, err
+	}
+	if timeline.CurrentKM == 0 {
+		return nil, nil
+	}
+
+	if km < timeline.CurrentKM-tolerance {
+		return nil, &OdometerError{Message: fmt.Sprintf("KM inicial inferior à última leitura do carro (%.0f km)", timeline.CurrentKM)}
+	}
+	gap := km - timeline.CurrentKM
+	if gap > OdometerMaxGap() {
+		return nil, &OdometerError{Message: fmt.Sprintf("KM inicial %.0f km acima da última leitura do carro (%.0f km); confira o valor informado", gap, timeline.CurrentKM)}
+	}
+	if gap > tolerance {
+		return &gap, nil
+	}
+	return nil, nil
+}
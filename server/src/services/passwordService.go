@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultPasswordResetMinutes = 60
+	defaultPasswordResetURL     = "https://forms.innova-energy.com.br/reset-password"
+
+	// passwordResetCooldown evita que pedidos repetidos inundem a caixa de
+	// entrada do usuário.
+	passwordResetCooldown = time.Minute
+)
+
+var ErrInvalidPasswordResetToken = errors.New("token de redefinição de senha inválido ou expirado")
+
+// PasswordError indica uma senha recusada; a mensagem pode ser devolvida
+// diretamente ao cliente.
+type PasswordError struct {
+	Message string
+}
+
+func (e *PasswordError) Error() string {
+	return e.Message
+}
+
+func passwordErrorf(format string, args ...interface{}) error {
+	return &PasswordError{Message: fmt.Sprintf(format, args...)}
+}
+
+// PasswordResetDuration lê PASSWORD_RESET_MINUTES, usando o padrão quando a
+// variável está ausente ou inválida.
+func PasswordResetDuration() time.Duration {
+	return minutesFromEnv("PASSWORD_RESET_MINUTES", defaultPasswordResetMinutes)
+}
+
+// passwordResetLink monta o link enviado ao usuário a partir de
+// PASSWORD_RESET_URL, a página do cliente web que recebe o token.
+func passwordResetLink(token string) string {
+	base := os.Getenv("PASSWORD_RESET_URL")
+	if base == "" {
+		base = defaultPasswordResetURL
+	}
+	link, err := url.Parse(base)
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type PasswordService struct {
+	users      repository.UserRepository
+	resets     repository.PasswordResetRepository
+	sessions   *SessionService
+	tokens     *TokenService
+	guard      *LoginGuardService
+	notifier   Notifier
+	transactor repository.Transactor
+}
+
+func NewPasswordService(store *repository.Store, notifier Notifier) *PasswordService {
+	return &PasswordService{
+		users:      store.Users,
+		resets:     store.PasswordResets,
+		sessions:   NewSessionService(store),
+		tokens:     NewTokenService(store),
+		guard:      NewLoginGuardService(store),
+		notifier:   notifier,
+		transactor: store.Transactor,
+	}
+}
+
+func validateNewPassword(password, currentHash string) error {
+	if !helper.ValidPasswordLength(password) {
+		return passwordErrorf("a senha deve ter entre %d e %d caracteres", helper.MinPasswordLength, helper.MaxPasswordLength)
+	}
+	if helper.CheckPassword(password, currentHash) {
+		return passwordErrorf("a nova senha deve ser diferente da atual")
+	}
+	return nil
+}
+
+// Change troca a senha do usuário após conferir a senha atual e encerra as
+// demais sessões, mantendo a sessão current usada na troca. Senhas atuais
+// incorretas contam como falhas de login da conta e do IP de attempt, e a
+// troca é recusada com *LoginThrottledError enquanto eles estiverem
+// bloqueados.
+func (s *PasswordService) Change(ctx context.Context, userID primitive.ObjectID, currentPassword, newPassword string, current *primitive.ObjectID, attempt *model.LoginAttempt) error {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	attempt.Email = user.Email
+	attempt.UserID = &user.ID
+	if err := s.guard.Check(ctx, attempt); err != nil {
+		return err
+	}
+	if !helper.CheckPassword(currentPassword, user.Password) {
+		if err := s.guard.RecordFailure(ctx, attempt); err != nil {
+			return err
+		}
+		return passwordErrorf("senha atual incorreta")
+	}
+	if err := validateNewPassword(newPassword, user.Password); err != nil {
+		return err
+	}
+
+	hash, err := helper.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.users.SetPassword(ctx, userID, hash, false); err != nil {
+		return err
+	}
+	InvalidateUserAccess(userID)
+
+	if err := s.sessions.RevokeOthers(ctx, userID, current, model.RefreshTokenRevokedPasswordChanged); err != nil {
+		return err
+	}
+	return s.guard.Unlock(ctx, user.Email)
+}
+
+// RequestReset envia ao email informado um link de redefinição de senha. Não
+// retorna erro quando o email não pertence a um usuário ativo, para não
+// revelar quais contas existem, e o envio é feito em segundo plano pelo
+// mesmo motivo.
+func (s *PasswordService) RequestReset(ctx context.Context, email, ip string) error {
+	user, err := s.users.FindActiveByEmail(ctx, email)
+	if err == repository.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	latest, err := s.resets.FindLatestByUser(ctx, user.ID)
+	if err != nil && err != repository.ErrNotFound {
+		return err
+	}
+	if latest != nil && now.Sub(latest.CreatedAt) < passwordResetCooldown {
+		return nil
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := s.resets.InvalidateUser(ctx, user.ID, now); err != nil {
+		return err
+	}
+	duration := PasswordResetDuration()
+	reset := model.PasswordReset{
+		ID:        primitive.NewObjectID(),
+		UserID:    user.ID,
+		TokenHash: hashResetToken(token),
+		RequestIP: ip,
+		CreatedAt: now,
+		ExpiresAt: now.Add(duration),
+	}
+	if err := s.resets.Create(ctx, &reset); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Olá, %s.\n\n"+
+		"Recebemos um pedido para redefinir a sua senha. Para criar uma nova senha, acesse o link abaixo em até %d minutos:\n\n"+
+		"%s\n\n"+
+		"Se você não fez esse pedido, ignore esta mensagem; a sua senha continua a mesma.\n",
+		user.Name, int(duration.Minutes()), passwordResetLink(token))
+	go func(to string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := s.notifier.Send(ctx, to, "Redefinição de senha", body); err != nil {
+			log.Println("Erro ao enviar redefinição de senha:", err)
+		}
+	}(user.Email)
+	return nil
+}
+
+// Reset define a nova senha com um token de redefinição válido. O token é
+// consumido na mesma transação que grava a senha; depois, as sessões do
+// usuário são encerradas e o bloqueio de login da conta é removido.
+func (s *PasswordService) Reset(ctx context.Context, token, newPassword string) error {
+	reset, err := s.resets.FindByTokenHash(ctx, hashResetToken(token))
+	if err == repository.ErrNotFound {
+		return ErrInvalidPasswordResetToken
+	}
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if reset.UsedAt != nil || !now.Before(reset.ExpiresAt) {
+		return ErrInvalidPasswordResetToken
+	}
+
+	user, err := s.users.FindByID(ctx, reset.UserID)
+	if err == repository.ErrNotFound {
+		return ErrInvalidPasswordResetToken
+	}
+	if err != nil {
+		return err
+	}
+	if !user.IsActive {
+		return ErrInvalidPasswordResetToken
+	}
+	if err := validateNewPassword(newPassword, user.Password); err != nil {
+		return err
+	}
+
+	hash, err := helper.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	err = s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.resets.MarkUsed(ctx, reset.ID, now); err != nil {
+			return err
+		}
+		return s.users.SetPassword(ctx, user.ID, hash, false)
+	})
+	if err == repository.ErrNotFound {
+		return ErrInvalidPasswordResetToken
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.tokens.RevokeUser(ctx, user.ID, model.RefreshTokenRevokedPasswordReset); err != nil {
+		return err
+	}
+	return s.guard.Unlock(ctx, user.Email)
+}
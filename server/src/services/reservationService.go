@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// defaultReservationNoShowMinutes é a tolerância de atraso após o início
+	// da reserva; passado esse prazo sem check-in, a reserva vira no_show.
+	defaultReservationNoShowMinutes = 30
+	// defaultReservationEarlyMinutes é a antecedência com que o motorista pode
+	// fazer o check-in de uma reserva.
+	defaultReservationEarlyMinutes = 30
+)
+
+var ErrInvalidReservationTransition = errors.New("a reserva não pode passar para este status")
+
+// activeReservationStatuses são os status que ocupam o horário do carro.
+var activeReservationStatuses = []string{model.ReservationPending, model.ReservationApproved, model.ReservationCheckedIn}
+
+// ReservationError indica uma reserva inválida ou um check-in recusado por
+// reserva de outro motorista; a mensagem pode ser devolvida ao cliente.
+type ReservationError struct {
+	Message string
+}
+
+func (e *ReservationError) Error() string {
+	return e.Message
+}
+
+func reservationErrorf(format string, args ...interface{}) error {
+	return &ReservationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// ReservationConflictError indica que o horário pedido se sobrepõe a outras
+// reservas do carro.
+type ReservationConflictError struct {
+	Conflicts []model.Reservation
+}
+
+func (e *ReservationConflictError) Error() string {
+	return "o carro já está reservado neste horário"
+}
+
+func minutesFromEnv(name string, fallback int) time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || minutes < 0 {
+		minutes = fallback
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// ReservationNoShowGrace lê RESERVATION_NO_SHOW_MINUTES, usando o padrão
+// quando a variável está ausente ou inválida.
+func ReservationNoShowGrace() time.Duration {
+	return minutesFromEnv("RESERVATION_NO_SHOW_MINUTES", defaultReservationNoShowMinutes)
+}
+
+// ReservationEarlyCheckIn lê RESERVATION_EARLY_CHECKIN_MINUTES, usando o
+// padrão quando a variável está ausente ou inválida.
+func ReservationEarlyCheckIn() time.Duration {
+	return minutesFromEnv("RESERVATION_EARLY_CHECKIN_MINUTES", defaultReservationEarlyMinutes)
+}
+
+type ReservationService struct {
+	reservations repository.ReservationRepository
+	cars         repository.CarRepository
+	transactor   repository.Transactor
+	licenses     *DriverLicenseService
+}
+
+func NewReservationService(store *repository.Store) *ReservationService {
+	return &ReservationService{
+		reservations: store.Reservations,
+		cars:         store.Cars,
+		transactor:   store.Transactor,
+		licenses:     NewDriverLicenseService(store),
+	}
+}
+
+// MarkNoShows libera os horários das reservas aprovadas cujo motorista não
+// fez check-in dentro da tolerância. É chamado antes de cada consulta, em vez
+// de depender de uma tarefa agendada.
+func (s *ReservationService) MarkNoShows(ctx context.Context) error {
+	now := time.Now()
+	_, err := s.reservations.MarkNoShows(ctx, now.Add(-ReservationNoShowGrace()), now)
+	return err
+}
+
+func (s *ReservationService) List(ctx context.Context, filter repository.ReservationFilter) ([]model.Reservation, error) {
+	if err := s.MarkNoShows(ctx); err != nil {
+		return nil, err
+	}
+	return s.reservations.List(ctx, filter)
+}
+
+// Create registra a reserva como pendente ou, quando feita por um
+// administrador (approvedBy), já aprovada. A verificação de conflito e a
+// gravação acontecem na mesma transação, com o carro travado.
+func (s *ReservationService) Create(ctx context.Context, reservation *model.Reservation, approvedBy *primitive.ObjectID) error {
+	now := time.Now()
+	if !reservation.End.After(reservation.Start) {
+		return reservationErrorf("o fim da reserva deve ser posterior ao início")
+	}
+	if !reservation.End.After(now) {
+		return reservationErrorf("a reserva não pode terminar no passado")
+	}
+
+	car, err := s.cars.FindByID(ctx, reservation.CarID)
+	if err == repository.ErrNotFound {
+		return reservationErrorf("carro não encontrado")
+	}
+	if err != nil {
+		return err
+	}
+	if !car.IsActive {
+		return reservationErrorf("carro desativado")
+	}
+	if err := s.licenses.CheckDriver(ctx, reservation.UserID, reservation.CarID); err != nil {
+		return err
+	}
+	if err := s.MarkNoShows(ctx); err != nil {
+		return err
+	}
+
+	reservation.ID = primitive.NewObjectID()
+	reservation.Status = model.ReservationPending
+	reservation.CarEntryID = nil
+	reservation.DecidedBy = nil
+	reservation.DecidedAt = nil
+	reservation.DecisionNote = ""
+	if approvedBy != nil {
+		reservation.Status = model.ReservationApproved
+		reservation.DecidedBy = approvedBy
+		reservation.DecidedAt = &now
+	}
+	reservation.CreatedAt = now
+	reservation.UpdatedAt = now
+
+	return s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.reservations.LockCar(ctx, reservation.CarID); err != nil {
+			return err
+		}
+
+		conflicts, err := s.reservations.List(ctx, repository.ReservationFilter{
+			CarID:  &reservation.CarID,
+			Status: activeReservationStatuses,
+			From:   &reservation.Start,
+			To:     &reservation.End,
+		})
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return &ReservationConflictError{Conflicts: conflicts}
+		}
+
+		own, err := s.reservations.List(ctx, repository.ReservationFilter{
+			UserID: &reservation.UserID,
+			Status: activeReservationStatuses,
+			From:   &reservation.Start,
+			To:     &reservation.End,
+		})
+		if err != nil {
+			return err
+		}
+		if len(own) > 0 {
+			return reservationErrorf("o motorista já tem outra reserva neste horário")
+		}
+
+		return s.reservations.Create(ctx, reservation)
+	})
+}
+
+func (s *ReservationService) transition(ctx context.Context, id primitive.ObjectID, from []string, change model.ReservationChange) (*model.Reservation, error) {
+	if _, err := s.reservations.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+	reservation, err := s.reservations.Transition(ctx, id, from, change)
+	if err == repository.ErrNotFound {
+		return nil, ErrInvalidReservationTransition
+	}
+	return reservation, err
+}
+
+// Decide aprova ou recusa uma reserva pendente.
+func (s *ReservationService) Decide(ctx context.Context, id primitive.ObjectID, approve bool, note string, by primitive.ObjectID) (*model.Reservation, error) {
+	status := model.ReservationRejected
+	if approve {
+		status = model.ReservationApproved
+	}
+	return s.transition(ctx, id, []string{model.ReservationPending}, model.ReservationChange{
+		Status:    status,
+		DecidedBy: &by,
+		Note:      note,
+		At:        time.Now(),
+	})
+}
+
+// Cancel cancela uma reserva que ainda não foi utilizada.
+func (s *ReservationService) Cancel(ctx context.Context, id primitive.ObjectID, note string, by primitive.ObjectID) (*model.Reservation, error) {
+	return s.transition(ctx, id, []string{model.ReservationPending, model.ReservationApproved}, model.ReservationChange{
+		Status:    model.ReservationCancelled,
+		DecidedBy: &by,
+		Note:      note,
+		At:        time.Now(),
+	})
+}
+
+// ForCheckIn encontra a reserva aprovada do motorista que o check-in atende,
+// considerando a antecedência permitida. Recusa o check-in se o carro estiver
+// reservado para outro motorista nesse horário.
+func (s *ReservationService) ForCheckIn(ctx context.Context, carID, userID primitive.ObjectID) (*model.Reservation, error) {
+	if err := s.MarkNoShows(ctx); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	until := now.Add(ReservationEarlyCheckIn())
+	reservations, err := s.reservations.List(ctx, repository.ReservationFilter{
+		CarID:  &carID,
+		Status: []string{model.ReservationApproved},
+		From:   &now,
+		To:     &until,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var other *model.Reservation
+	for i := range reservations {
+		if reservations[i].UserID == userID {
+			return &reservations[i], nil
+		}
+		if other == nil {
+			other = &reservations[i]
+		}
+	}
+	if other != nil {
+		return nil, reservationErrorf("Carro reservado para outro motorista das %s às %s",
+			other.Start.Local().Format("02/01 15:04"), other.End.Local().Format("02/01 15:04"))
+	}
+	return nil, nil
+}
+
+// LinkCheckIn marca a reserva como em uso pela entrada aberta no check-in.
+func (s *ReservationService) LinkCheckIn(ctx context.Context, id, entryID primitive.ObjectID) error {
+	_, err := s.transition(ctx, id, []string{model.ReservationApproved}, model.ReservationChange{
+		Status:     model.ReservationCheckedIn,
+		CarEntryID: &entryID,
+		At:         time.Now(),
+	})
+	return err
+}
+
+// Complete encerra a reserva no check-out da entrada vinculada.
+func (s *ReservationService) Complete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := s.transition(ctx, id, []string{model.ReservationCheckedIn}, model.ReservationChange{
+		Status: model.ReservationCompleted,
+		At:     time.Now(),
+	})
+	return err
+}
+
+// Slots informa, para cada carro ativo, se ele está livre em [from, to).
+func (s *ReservationService) Slots(ctx context.Context, from, to time.Time) ([]model.CarSlot, error) {
+	cars, err := s.cars.List(ctx, repository.CarFilter{IsActive: true})
+	if err != nil {
+		return nil, err
+	}
+	reservations, err := s.List(ctx, repository.ReservationFilter{Status: activeReservationStatuses, From: &from, To: &to})
+	if err != nil {
+		return nil, err
+	}
+
+	byCar := make(map[primitive.ObjectID][]model.Reservation)
+	for _, reservation := range reservations {
+		byCar[reservation.CarID] = append(byCar[reservation.CarID], reservation)
+	}
+
+	slots := []model.CarSlot{}
+	for _, car := range cars {
+		conflicts := byCar[car.ID]
+		if conflicts == nil {
+			conflicts = []model.Reservation{}
+		}
+		slots = append(slots, model.CarSlot{
+			CarID:     car.ID,
+			Plate:     car.Plate,
+			Model:     car.Model,
+			Free:      len(conflicts) == 0,
+			Conflicts: conflicts,
+		})
+	}
+	return slots, nil
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sessionTouchInterval evita gravar o último acesso a cada requisição.
+const sessionTouchInterval = time.Minute
+
+var ErrSessionRevoked = errors.New("sessão encerrada")
+
+type SessionService struct {
+	sessions      repository.SessionRepository
+	refreshTokens repository.RefreshTokenRepository
+}
+
+func NewSessionService(store *repository.Store) *SessionService {
+	return &SessionService{sessions: store.Sessions, refreshTokens: store.RefreshTokens}
+}
+
+func sessionActive(session model.Session, now time.Time) bool {
+	return session.RevokedAt == nil && session.ExpiresAt.After(now)
+}
+
+// List devolve as sessões do usuário, marcando current. Sem includeInactive,
+// omite as encerradas e as expiradas.
+func (s *SessionService) List(ctx context.Context, userID primitive.ObjectID, current *primitive.ObjectID, includeInactive bool) ([]model.Session, error) {
+	sessions, err := s.sessions.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := []model.Session{}
+	for _, session := range sessions {
+		if !includeInactive && !sessionActive(session, now) {
+			continue
+		}
+		session.Current = current != nil && session.ID == *current
+		result = append(result, session)
+	}
+	return result, nil
+}
+
+// Revoke encerra a sessão e revoga os refresh tokens emitidos para ela.
+func (s *SessionService) Revoke(ctx context.Context, id, by primitive.ObjectID, reason string) (*model.Session, error) {
+	now := time.Now()
+	if err := s.sessions.Revoke(ctx, id, &by, reason, now); err != nil {
+		return nil, err
+	}
+	invalidateSessionAccess(id)
+	if err := s.refreshTokens.RevokeFamily(ctx, id, reason, now); err != nil {
+		return nil, err
+	}
+	return s.sessions.FindByID(ctx, id)
+}
+
+// RevokeOthers encerra as sessões ativas do usuário, exceto keep, e revoga
+// os refresh tokens emitidos para elas.
+func (s *SessionService) RevokeOthers(ctx context.Context, userID primitive.ObjectID, keep *primitive.ObjectID, reason string) error {
+	sessions, err := s.sessions.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, session := range sessions {
+		if !sessionActive(session, now) || (keep != nil && session.ID == *keep) {
+			continue
+		}
+		if err := s.sessions.Revoke(ctx, session.ID, &userID, reason, now); err != nil && err != repository.ErrNotFound {
+			return err
+		}
+		invalidateSessionAccess(session.ID)
+		if err := s.refreshTokens.RevokeFamily(ctx, session.ID, reason, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate confere se a sessão do access token continua ativa e registra o
+// acesso. Retorna ErrSessionRevoked se ela tiver sido encerrada.
+func (s *SessionService) Validate(ctx context.Context, id primitive.ObjectID, ip string) error {
+	session, err := s.sessions.FindByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return ErrSessionRevoked
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if !sessionActive(*session, now) {
+		return ErrSessionRevoked
+	}
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval || session.LastSeenIP != ip {
+		if err := s.sessions.Touch(ctx, id, now, ip, nil); err != nil {
+			log.Println("Erro ao registrar acesso da sessão", id.Hex(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	helper "server/src/helpers"
+	model "server/src/models"
+	repository "server/src/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token inválido")
+	ErrRefreshTokenReused  = errors.New("refresh token reutilizado")
+	ErrInactiveToken       = errors.New("token sem sessão ativa")
+)
+
+// TokenPair é o resultado de um login ou de uma renovação. RefreshToken fica
+// vazio quando o usuário não pediu para manter a conexão.
+type TokenPair struct {
+	AccessToken      string
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+type TokenService struct {
+	users         repository.UserRepository
+	refreshTokens repository.RefreshTokenRepository
+	sessions      repository.SessionRepository
+	transactor    repository.Transactor
+}
+
+func NewTokenService(store *repository.Store) *TokenService {
+	return &TokenService{
+		users:         store.Users,
+		refreshTokens: store.RefreshTokens,
+		sessions:      store.Sessions,
+		transactor:    store.Transactor,
+	}
+}
+
+func (s *TokenService) newRefreshToken(userID, familyID primitive.ObjectID) model.RefreshToken {
+	now := time.Now()
+	return model.RefreshToken{
+		ID:        primitive.NewObjectID(),
+		FamilyID:  familyID,
+		UserID:    userID,
+		ExpiresAt: now.Add(helper.RefreshTokenDuration),
+		CreatedAt: now,
+	}
+}
+
+func (s *TokenService) sign(user *model.User, sessionID primitive.ObjectID, refresh *model.RefreshToken) (*TokenPair, error) {
+	accessToken, err := helper.GenerateAccessToken(user.ID.Hex(), user.Name, user.UserType, sessionID.Hex(), user.TokenVersion)
+	if err != nil {
+		return nil, err
+	}
+	pair := &TokenPair{AccessToken: accessToken}
+	if refresh == nil {
+		return pair, nil
+	}
+
+	pair.RefreshToken, err = helper.GenerateRefreshToken(user.ID.Hex(), refresh.ID.Hex(), refresh.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	pair.RefreshExpiresAt = refresh.ExpiresAt
+	return pair, nil
+}
+
+// Issue abre uma sessão para o login feito no dispositivo informado e emite
+// seus tokens. Com keepLogged, a sessão dura o mesmo que o refresh token e
+// ganha sua própria família de refresh tokens.
+func (s *TokenService) Issue(ctx context.Context, user *model.User, keepLogged bool, device model.DeviceInfo) (*TokenPair, error) {
+	now := time.Now()
+	session := model.Session{
+		ID:             primitive.NewObjectID(),
+		UserID:         user.ID,
+		DeviceInfo:     device,
+		KeepConnection: keepLogged,
+		CreatedAt:      now,
+		LastSeenAt:     now,
+		LastSeenIP:     device.IPAddress,
+		ExpiresAt:      now.Add(helper.AccessTokenDuration),
+	}
+	if !keepLogged {
+		if err := s.sessions.Create(ctx, &session); err != nil {
+			return nil, err
+		}
+		return s.sign(user, session.ID, nil)
+	}
+
+	refresh := s.newRefreshToken(user.ID, session.ID)
+	session.ExpiresAt = refresh.ExpiresAt
+	err := s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.sessions.Create(ctx, &session); err != nil {
+			return err
+		}
+		return s.refreshTokens.Create(ctx, &refresh)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.sign(user, session.ID, &refresh)
+}
+
+// Rotate troca o refresh token por um novo da mesma família e emite um novo
+// access token com os dados atuais do usuário, prorrogando a sessão. Um token
+// já usado revoga a família inteira e retorna ErrRefreshTokenReused.
+func (s *TokenService) Rotate(ctx context.Context, refreshToken, ip string) (*TokenPair, error) {
+	claims, err := helper.ParseToken(refreshToken)
+	if err != nil || claims["TokenType"] != helper.TokenTypeRefresh {
+		return nil, ErrInvalidRefreshToken
+	}
+	jti, _ := claims["jti"].(string)
+	id, err := primitive.ObjectIDFromHex(jti)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	current, err := s.refreshTokens.FindByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if current.RevokedAt != nil || !current.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if current.UsedAt != nil {
+		return nil, s.revokeReused(ctx, current)
+	}
+
+	user, err := s.users.FindByID(ctx, current.UserID)
+	if err != nil && err != repository.ErrNotFound {
+		return nil, err
+	}
+	if err == repository.ErrNotFound || !user.IsActive {
+		if err := s.refreshTokens.RevokeFamily(ctx, current.FamilyID, model.RefreshTokenRevokedUserDisabled, time.Now()); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session, err := s.sessions.FindByID(ctx, current.FamilyID)
+	if err == repository.ErrNotFound {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	next := s.newRefreshToken(current.UserID, current.FamilyID)
+	err = s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.refreshTokens.MarkUsed(ctx, current.ID, next.ID, next.CreatedAt); err != nil {
+			return err
+		}
+		if err := s.refreshTokens.Create(ctx, &next); err != nil {
+			return err
+		}
+		return s.sessions.Touch(ctx, session.ID, next.CreatedAt, ip, &next.ExpiresAt)
+	})
+	if err == repository.ErrNotFound {
+		return nil, s.revokeReused(ctx, current)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.sign(user, session.ID, &next)
+}
+
+// revokeReused encerra a sessão da família do token reutilizado, que pode
+// ter sido roubado, junto com os seus refresh tokens.
+func (s *TokenService) revokeReused(ctx context.Context, token *model.RefreshToken) error {
+	log.Println("Refresh token reutilizado; revogando a família", token.FamilyID.Hex(), "do usuário", token.UserID.Hex())
+	now := time.Now()
+	if err := s.sessions.Revoke(ctx, token.FamilyID, nil, model.RefreshTokenRevokedReuse, now); err != nil && err != repository.ErrNotFound {
+		return err
+	}
+	invalidateSessionAccess(token.FamilyID)
+	if err := s.refreshTokens.RevokeFamily(ctx, token.FamilyID, model.RefreshTokenRevokedReuse, now); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// activeSession devolve a sessão ainda ativa a que o token pertence. Um
+// refresh token só vale se não tiver sido usado nem revogado; qualquer outro
+// caso retorna ErrInactiveToken.
+func (s *TokenService) activeSession(ctx context.Context, token string) (*model.Session, error) {
+	claims, err := helper.ParseToken(token)
+	if err != nil {
+		return nil, ErrInactiveToken
+	}
+
+	now := time.Now()
+	var sessionHex string
+	switch claims["TokenType"] {
+	case helper.TokenTypeRefresh:
+		jti, _ := claims["jti"].(string)
+		id, err := primitive.ObjectIDFromHex(jti)
+		if err != nil {
+			return nil, ErrInactiveToken
+		}
+		refresh, err := s.refreshTokens.FindByID(ctx, id)
+		if err == repository.ErrNotFound {
+			return nil, ErrInactiveToken
+		}
+		if err != nil {
+			return nil, err
+		}
+		if refresh.UsedAt != nil || refresh.RevokedAt != nil || !refresh.ExpiresAt.After(now) {
+			return nil, ErrInactiveToken
+		}
+		sessionHex = refresh.FamilyID.Hex()
+	case helper.TokenTypeAccess:
+		sessionHex, _ = claims["SessionId"].(string)
+	default:
+		return nil, ErrInactiveToken
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(sessionHex)
+	if err != nil {
+		return nil, ErrInactiveToken
+	}
+	session, err := s.sessions.FindByID(ctx, sessionID)
+	if err == repository.ErrNotFound {
+		return nil, ErrInactiveToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if userId, _ := claims["UserId"].(string); userId != session.UserID.Hex() || !sessionActive(*session, now) {
+		return nil, ErrInactiveToken
+	}
+	return session, nil
+}
+
+// Logout encerra a sessão do token informado ou, com everywhere, todas as
+// sessões do usuário. Tokens já usados, revogados ou de sessões encerradas
+// retornam ErrInactiveToken sem encerrar nada.
+func (s *TokenService) Logout(ctx context.Context, token string, everywhere bool) error {
+	session, err := s.activeSession(ctx, token)
+	if err != nil {
+		return err
+	}
+	if everywhere {
+		return s.RevokeUser(ctx, session.UserID, model.RefreshTokenRevokedLogout)
+	}
+
+	now := time.Now()
+	if err := s.sessions.Revoke(ctx, session.ID, &session.UserID, model.RefreshTokenRevokedLogout, now); err != nil && err != repository.ErrNotFound {
+		return err
+	}
+	invalidateSessionAccess(session.ID)
+	return s.refreshTokens.RevokeFamily(ctx, session.ID, model.RefreshTokenRevokedLogout, now)
+}
+
+// RevokeUser encerra todas as sessões do usuário e revoga seus refresh
+// tokens.
+func (s *TokenService) RevokeUser(ctx context.Context, userID primitive.ObjectID, reason string) error {
+	now := time.Now()
+	defer InvalidateUserAccess(userID)
+	if err := s.sessions.RevokeUser(ctx, userID, nil, reason, now); err != nil {
+		return err
+	}
+	return s.refreshTokens.RevokeUser(ctx, userID, reason, now)
+}
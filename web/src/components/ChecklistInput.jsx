@@ -0,0 +1,145 @@
+// src/components/ChecklistInput.jsx
+import React, { useEffect, useState } from "react";
+import { useDispatch, useSelector } from "react-redux";
+import { getChecklistTemplates } from "@/store/slicers/checklistSlicer";
+
+const results = [
+  { value: "pass", label: "OK", className: "bg-green-600" },
+  { value: "fail", label: "Falha", className: "bg-red-600" },
+  { value: "na", label: "N/A", className: "bg-gray-500" },
+];
+
+// ChecklistInput carrega os modelos ativos da etapa que valem para o carro e
+// devolve, via onChange, o checklist no formato esperado pelo servidor (ou
+// null se não houver modelo). As fotos por item são devolvidas em onPhotosChange como
+// { [key]: File }, para envio depois que a entrada for criada.
+const ChecklistInput = ({ stage, carId, onChange, onPhotosChange }) => {
+  const dispatch = useDispatch();
+  const { templates } = useSelector((state) => state.checklist);
+
+  const [templateId, setTemplateId] = useState("");
+  const [answers, setAnswers] = useState({});
+  const [photos, setPhotos] = useState({});
+
+  useEffect(() => {
+    if (carId) {
+      dispatch(getChecklistTemplates(`?stage=${stage}&carId=${carId}`));
+    }
+  }, [dispatch, stage, carId]);
+
+  useEffect(() => {
+    if (!templates.some((t) => t.id === templateId)) {
+      setTemplateId(templates[0]?.id ?? "");
+      setAnswers({});
+      setPhotos({});
+    }
+  }, [templates, templateId]);
+
+  const template = templates.find((t) => t.id === templateId);
+
+  useEffect(() => {
+    if (!template) {
+      onChange(null);
+      return;
+    }
+    onChange({
+      templateID: template.id,
+      templateVersion: template.version,
+      items: template.items.map((item) => ({
+        key: item.key,
+        result: answers[item.key]?.result ?? "",
+        notes: answers[item.key]?.notes ?? "",
+      })),
+    });
+  }, [template, answers]);
+
+  useEffect(() => {
+    onPhotosChange?.(photos);
+  }, [photos]);
+
+  if (!carId || templates.length === 0) {
+    return null;
+  }
+
+  const setAnswer = (key, field, value) =>
+    setAnswers({ ...answers, [key]: { ...answers[key], [field]: value } });
+
+  return (
+    <div className="space-y-4">
+      <div className="flex items-center justify-between gap-2">
+        <label className="block text-sm font-medium">Checklist</label>
+        {templates.length > 1 && (
+          <select
+            value={templateId}
+            className="p-1 border rounded-lg text-sm"
+            onChange={(e) => {
+              setTemplateId(e.target.value);
+              setAnswers({});
+              setPhotos({});
+            }}
+          >
+            {templates.map((t) => (
+              <option key={t.id} value={t.id}>
+                {t.name}
+              </option>
+            ))}
+          </select>
+        )}
+      </div>
+
+      {template?.items.map((item) => {
+        const answer = answers[item.key] ?? {};
+        return (
+          <div key={item.key} className="p-3 border rounded-lg space-y-2">
+            <div className="flex items-center justify-between gap-2">
+              <span className="text-sm font-medium">
+                {item.label}
+                {item.required && <span className="text-red-500"> *</span>}
+              </span>
+              <div className="flex gap-1">
+                {results
+                  .filter((r) => !(item.required && r.value === "na"))
+                  .map((r) => (
+                    <button
+                      key={r.value}
+                      type="button"
+                      onClick={() => setAnswer(item.key, "result", r.value)}
+                      className={`px-2 py-1 text-xs rounded ${
+                        answer.result === r.value
+                          ? `${r.className} text-white`
+                          : "bg-gray-100 text-gray-700"
+                      }`}
+                    >
+                      {r.label}
+                    </button>
+                  ))}
+              </div>
+            </div>
+
+            {answer.result === "fail" && (
+              <>
+                <input
+                  required
+                  placeholder="Descreva o problema"
+                  value={answer.notes ?? ""}
+                  className="w-full p-2 border rounded-lg text-sm"
+                  onChange={(e) => setAnswer(item.key, "notes", e.target.value)}
+                />
+                <input
+                  type="file"
+                  accept="image/*"
+                  className="text-sm"
+                  onChange={(e) =>
+                    setPhotos({ ...photos, [item.key]: e.target.files[0] })
+                  }
+                />
+              </>
+            )}
+          </div>
+        );
+      })}
+    </div>
+  );
+};
+
+export default ChecklistInput;
@@ -24,9 +24,12 @@ const CarForm = ({ open, onClose, carData }) => {
         plate: "",
         model: "",
         brand: "",
+        category: "",
+        requiredLicense: "",
         year: new Date().getFullYear(),
         consumption: 0,
         capacity: 0,
+        lowFuelThreshold: 0,
         isActive: true,
       });
     }
@@ -120,6 +123,36 @@ const CarForm = ({ open, onClose, carData }) => {
                   } shadow-sm focus:border-indigo-500 focus:ring-indigo-500`}
                 />
               </div>
+
+              <div>
+                <label className="block text-sm font-medium text-gray-700">
+                  Categoria
+                </label>
+                <input
+                  placeholder="pickup, sedan, caminhão..."
+                  {...register("category", { maxLength: 30 })}
+                  className={`mt-1 block w-full rounded-md border ${
+                    errors.category ? "border-red-500" : "border-gray-300"
+                  } shadow-sm focus:border-indigo-500 focus:ring-indigo-500`}
+                />
+              </div>
+
+              <div>
+                <label className="block text-sm font-medium text-gray-700">
+                  CNH exigida
+                </label>
+                <select
+                  {...register("requiredLicense")}
+                  className="mt-1 block w-full rounded-md border border-gray-300 shadow-sm focus:border-indigo-500 focus:ring-indigo-500"
+                >
+                  <option value="">Nenhuma</option>
+                  {["A", "B", "C", "D", "E"].map((category) => (
+                    <option key={category} value={category}>
+                      {category}
+                    </option>
+                  ))}
+                </select>
+              </div>
             </div>
 
             <div className="grid grid-cols-2 gap-4">
@@ -187,6 +220,26 @@ const CarForm = ({ open, onClose, carData }) => {
                   } shadow-sm focus:border-indigo-500 focus:ring-indigo-500`}
                 />
               </div>
+              <div>
+                <label className="block text-sm font-medium text-gray-700">
+                  Alerta de combustível baixo (%)
+                </label>
+                <input
+                  type="number"
+                  step="1"
+                  placeholder="Padrão do sistema"
+                  {...register("lowFuelThreshold", {
+                    min: 0,
+                    max: 100,
+                    setValueAs: (value) => (value === "" ? 0 : Number(value)),
+                  })}
+                  className={`mt-1 block w-full rounded-md border ${
+                    errors.lowFuelThreshold
+                      ? "border-red-500"
+                      : "border-gray-300"
+                  } shadow-sm focus:border-indigo-500 focus:ring-indigo-500`}
+                />
+              </div>
             </div>
 
             <div className="flex justify-end gap-3">
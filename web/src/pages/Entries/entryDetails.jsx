@@ -139,6 +139,29 @@ const CarEntryDetails = () => {
               <p className="font-medium">{carEntry.checkIn.nextLocation}</p>
             </div>
 
+            {carEntry.checkIn.purpose && (
+              <div>
+                <p className="text-sm text-gray-500">Finalidade</p>
+                <p className="font-medium">{carEntry.checkIn.purpose}</p>
+              </div>
+            )}
+
+            {carEntry.checkIn.costCenter && (
+              <div>
+                <p className="text-sm text-gray-500">Centro de custo</p>
+                <p className="font-medium">{carEntry.checkIn.costCenter}</p>
+              </div>
+            )}
+
+            {carEntry.checkIn.passengers?.length > 0 && (
+              <div>
+                <p className="text-sm text-gray-500">Passageiros</p>
+                <p className="font-medium">
+                  {carEntry.checkIn.passengers.join(", ")}
+                </p>
+              </div>
+            )}
+
             <div>
               <p className="text-sm text-gray-500">Quilometragem inicial</p>
               <p className="font-medium">{carEntry.checkIn.actualKM}</p>
@@ -1,11 +1,17 @@
 // src/pages/forms/CheckInForm.jsx
 import React, { useState, useEffect } from "react";
 import { useDispatch, useSelector } from "react-redux";
-import { checkIn, postCheckInImages } from "@/store/slicers/carEntrySlicer";
+import {
+  checkIn,
+  postCheckInImages,
+  postChecklistPhoto,
+} from "@/store/slicers/carEntrySlicer";
 import { getCars } from "@/store/slicers/carSlicer";
+import { getCostCenters } from "@/store/slicers/costCenterSlicer";
 import { TruckIcon } from "@heroicons/react/24/outline";
 // import LocationInput from "../../components/LocationInput";
 import ImageUploader from "../../components/ImageUploader";
+import ChecklistInput from "../../components/ChecklistInput";
 import imageCompression from "browser-image-compression";
 
 const compressImage = async (file) => {
@@ -22,26 +28,35 @@ const CheckInForm = () => {
   const { status } = useSelector((state) => state.carEntry);
   const { user } = useSelector((state) => state.auth);
   const { cars } = useSelector((state) => state.car);
+  const { costCenters } = useSelector((state) => state.costCenter);
 
   const [successMessage, setSuccessMessage] = useState("");
   const [errorMessage, setErrorMessage] = useState("");
   const [locationError, setLocationError] = useState(false);
   const [selectedFiles, setSelectedFiles] = useState([]);
+  const [checklist, setChecklist] = useState(null);
+  const [checklistPhotos, setChecklistPhotos] = useState({});
+  const [blocked, setBlocked] = useState(false);
+  const [overrideReason, setOverrideReason] = useState("");
 
   const [formData, setFormData] = useState({
     carID: "",
     checkIn: {
       nextLocation: "",
+      purpose: "",
+      costCenter: "",
       carState: "",
       actualKM: "",
     },
   });
+  const [passengers, setPassengers] = useState("");
   const [location, setLocation] = useState({ latitude: 0, longitude: 0 });
 
   const [uploadError, setUploadError] = useState(null);
 
   useEffect(() => {
     dispatch(getCars("?active=true"));
+    dispatch(getCostCenters("?active=true"));
   }, [dispatch]);
 
   useEffect(() => tryToGetLocation(), []);
@@ -63,7 +78,16 @@ const CheckInForm = () => {
         ...formData.checkIn,
         location: location,
         actualKM: parseFloat(formData.checkIn.actualKM),
+        passengers: passengers
+          .split(",")
+          .map((name) => name.trim())
+          .filter(Boolean),
+        ...(checklist && { checklist }),
       },
+      ...(blocked &&
+        overrideReason && {
+          availabilityOverride: { reason: overrideReason },
+        }),
     };
 
     dispatch(checkIn(payload))
@@ -89,6 +113,30 @@ const CheckInForm = () => {
             });
           }
 
+          const failed = (checklist?.items ?? [])
+            .filter((item) => item.result === "fail")
+            .map((item) => item.key);
+          for (const itemKey of failed) {
+            if (!checklistPhotos[itemKey]) continue;
+            const photoData = new FormData();
+            const photo = await compressImage(checklistPhotos[itemKey]);
+            photoData.append("images", photo, photo.name);
+            dispatch(
+              postChecklistPhoto({
+                entryId: action.id,
+                stage: "checkin",
+                itemKey,
+                data: photoData,
+              })
+            ).then((response) => {
+              if (response.error) {
+                setUploadError("Erro ao fazer upload das imagens");
+              }
+            });
+          }
+
+          setBlocked(false);
+          setOverrideReason("");
           setSuccessMessage("Check-In registrado com sucesso!");
           setFormData({
             carID: "",
@@ -96,13 +144,17 @@ const CheckInForm = () => {
             checkIn: {
               location: { latitude: 0, longitude: 0 },
               nextLocation: "",
+              purpose: "",
+              costCenter: "",
               carState: "",
               actualKM: "",
             },
           });
+          setPassengers("");
         }
       })
       .catch((err) => {
+        setBlocked(Boolean(err.reasons?.length));
         setErrorMessage(err.error || "Erro ao registrar o Check-In");
       });
   };
@@ -159,6 +211,21 @@ const CheckInForm = () => {
       )}
 
       <form onSubmit={handleSubmit} className="space-y-6">
+        {blocked && user.userType === "ADMIN" && (
+          <div className="p-3 border border-yellow-400 bg-yellow-50 rounded-lg">
+            <label className="block text-sm font-medium mb-2">
+              Justificativa para liberar o check-in
+            </label>
+            <textarea
+              required
+              value={overrideReason}
+              className="w-full p-2 border rounded-lg"
+              onChange={(e) => setOverrideReason(e.target.value)}
+              rows="2"
+            />
+          </div>
+        )}
+
         <div className="space-y-4">
           <div>
             <label className="block text-sm font-medium mb-2">Veículo</label>
@@ -215,10 +282,59 @@ const CheckInForm = () => {
 
           <div>
             <label className="block text-sm font-medium mb-2">
-              Estado do Veículo
+              Finalidade da viagem
+            </label>
+            <input
+              className="w-full p-2 border rounded-lg"
+              value={formData.checkIn.purpose}
+              onChange={(e) =>
+                setFormData({
+                  ...formData,
+                  checkIn: { ...formData.checkIn, purpose: e.target.value },
+                })
+              }
+            />
+          </div>
+
+          <div>
+            <label className="block text-sm font-medium mb-2">
+              Centro de custo
+            </label>
+            <select
+              className="w-full p-2 border rounded-lg"
+              value={formData.checkIn.costCenter}
+              onChange={(e) =>
+                setFormData({
+                  ...formData,
+                  checkIn: { ...formData.checkIn, costCenter: e.target.value },
+                })
+              }
+            >
+              <option value="">Nenhum</option>
+              {costCenters.map((costCenter) => (
+                <option key={costCenter.id} value={costCenter.code}>
+                  {costCenter.code} - {costCenter.name}
+                </option>
+              ))}
+            </select>
+          </div>
+
+          <div>
+            <label className="block text-sm font-medium mb-2">
+              Passageiros (separados por vírgula)
+            </label>
+            <input
+              className="w-full p-2 border rounded-lg"
+              value={passengers}
+              onChange={(e) => setPassengers(e.target.value)}
+            />
+          </div>
+
+          <div>
+            <label className="block text-sm font-medium mb-2">
+              Observações
             </label>
             <textarea
-              required
               className="w-full p-2 border rounded-lg"
               onChange={(e) =>
                 setFormData({
@@ -247,6 +363,13 @@ const CheckInForm = () => {
           </div>
         </div>
 
+        <ChecklistInput
+          stage="checkin"
+          carId={formData.carID}
+          onChange={setChecklist}
+          onPhotosChange={setChecklistPhotos}
+        />
+
         <div className="mt-4">
           <label className="block text-sm font-medium mb-2">
             Anexar Imagens (Máximo 5)
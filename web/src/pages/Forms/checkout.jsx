@@ -1,10 +1,15 @@
 // src/pages/forms/CheckOutForm.jsx
 import React, { useState, useEffect } from "react";
 import { useDispatch, useSelector } from "react-redux";
-import { checkOut, postCheckOutImages } from "@/store/slicers/carEntrySlicer";
+import {
+  checkOut,
+  postCheckOutImages,
+  postChecklistPhoto,
+} from "@/store/slicers/carEntrySlicer";
 import { getCars } from "@/store/slicers/carSlicer";
 // import { LocationInput } from "@/components/LocationInput";
 import ImageUploader from "../../components/ImageUploader";
+import ChecklistInput from "../../components/ChecklistInput";
 import imageCompression from "browser-image-compression";
 
 const compressImage = async (file) => {
@@ -26,6 +31,8 @@ const CheckOutForm = () => {
   const [errorMessage, setErrorMessage] = useState("");
   const [locationError, setLocationError] = useState(false);
   const [selectedFiles, setSelectedFiles] = useState([]);
+  const [checklist, setChecklist] = useState(null);
+  const [checklistPhotos, setChecklistPhotos] = useState({});
 
   const [formData, setFormData] = useState({
     carID: "",
@@ -61,6 +68,7 @@ const CheckOutForm = () => {
         ...formData.checkOut,
         location: location,
         actualKM: parseFloat(formData.checkOut.actualKM),
+        ...(checklist && { checklist }),
       },
     };
 
@@ -88,6 +96,28 @@ const CheckOutForm = () => {
             });
           }
 
+          const failed = (checklist?.items ?? [])
+            .filter((item) => item.result === "fail")
+            .map((item) => item.key);
+          for (const itemKey of failed) {
+            if (!checklistPhotos[itemKey]) continue;
+            const photoData = new FormData();
+            const photo = await compressImage(checklistPhotos[itemKey]);
+            photoData.append("images", photo, photo.name);
+            dispatch(
+              postChecklistPhoto({
+                entryId: action.id,
+                stage: "checkout",
+                itemKey,
+                data: photoData,
+              })
+            ).then((response) => {
+              if (response.error) {
+                setUploadError("Erro ao enviar as imagens.");
+              }
+            });
+          }
+
           setSuccessMessage("Check-Out registrado com sucesso!");
           setFormData({
             carID: "",
@@ -208,10 +238,9 @@ const CheckOutForm = () => {
         <div className="space-y-4">
           <div>
             <label className="block text-sm font-medium mb-2">
-              Estado do Veículo
+              Observações
             </label>
             <textarea
-              required
               className="w-full p-2 border rounded-lg"
               onChange={(e) =>
                 setFormData({
@@ -241,6 +270,13 @@ const CheckOutForm = () => {
           </div>
         </div>
 
+        <ChecklistInput
+          stage="checkout"
+          carId={formData.carID}
+          onChange={setChecklist}
+          onPhotosChange={setChecklistPhotos}
+        />
+
         <div className="mt-4">
           <label className="block text-sm font-medium mb-2">
             Anexar Imagens (Máximo 5)
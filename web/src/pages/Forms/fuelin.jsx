@@ -1,8 +1,30 @@
 // src/pages/forms/FuelForm.jsx
 import React, { useState, useEffect } from "react";
 import { useDispatch, useSelector } from "react-redux";
-import { fuelIn } from "@/store/slicers/carEntrySlicer";
+import { fuelIn, postFuelReceipt } from "@/store/slicers/carEntrySlicer";
 import { getCars } from "@/store/slicers/carSlicer";
+import ImageUploader from "../../components/ImageUploader";
+import imageCompression from "browser-image-compression";
+
+const compressImage = async (file) => {
+  const options = {
+    maxSizeMB: 0.1,
+    maxWidthOrHeight: 1920,
+    useWebWorker: true,
+  };
+  return await imageCompression(file, options);
+};
+
+const emptyForm = {
+  carID: "",
+  liters: "",
+  pricePerLiter: "",
+  totalCost: "",
+  fuelKind: "",
+  station: "",
+  odometer: "",
+  fullTank: false,
+};
 
 const FuelForm = () => {
   const dispatch = useDispatch();
@@ -11,37 +33,63 @@ const FuelForm = () => {
 
   const [successMessage, setSuccessMessage] = useState("");
   const [errorMessage, setErrorMessage] = useState("");
+  const [uploadError, setUploadError] = useState(null);
+  const [selectedFiles, setSelectedFiles] = useState([]);
 
-  const [formData, setFormData] = useState({
-    carID: "",
-    fuelAdded: "",
-  });
+  const [formData, setFormData] = useState(emptyForm);
 
   useEffect(() => {
     dispatch(getCars("?active=true"));
   }, [dispatch]);
 
+  const selectedCar = cars.find((car) => car.id === formData.carID);
+
   const handleSubmit = (e) => {
     e.preventDefault();
+    setSuccessMessage("");
+    setErrorMessage("");
+    setUploadError(null);
+
     const payload = {
-      ...formData,
-      fuelAdded: parseFloat(formData.fuelAdded),
+      carId: formData.carID,
+      liters: parseFloat(formData.liters),
+      pricePerLiter: parseFloat(formData.pricePerLiter),
+      totalCost: formData.totalCost ? parseFloat(formData.totalCost) : 0,
+      fuelKind: formData.fuelKind,
+      station: formData.station,
+      odometer: parseFloat(formData.odometer),
+      fullTank: formData.fullTank,
     };
 
     dispatch(fuelIn(payload))
       .unwrap()
-      .then((response) => {
+      .then(async (response) => {
+        const fuelId = response?.fuelRecord?.id;
+        if (fuelId && selectedFiles.length > 0) {
+          const formDataImages = new FormData();
+          const receipt = await compressImage(selectedFiles[0]);
+          formDataImages.append("images", receipt, receipt.name);
+
+          dispatch(postFuelReceipt({ fuelId, data: formDataImages })).then(
+            (response) => {
+              if (response.error) {
+                setUploadError("Erro ao fazer upload do comprovante");
+              }
+            }
+          );
+        }
+
         setSuccessMessage("Abastecimento registrado com sucesso!");
-        setFormData({
-          carID: "",
-          fuelAdded: "",
-        });
+        setFormData(emptyForm);
       })
       .catch((err) => {
         setErrorMessage(err.error || "Erro ao registrar o abastecimento.");
       });
   };
 
+  const handleChange = (field) => (e) =>
+    setFormData({ ...formData, [field]: e.target.value });
+
   return (
     <div className="max-w-md mx-auto p-4">
       <h1 className="text-2xl font-bold mb-6">Registro de Abastecimento</h1>
@@ -56,16 +104,20 @@ const FuelForm = () => {
           {errorMessage}
         </div>
       )}
+      {uploadError && (
+        <div className="mb-4 p-3 bg-red-100 text-red-700 rounded">
+          {uploadError}
+        </div>
+      )}
 
       <form onSubmit={handleSubmit} className="space-y-6">
         <div>
           <label className="block text-sm font-medium mb-2">Veículo</label>
           <select
             required
+            value={formData.carID}
             className="w-full p-2 border rounded-lg"
-            onChange={(e) =>
-              setFormData({ ...formData, carID: e.target.value })
-            }
+            onChange={handleChange("carID")}
           >
             <option value="">Selecione o veículo</option>
             {cars.map((car) => (
@@ -76,19 +128,118 @@ const FuelForm = () => {
           </select>
         </div>
 
+        <div>
+          <label className="block text-sm font-medium mb-2">Combustível</label>
+          <select
+            required
+            value={formData.fuelKind}
+            className="w-full p-2 border rounded-lg"
+            onChange={handleChange("fuelKind")}
+          >
+            <option value="">Selecione o combustível</option>
+            <option value="gasolina">Gasolina</option>
+            <option value="etanol">Etanol</option>
+            <option value="diesel">Diesel</option>
+            <option value="gnv">GNV</option>
+          </select>
+        </div>
+
         <div>
           <label className="block text-sm font-medium mb-2">
             Litros Abastecidos
           </label>
+          <input
+            required
+            type="number"
+            step="0.01"
+            min="0.01"
+            max={selectedCar?.capacity}
+            value={formData.liters}
+            className="w-full p-2 border rounded-lg"
+            onChange={handleChange("liters")}
+          />
+        </div>
+
+        <div className="grid grid-cols-2 gap-4">
+          <div>
+            <label className="block text-sm font-medium mb-2">
+              Preço por Litro (R$)
+            </label>
+            <input
+              required
+              type="number"
+              step="0.001"
+              min="0.001"
+              value={formData.pricePerLiter}
+              className="w-full p-2 border rounded-lg"
+              onChange={handleChange("pricePerLiter")}
+            />
+          </div>
+          <div>
+            <label className="block text-sm font-medium mb-2">
+              Valor Total (R$)
+            </label>
+            <input
+              type="number"
+              step="0.01"
+              min="0"
+              value={formData.totalCost}
+              placeholder={
+                formData.liters && formData.pricePerLiter
+                  ? (
+                      parseFloat(formData.liters) *
+                      parseFloat(formData.pricePerLiter)
+                    ).toFixed(2)
+                  : ""
+              }
+              className="w-full p-2 border rounded-lg"
+              onChange={handleChange("totalCost")}
+            />
+          </div>
+        </div>
+
+        <div>
+          <label className="block text-sm font-medium mb-2">Posto</label>
+          <input
+            required
+            type="text"
+            value={formData.station}
+            className="w-full p-2 border rounded-lg"
+            onChange={handleChange("station")}
+          />
+        </div>
+
+        <div>
+          <label className="block text-sm font-medium mb-2">
+            Quilometragem Atual
+          </label>
           <input
             required
             type="number"
             step="0.1"
+            min="0"
+            value={formData.odometer}
             className="w-full p-2 border rounded-lg"
+            onChange={handleChange("odometer")}
+          />
+        </div>
+
+        <label className="flex items-center gap-2 text-sm font-medium">
+          <input
+            type="checkbox"
+            checked={formData.fullTank}
             onChange={(e) =>
-              setFormData({ ...formData, fuelAdded: e.target.value })
+              setFormData({ ...formData, fullTank: e.target.checked })
             }
           />
+          Tanque completado
+        </label>
+
+        <div>
+          <label className="block text-sm font-medium mb-2">
+            Comprovante (opcional)
+          </label>
+          <ImageUploader onFilesChange={setSelectedFiles} />
         </div>
 
         <div className="border-t pt-4">
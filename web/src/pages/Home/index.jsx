@@ -88,9 +88,9 @@ const Home = () => {
       100
     ).toFixed(0);
 
-    const status = car.checkout === null ? "Em Uso" : "Parado";
+    const status = car.inUse ? "Em Uso" : "Parado";
     const statusColor =
-      car.checkout === null
+      car.inUse
         ? "bg-yellow-100 text-yellow-800"
         : "bg-green-100 text-green-800";
 
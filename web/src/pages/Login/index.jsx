@@ -1,16 +1,17 @@
 import React, { useState } from "react";
 import { useDispatch, useSelector } from "react-redux";
 import { login } from "@/store/slicers/authSlicer";
-import { useNavigate } from "react-router-dom";
+import { Link, useNavigate } from "react-router-dom";
 
 const Login = () => {
   const dispatch = useDispatch();
-  const { user, status } = useSelector((state) => state.auth);
+  const { user, status, error } = useSelector((state) => state.auth);
   const [formData, setFormData] = useState({
     email: "",
     password: "",
     keepConnection: false,
   });
+  const [submitted, setSubmitted] = useState(false);
 
   const navigate = useNavigate();
 
@@ -24,11 +25,16 @@ const Login = () => {
 
   const handleSubmit = async (e) => {
     e.preventDefault();
+    setSubmitted(true);
     dispatch(login(formData));
   };
 
   if (user) {
-    navigate(-1, { replace: true });
+    if (user.mustChangePassword) {
+      navigate("/change-password", { replace: true });
+    } else {
+      navigate(-1, { replace: true });
+    }
   }
 
   return (
@@ -83,8 +89,18 @@ const Login = () => {
             >
               Manter login
             </label>
+            <Link
+              to="/forgot-password"
+              className="ml-auto text-sm text-indigo-600 hover:text-indigo-500"
+            >
+              Esqueci minha senha
+            </Link>
           </div>
 
+          {submitted && status === "failed" && error?.error && (
+            <p className="text-sm text-red-600">{error.error}</p>
+          )}
+
           <div>
             <button
               type="submit"
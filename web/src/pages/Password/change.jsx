@@ -0,0 +1,106 @@
+import React, { useState } from "react";
+import { useDispatch, useSelector } from "react-redux";
+import { useNavigate } from "react-router-dom";
+import { changePassword } from "@/store/slicers/authSlicer";
+
+const ChangePassword = () => {
+  const dispatch = useDispatch();
+  const navigate = useNavigate();
+  const user = useSelector((state) => state.auth.user);
+  const [formData, setFormData] = useState({
+    currentPassword: "",
+    newPassword: "",
+    confirmPassword: "",
+  });
+  const [error, setError] = useState("");
+  const [loading, setLoading] = useState(false);
+
+  const handleChange = (e) => {
+    const { name, value } = e.target;
+    setFormData((prevData) => ({ ...prevData, [name]: value }));
+  };
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    if (formData.newPassword !== formData.confirmPassword) {
+      setError("As senhas não conferem");
+      return;
+    }
+
+    setError("");
+    setLoading(true);
+    try {
+      await dispatch(
+        changePassword({
+          currentPassword: formData.currentPassword,
+          newPassword: formData.newPassword,
+        })
+      ).unwrap();
+      navigate(user?.userType === "ADMIN" ? "/" : "/forms/check-in", {
+        replace: true,
+      });
+    } catch (err) {
+      setError(err?.error || "Erro ao alterar a senha");
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-gray-100">
+      <div className="w-full max-w-md p-8 space-y-6 bg-white shadow rounded">
+        <h2 className="text-center text-2xl font-bold text-gray-900">
+          Alterar senha
+        </h2>
+        {user?.mustChangePassword && (
+          <p className="text-center text-sm text-gray-600">
+            Defina uma nova senha para substituir a senha provisória.
+          </p>
+        )}
+        <form className="space-y-4" onSubmit={handleSubmit}>
+          <input
+            name="currentPassword"
+            type="password"
+            required
+            value={formData.currentPassword}
+            onChange={handleChange}
+            className="block w-full px-3 py-2 border border-gray-300 placeholder-gray-500 text-gray-900 rounded-md focus:outline-none focus:ring-indigo-600 focus:border-indigo-500 sm:text-sm"
+            placeholder="Senha atual"
+          />
+          <input
+            name="newPassword"
+            type="password"
+            required
+            minLength={5}
+            maxLength={60}
+            value={formData.newPassword}
+            onChange={handleChange}
+            className="block w-full px-3 py-2 border border-gray-300 placeholder-gray-500 text-gray-900 rounded-md focus:outline-none focus:ring-indigo-600 focus:border-indigo-500 sm:text-sm"
+            placeholder="Nova senha"
+          />
+          <input
+            name="confirmPassword"
+            type="password"
+            required
+            value={formData.confirmPassword}
+            onChange={handleChange}
+            className="block w-full px-3 py-2 border border-gray-300 placeholder-gray-500 text-gray-900 rounded-md focus:outline-none focus:ring-indigo-600 focus:border-indigo-500 sm:text-sm"
+            placeholder="Confirme a nova senha"
+          />
+
+          {error && <p className="text-sm text-red-600">{error}</p>}
+
+          <button
+            type="submit"
+            disabled={loading}
+            className="w-full flex justify-center py-2 px-4 border border-transparent text-sm font-medium rounded-md text-white bg-indigo-600 hover:bg-indigo-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-indigo-600"
+          >
+            {loading ? "Salvando..." : "Alterar senha"}
+          </button>
+        </form>
+      </div>
+    </div>
+  );
+};
+
+export default ChangePassword;
@@ -0,0 +1,69 @@
+import React, { useState } from "react";
+import { useDispatch } from "react-redux";
+import { Link } from "react-router-dom";
+import { forgotPassword } from "@/store/slicers/authSlicer";
+
+const ForgotPassword = () => {
+  const dispatch = useDispatch();
+  const [email, setEmail] = useState("");
+  const [message, setMessage] = useState("");
+  const [error, setError] = useState("");
+  const [loading, setLoading] = useState(false);
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    setError("");
+    setLoading(true);
+    try {
+      const result = await dispatch(forgotPassword(email)).unwrap();
+      setMessage(result.message);
+    } catch (err) {
+      setError(err?.error || "Erro ao solicitar a redefinição de senha");
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-gray-100">
+      <div className="w-full max-w-md p-8 space-y-6 bg-white shadow rounded">
+        <h2 className="text-center text-2xl font-bold text-gray-900">
+          Esqueci minha senha
+        </h2>
+        {message ? (
+          <p className="text-center text-sm text-gray-700">{message}</p>
+        ) : (
+          <form className="space-y-4" onSubmit={handleSubmit}>
+            <input
+              name="email"
+              type="email"
+              required
+              value={email}
+              onChange={(e) => setEmail(e.target.value)}
+              className="block w-full px-3 py-2 border border-gray-300 placeholder-gray-500 text-gray-900 rounded-md focus:outline-none focus:ring-indigo-600 focus:border-indigo-500 sm:text-sm"
+              placeholder="Email address"
+            />
+
+            {error && <p className="text-sm text-red-600">{error}</p>}
+
+            <button
+              type="submit"
+              disabled={loading}
+              className="w-full flex justify-center py-2 px-4 border border-transparent text-sm font-medium rounded-md text-white bg-indigo-600 hover:bg-indigo-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-indigo-600"
+            >
+              {loading ? "Enviando..." : "Enviar link"}
+            </button>
+          </form>
+        )}
+        <Link
+          to="/login"
+          className="block text-center text-sm text-indigo-600 hover:text-indigo-500"
+        >
+          Voltar para o login
+        </Link>
+      </div>
+    </div>
+  );
+};
+
+export default ForgotPassword;
@@ -0,0 +1,103 @@
+import React, { useState } from "react";
+import { useDispatch } from "react-redux";
+import { Link, useSearchParams } from "react-router-dom";
+import { resetPassword } from "@/store/slicers/authSlicer";
+
+const ResetPassword = () => {
+  const dispatch = useDispatch();
+  const [searchParams] = useSearchParams();
+  const token = searchParams.get("token") || "";
+  const [formData, setFormData] = useState({
+    newPassword: "",
+    confirmPassword: "",
+  });
+  const [done, setDone] = useState(false);
+  const [error, setError] = useState("");
+  const [loading, setLoading] = useState(false);
+
+  const handleChange = (e) => {
+    const { name, value } = e.target;
+    setFormData((prevData) => ({ ...prevData, [name]: value }));
+  };
+
+  const handleSubmit = async (e) => {
+    e.preventDefault();
+    if (formData.newPassword !== formData.confirmPassword) {
+      setError("As senhas não conferem");
+      return;
+    }
+
+    setError("");
+    setLoading(true);
+    try {
+      await dispatch(
+        resetPassword({ token, newPassword: formData.newPassword })
+      ).unwrap();
+      setDone(true);
+    } catch (err) {
+      setError(err?.error || "Erro ao redefinir a senha");
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="min-h-screen flex items-center justify-center bg-gray-100">
+      <div className="w-full max-w-md p-8 space-y-6 bg-white shadow rounded">
+        <h2 className="text-center text-2xl font-bold text-gray-900">
+          Redefinir senha
+        </h2>
+        {done ? (
+          <p className="text-center text-sm text-gray-700">
+            Senha redefinida com sucesso. Entre com a nova senha.
+          </p>
+        ) : !token ? (
+          <p className="text-center text-sm text-red-600">
+            Link de redefinição inválido.
+          </p>
+        ) : (
+          <form className="space-y-4" onSubmit={handleSubmit}>
+            <input
+              name="newPassword"
+              type="password"
+              required
+              minLength={5}
+              maxLength={60}
+              value={formData.newPassword}
+              onChange={handleChange}
+              className="block w-full px-3 py-2 border border-gray-300 placeholder-gray-500 text-gray-900 rounded-md focus:outline-none focus:ring-indigo-600 focus:border-indigo-500 sm:text-sm"
+              placeholder="Nova senha"
+            />
+            <input
+              name="confirmPassword"
+              type="password"
+              required
+              value={formData.confirmPassword}
+              onChange={handleChange}
+              className="block w-full px-3 py-2 border border-gray-300 placeholder-gray-500 text-gray-900 rounded-md focus:outline-none focus:ring-indigo-600 focus:border-indigo-500 sm:text-sm"
+              placeholder="Confirme a nova senha"
+            />
+
+            {error && <p className="text-sm text-red-600">{error}</p>}
+
+            <button
+              type="submit"
+              disabled={loading}
+              className="w-full flex justify-center py-2 px-4 border border-transparent text-sm font-medium rounded-md text-white bg-indigo-600 hover:bg-indigo-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-indigo-600"
+            >
+              {loading ? "Salvando..." : "Redefinir senha"}
+            </button>
+          </form>
+        )}
+        <Link
+          to="/login"
+          className="block text-center text-sm text-indigo-600 hover:text-indigo-500"
+        >
+          Voltar para o login
+        </Link>
+      </div>
+    </div>
+  );
+};
+
+export default ResetPassword;
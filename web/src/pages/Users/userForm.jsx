@@ -22,12 +22,21 @@ const UserForm = ({ open, onClose, userData }) => {
 
   useEffect(() => {
     if (userData) {
-      reset({ ...userData, password: "" });
+      reset({
+        ...userData,
+        password: "",
+        cnhCategories: userData.cnhCategories || [],
+        cnhExpiresAt: userData.cnhExpiresAt
+          ? userData.cnhExpiresAt.slice(0, 10)
+          : "",
+      });
     } else {
       reset({
         name: "",
         email: "",
         cnh: "",
+        cnhCategories: [],
+        cnhExpiresAt: "",
         password: "",
         userType: "USER",
         isActive: true,
@@ -35,7 +44,14 @@ const UserForm = ({ open, onClose, userData }) => {
     }
   }, [userData, reset]);
 
-  const onSubmit = (data) => {
+  const onSubmit = (formData) => {
+    const data = {
+      ...formData,
+      cnhCategories: [].concat(formData.cnhCategories || []),
+      cnhExpiresAt: formData.cnhExpiresAt
+        ? new Date(`${formData.cnhExpiresAt}T00:00:00`).toISOString()
+        : null,
+    };
     const payload = userData ? { id: userData.id, ...data } : data;
 
     if (userData) {
@@ -114,6 +130,41 @@ const UserForm = ({ open, onClose, userData }) => {
               )}
             </div>
 
+            <div className="grid grid-cols-2 gap-4">
+              <div>
+                <label className="block text-sm font-medium text-gray-700">
+                  Categorias da CNH
+                </label>
+                <div className="mt-2 flex gap-3">
+                  {["A", "B", "C", "D", "E"].map((category) => (
+                    <label
+                      key={category}
+                      className="flex items-center gap-1 text-sm text-gray-700"
+                    >
+                      <input
+                        type="checkbox"
+                        value={category}
+                        {...register("cnhCategories")}
+                        className="h-4 w-4 text-indigo-600 rounded border-gray-300"
+                      />
+                      {category}
+                    </label>
+                  ))}
+                </div>
+              </div>
+
+              <div>
+                <label className="block text-sm font-medium text-gray-700">
+                  Validade da CNH
+                </label>
+                <input
+                  type="date"
+                  {...register("cnhExpiresAt")}
+                  className="mt-1 block w-full rounded-md border border-gray-300 shadow-sm focus:border-indigo-500 focus:ring-indigo-500"
+                />
+              </div>
+            </div>
+
             <div className="grid grid-cols-2 gap-4">
               <div>
                 <label className="block text-sm font-medium text-gray-700">
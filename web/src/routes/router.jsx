@@ -1,5 +1,11 @@
 import React, { useState, useEffect } from "react";
-import { BrowserRouter, Routes, Route, Navigate } from "react-router-dom";
+import {
+  BrowserRouter,
+  Routes,
+  Route,
+  Navigate,
+  useLocation,
+} from "react-router-dom";
 import { getCurrentUser } from "@/store/slicers/authSlicer";
 import { useSelector, useDispatch } from "react-redux";
 import Home from "../pages/Home";
@@ -14,10 +20,14 @@ import CarEntryDetails from "../pages/Entries/entryDetails";
 import CheckInForm from "../pages/Forms/checkin";
 import CheckOutForm from "../pages/Forms/checkout";
 import FuelForm from "../pages/Forms/fuelin";
+import ChangePassword from "../pages/Password/change";
+import ForgotPassword from "../pages/Password/forgot";
+import ResetPassword from "../pages/Password/reset";
 
 const ProtectedRoute = ({ children }) => {
   const dispatch = useDispatch();
   const user = useSelector((state) => state.auth.user);
+  const location = useLocation();
   const [loading, setLoading] = useState(true);
 
   useEffect(() => {
@@ -40,6 +50,8 @@ const ProtectedRoute = ({ children }) => {
 
   if (loading) return <div>Carregando...</div>;
   if (!user) return <Navigate to="/login" />;
+  if (user.mustChangePassword && location.pathname !== "/change-password")
+    return <Navigate to="/change-password" replace />;
 
   return children;
 };
@@ -47,6 +59,7 @@ const ProtectedRoute = ({ children }) => {
 const AdminRoute = ({ children }) => {
   const dispatch = useDispatch();
   const user = useSelector((state) => state.auth.user);
+  const location = useLocation();
   const [loading, setLoading] = useState(true);
 
   useEffect(() => {
@@ -68,6 +81,8 @@ const AdminRoute = ({ children }) => {
 
   if (loading) return <div>Carregando...</div>;
   if (!user) return <Navigate to="/login" />;
+  if (user.mustChangePassword && location.pathname !== "/change-password")
+    return <Navigate to="/change-password" replace />;
   if (user.userType !== "ADMIN")
     return <Navigate to="/forms/check-in" replace />;
 
@@ -79,6 +94,16 @@ const Router = () => {
     <BrowserRouter>
       <Routes>
         <Route path="/login" element={<Login />} />
+        <Route path="/forgot-password" element={<ForgotPassword />} />
+        <Route path="/reset-password" element={<ResetPassword />} />
+        <Route
+          path="/change-password"
+          element={
+            <ProtectedRoute>
+              <ChangePassword />
+            </ProtectedRoute>
+          }
+        />
 
         <Route
           path="/"
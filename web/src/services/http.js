@@ -17,7 +17,13 @@ formsApi.interceptors.response.use(
   (response) => response,
   async (error) => {
     const originalRequest = error.config;
-    if (error.response.status === 401 && !originalRequest._retry) {
+    // Falhas de login e do próprio refresh não devem disparar outro refresh.
+    const isAuthRequest = originalRequest.url?.startsWith("/auth/");
+    if (
+      error.response.status === 401 &&
+      !originalRequest._retry &&
+      !isAuthRequest
+    ) {
       originalRequest._retry = true;
 
       try {
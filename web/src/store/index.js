@@ -4,6 +4,8 @@ import userReducer from "./slicers/userSlicer";
 import carReducer from "./slicers/carSlicer";
 import carEntryReducer from "./slicers/carEntrySlicer";
 import formsReducer from "./slicers/formsSlicer";
+import checklistReducer from "./slicers/checklistSlicer";
+import costCenterReducer from "./slicers/costCenterSlicer";
 
 const store = configureStore({
   reducer: {
@@ -12,6 +14,8 @@ const store = configureStore({
     car: carReducer,
     carEntry: carEntryReducer,
     forms: formsReducer,
+    checklist: checklistReducer,
+    costCenter: costCenterReducer,
   },
 });
 
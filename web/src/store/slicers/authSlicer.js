@@ -46,6 +46,42 @@ export const getCurrentUser = createAsyncThunk(
   }
 );
 
+export const changePassword = createAsyncThunk(
+  "auth/changePassword",
+  async (passwords, thunkAPI) => {
+    try {
+      const response = await formsApi.post("/user/change-password", passwords);
+      return response.data;
+    } catch (error) {
+      return thunkAPI.rejectWithValue(error.response.data);
+    }
+  }
+);
+
+export const forgotPassword = createAsyncThunk(
+  "auth/forgotPassword",
+  async (email, thunkAPI) => {
+    try {
+      const response = await formsApi.post("/auth/forgot-password", { email });
+      return response.data;
+    } catch (error) {
+      return thunkAPI.rejectWithValue(error.response.data);
+    }
+  }
+);
+
+export const resetPassword = createAsyncThunk(
+  "auth/resetPassword",
+  async (data, thunkAPI) => {
+    try {
+      const response = await formsApi.post("/auth/reset-password", data);
+      return response.data;
+    } catch (error) {
+      return thunkAPI.rejectWithValue(error.response.data);
+    }
+  }
+);
+
 const authSlice = createSlice({
   name: "auth",
   initialState: {
@@ -105,6 +141,12 @@ const authSlice = createSlice({
         state.status = "failed";
         state.error = action.payload;
       });
+
+    builder.addCase(changePassword.fulfilled, (state) => {
+      if (state.user) {
+        state.user.mustChangePassword = false;
+      }
+    });
   },
 });
 
@@ -13,6 +13,46 @@ export const fuelIn = createAsyncThunk(
   }
 );
 
+export const postFuelReceipt = createAsyncThunk(
+  "car-entry/postFuelReceipt",
+  async ({ data, fuelId }, thunkAPI) => {
+    try {
+      const response = await formsApi.post(
+        `/car-entry/fuel/${fuelId}/receipt`,
+        data,
+        {
+          headers: {
+            "Content-Type": "multipart/form-data",
+          },
+        }
+      );
+      return response.data;
+    } catch (error) {
+      return thunkAPI.rejectWithValue(error.response.data);
+    }
+  }
+);
+
+export const postChecklistPhoto = createAsyncThunk(
+  "car-entry/postChecklistPhoto",
+  async ({ data, entryId, stage, itemKey }, thunkAPI) => {
+    try {
+      const response = await formsApi.post(
+        `/car-entry/${entryId}/${stage}/checklist/${itemKey}/upload`,
+        data,
+        {
+          headers: {
+            "Content-Type": "multipart/form-data",
+          },
+        }
+      );
+      return response.data;
+    } catch (error) {
+      return thunkAPI.rejectWithValue(error.response.data);
+    }
+  }
+);
+
 export const checkIn = createAsyncThunk(
   "car-entry/start",
   async (checkInData, thunkAPI) => {
@@ -0,0 +1,40 @@
+import { createSlice, createAsyncThunk } from "@reduxjs/toolkit";
+import { formsApi } from "@/services/http";
+
+export const getChecklistTemplates = createAsyncThunk(
+  "checklist/getChecklistTemplates",
+  async (query, thunkAPI) => {
+    try {
+      const response = await formsApi.get("/checklist/" + query);
+      return response.data;
+    } catch (error) {
+      return thunkAPI.rejectWithValue(error.response.data);
+    }
+  }
+);
+
+const checklistSlice = createSlice({
+  name: "checklist",
+  initialState: {
+    templates: [],
+    status: "idle",
+    error: null,
+  },
+  reducers: {},
+  extraReducers: (builder) => {
+    builder
+      .addCase(getChecklistTemplates.pending, (state) => {
+        state.status = "loading";
+      })
+      .addCase(getChecklistTemplates.fulfilled, (state, action) => {
+        state.status = "succeeded";
+        state.templates = action.payload ?? [];
+      })
+      .addCase(getChecklistTemplates.rejected, (state, action) => {
+        state.status = "failed";
+        state.error = action.payload;
+      });
+  },
+});
+
+export default checklistSlice.reducer;
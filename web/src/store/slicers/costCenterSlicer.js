@@ -0,0 +1,40 @@
+import { createSlice, createAsyncThunk } from "@reduxjs/toolkit";
+import { formsApi } from "@/services/http";
+
+export const getCostCenters = createAsyncThunk(
+  "costCenter/getCostCenters",
+  async (query, thunkAPI) => {
+    try {
+      const response = await formsApi.get("/cost-center/" + query);
+      return response.data;
+    } catch (error) {
+      return thunkAPI.rejectWithValue(error.response.data);
+    }
+  }
+);
+
+const costCenterSlice = createSlice({
+  name: "costCenter",
+  initialState: {
+    costCenters: [],
+    status: "idle",
+    error: null,
+  },
+  reducers: {},
+  extraReducers: (builder) => {
+    builder
+      .addCase(getCostCenters.pending, (state) => {
+        state.status = "loading";
+      })
+      .addCase(getCostCenters.fulfilled, (state, action) => {
+        state.status = "succeeded";
+        state.costCenters = action.payload ?? [];
+      })
+      .addCase(getCostCenters.rejected, (state, action) => {
+        state.status = "failed";
+        state.error = action.payload;
+      });
+  },
+});
+
+export default costCenterSlice.reducer;